@@ -0,0 +1,204 @@
+// Package abc renders chords, notes and scales of the theory package using
+// the ABC music notation (http://abcnotation.com).
+package abc
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-audio/music/internal/notation"
+	"github.com/go-audio/music/theory"
+)
+
+// Options are the settings used to render tunes.
+type Options struct {
+	// TicksPerQuarter is the resolution of the timed events (96 by default).
+	TicksPerQuarter int
+	// TimeSignature is the numerator and denominator of the time signature
+	// (4/4 when not set).
+	TimeSignature [2]int
+	// Scale, if set, is used for the key signature and to spell the notes.
+	Scale *theory.Scale
+	// BPM is the tempo of the tune, not written if not set.
+	BPM float64
+}
+
+func (o Options) meter() notation.Meter {
+	return notation.Meter{TicksPerQuarter: o.TicksPerQuarter, TimeSignature: o.TimeSignature}
+}
+
+// keyModes are the ABC modes of the scales.
+var keyModes = map[theory.ScaleName]string{
+	theory.MajorScale:        "",
+	theory.NaturalMinorScale: "m",
+	theory.DorianScale:       "dor",
+	theory.PhrygianScale:     "phr",
+	theory.LydianScale:       "lyd",
+	theory.MixolydianScale:   "mix",
+	theory.LocrianScale:      "loc",
+}
+
+// Key returns the ABC key of a scale (Bb, F#m, Ddor). Scales without a
+// matching ABC mode use the major or minor key sharing their key signature.
+func Key(s *theory.Scale) string {
+	if s == nil {
+		return "C"
+	}
+	mode, ok := keyModes[s.Def.Name]
+	if !ok {
+		name := theory.MajorScale
+		if s.IsMinor() {
+			name = theory.NaturalMinorScale
+		}
+		s = theory.NewScaleFromKeySignature(s.KeySignature(), name)
+		mode = keyModes[name]
+	}
+	return s.NoteNames()[0] + mode
+}
+
+// keyAlterations returns the alteration of each letter (C to B) in the key
+// signature of the scale.
+func keyAlterations(s *theory.Scale) map[byte]int {
+	alterations := map[byte]int{}
+	fifths := 0
+	if s != nil {
+		fifths = s.KeySignature()
+	}
+	for i := 0; i < fifths; i++ {
+		alterations["FCGDAEB"[i]] = 1
+	}
+	for i := 0; i < -fifths; i++ {
+		alterations["BEADGCF"[i]] = -1
+	}
+	return alterations
+}
+
+// lengths are the note lengths that can be written, expressed in 64th notes,
+// longest first.
+var lengths = []int{64, 48, 32, 24, 16, 12, 8, 6, 4, 2, 1}
+
+// Lengths returns the ABC lengths (using a 1/8 unit note length) of the notes
+// to tie to express a duration in ticks. For instance 5 eighth notes give
+// 4 and "" (an eighth note). Durations shorter than a 64th note are dropped.
+func Lengths(ticks, ticksPerQuarter int) []string {
+	values := []string{}
+	for _, l := range notation.Split(ticks, ticksPerQuarter, lengths) {
+		values = append(values, fraction(l, 8))
+	}
+	return values
+}
+
+// fraction writes the ABC length of n/d unit notes.
+func fraction(n, d int) string {
+	g := gcd(n, d)
+	n, d = n/g, d/g
+	switch {
+	case d == 1 && n == 1:
+		return ""
+	case d == 1:
+		return fmt.Sprint(n)
+	case n == 1 && d == 2:
+		return "/"
+	case n == 1:
+		return fmt.Sprintf("/%d", d)
+	}
+	return fmt.Sprintf("%d/%d", n, d)
+}
+
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+// bar tracks the accidentals of the current bar.
+type bar struct {
+	key         map[byte]int
+	accidentals map[string]int
+}
+
+// pitch returns the ABC pitch of a spelled MIDI key, C being middle C
+// (midi.KeyInt("C", 3)), with an accidental if the key signature or a previous
+// accidental of the bar doesn't already alter it.
+func (b *bar) pitch(key int, name string) string {
+	alter := theory.Alteration(name)
+	letter := strings.ToUpper(name[:1])
+	// C is the middle C, midi.KeyInt("C", 3)
+	octave := theory.SpelledOctave(key, name) - 3
+	var p string
+	switch {
+	case octave <= 0:
+		p = letter + strings.Repeat(",", -octave)
+	default:
+		p = strings.ToLower(letter) + strings.Repeat("'", octave-1)
+	}
+	current, ok := b.accidentals[p]
+	if !ok {
+		current = b.key[letter[0]]
+	}
+	if current == alter {
+		return p
+	}
+	b.accidentals[p] = alter
+	switch {
+	case alter == 0:
+		return "=" + p
+	case alter > 0:
+		return strings.Repeat("^", alter) + p
+	}
+	return strings.Repeat("_", -alter) + p
+}
+
+// body renders the notes, cutting them (and tying them) wherever a note starts
+// or stops and at the bar lines. Annotations (chord symbols) are written
+// before the notes starting at their tick.
+func body(notes []notation.Note, annotations map[int]string, opts Options) string {
+	meter := opts.meter()
+	marks := []int{}
+	for tick := range annotations {
+		marks = append(marks, tick)
+	}
+
+	keyAlter := keyAlterations(opts.Scale)
+	b := &bar{key: keyAlter, accidentals: map[string]int{}}
+	var out strings.Builder
+	for _, slice := range meter.Slices(notes, marks...) {
+		if slice.Bar {
+			out.WriteString("| ")
+			b = &bar{key: keyAlter, accidentals: map[string]int{}}
+		}
+		if a, ok := annotations[slice.From]; ok && a != "" {
+			fmt.Fprintf(&out, "%q", a)
+		}
+		values := Lengths(slice.To-slice.From, meter.Ticks())
+		for j, v := range values {
+			last := j == len(values)-1
+			if len(slice.Notes) == 0 {
+				out.WriteString("z" + v + " ")
+				continue
+			}
+			pitches := []string{}
+			for _, n := range slice.Notes {
+				p := b.pitch(n.Key, n.Name)
+				if !last || n.End() > slice.To {
+					p += "-"
+				}
+				pitches = append(pitches, p)
+			}
+			if len(pitches) == 1 {
+				// the tie goes after the length
+				tie := strings.HasSuffix(pitches[0], "-")
+				out.WriteString(strings.TrimSuffix(pitches[0], "-") + v)
+				if tie {
+					out.WriteString("-")
+				}
+			} else {
+				out.WriteString("[" + strings.Join(pitches, "") + "]" + v)
+			}
+			out.WriteString(" ")
+		}
+	}
+	out.WriteString("|]")
+	return out.String()
+}
@@ -0,0 +1,96 @@
+package abc
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-audio/midi"
+	"github.com/go-audio/music/theory"
+)
+
+func TestKey(t *testing.T) {
+	tests := []struct {
+		scale *theory.Scale
+		want  string
+	}{
+		{nil, "C"},
+		{&theory.Scale{Root: 10, Def: theory.ScaleDefMap[theory.MajorScale]}, "Bb"},
+		{&theory.Scale{Root: 6, Def: theory.ScaleDefMap[theory.NaturalMinorScale]}, "F#m"},
+		{&theory.Scale{Root: 2, Def: theory.ScaleDefMap[theory.DorianScale]}, "Ddor"},
+		{&theory.Scale{Root: 2, Def: theory.ScaleDefMap[theory.HarmonicMinorScale]}, "Dm"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := Key(tt.scale); got != tt.want {
+				t.Errorf("Key(%v) = %s, want %s", tt.scale, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLengths(t *testing.T) {
+	tests := []struct {
+		ticks int
+		want  []string
+	}{
+		{48, []string{""}},
+		{96, []string{"2"}},
+		{144, []string{"3"}},
+		{24, []string{"/"}},
+		{240, []string{"4", ""}},
+		{72, []string{"3/2"}},
+	}
+	for _, tt := range tests {
+		if got := Lengths(tt.ticks, 96); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Lengths(%d, 96) = %q, want %q", tt.ticks, got, tt.want)
+		}
+	}
+}
+
+func TestNoteLine(t *testing.T) {
+	dMinor := &theory.Scale{Root: 2, Def: theory.ScaleDefMap[theory.HarmonicMinorScale]}
+	notes := []theory.NoteEvent{
+		{Key: midi.KeyInt("D", 3), Start: 0, Duration: 96},
+		{Key: midi.KeyInt("C#", 3), Start: 96, Duration: 48},
+		{Key: midi.KeyInt("C#", 3), Start: 144, Duration: 48},
+		{Key: midi.KeyInt("A#", 3), Start: 192, Duration: 96},
+		// crosses the bar line
+		{Key: midi.KeyInt("A", 4), Start: 288, Duration: 192},
+		{Key: midi.KeyInt("C#", 2), Start: 480, Duration: 96},
+	}
+	got := NoteLine(notes, Options{Scale: dMinor})
+	want := `D2 ^C C B2 a2- | a2 ^C,2 |]`
+	if got != want {
+		t.Errorf("NoteLine() = %s, want %s", got, want)
+	}
+}
+
+func TestChordLine(t *testing.T) {
+	chords := theory.Chords{
+		{Keys: []int{midi.KeyInt("C", 3), midi.KeyInt("D#", 3), midi.KeyInt("G", 3)}, Spelling: theory.FlatSpelling},
+		{Keys: []int{midi.KeyInt("B", 2), midi.KeyInt("D", 3), midi.KeyInt("G", 3)}},
+	}
+	spans := theory.NewChordSpans(chords, 576, 192)
+	got := ChordLine(spans, Options{})
+	want := `"Cm"[C-_E-G-]8 | [C_EG]4 "G/B"[B,DG]4 |]`
+	if got != want {
+		t.Errorf("ChordLine() = %s, want %s", got, want)
+	}
+}
+
+func TestTune_String(t *testing.T) {
+	g := &theory.Scale{Root: 7, Def: theory.ScaleDefMap[theory.MajorScale]}
+	tune := &Tune{
+		Title:   "G Major",
+		Options: Options{Scale: g, BPM: 90, TimeSignature: [2]int{3, 4}},
+		Melody:  nil,
+	}
+	tune.Chords = theory.NewChordSpans(theory.Chords{g.TriadChordForRoot(midi.KeyInt("G", 3))}, 288)
+	want := "X:1\nT:G Major\nM:3/4\nL:1/8\nQ:1/4=90\nK:G\n\"G\"[GBd]6 |]\n"
+	if got := tune.String(); got != want {
+		t.Errorf("Tune.String() =\n%s\nwant\n%s", got, want)
+	}
+	if got, want := ScaleLine(g, midi.KeyInt("G", 3), 3, Options{}), `G2 A2 B2 c2 |]`; got != want {
+		t.Errorf("ScaleLine() = %s, want %s", got, want)
+	}
+}
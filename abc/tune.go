@@ -0,0 +1,79 @@
+package abc
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-audio/music/internal/notation"
+	"github.com/go-audio/music/theory"
+)
+
+// ChordLine returns the ABC notes playing the chord spans using their
+// voicing, spelled relative to each chord root and annotated with the chord
+// symbols.
+func ChordLine(spans theory.ChordSpans, opts Options) string {
+	symbols := map[int]string{}
+	for _, span := range spans {
+		if span.Chord != nil {
+			symbols[span.Start] = span.Chord.Symbol()
+		}
+	}
+	return body(notation.ChordNotes(spans), symbols, opts)
+}
+
+// NoteLine returns the ABC notes playing the passed notes, spelled using the
+// scale of the options. Overlapping notes are written as tied chords.
+func NoteLine(notes []theory.NoteEvent, opts Options) string {
+	return body(notation.SpellNotes(notes, opts.Scale), nil, opts)
+}
+
+// ScaleLine returns the ABC notes playing a run of the scale as quarter notes,
+// from the start note and moving the passed number of steps.
+func ScaleLine(s *theory.Scale, start, steps int, opts Options) string {
+	opts.Scale = s
+	tpq := opts.meter().Ticks()
+	notes := []theory.NoteEvent{}
+	for i, k := range s.Run(start, steps) {
+		notes = append(notes, theory.NoteEvent{Key: k, Start: i * tpq, Duration: tpq})
+	}
+	return NoteLine(notes, opts)
+}
+
+// Tune is an ABC tune with a melody voice and/or a chords voice.
+type Tune struct {
+	// Number is the reference number of the tune (1 by default).
+	Number int
+	Title  string
+	Options
+	Melody []theory.NoteEvent
+	Chords theory.ChordSpans
+}
+
+// String returns the ABC source of the tune.
+func (t *Tune) String() string {
+	var b strings.Builder
+	number := t.Number
+	if number <= 0 {
+		number = 1
+	}
+	beats, beatType := t.meter().Signature()
+	fmt.Fprintf(&b, "X:%d\n", number)
+	if t.Title != "" {
+		fmt.Fprintf(&b, "T:%s\n", t.Title)
+	}
+	fmt.Fprintf(&b, "M:%d/%d\nL:1/8\n", beats, beatType)
+	if t.BPM > 0 {
+		fmt.Fprintf(&b, "Q:1/4=%.0f\n", t.BPM)
+	}
+	fmt.Fprintf(&b, "K:%s\n", Key(t.Scale))
+	switch {
+	case len(t.Melody) > 0 && len(t.Chords) > 0:
+		fmt.Fprintf(&b, "V:1 name=\"Melody\"\n%s\n", NoteLine(t.Melody, t.Options))
+		fmt.Fprintf(&b, "V:2 name=\"Chords\"\n%s\n", ChordLine(t.Chords, t.Options))
+	case len(t.Melody) > 0:
+		fmt.Fprintf(&b, "%s\n", NoteLine(t.Melody, t.Options))
+	case len(t.Chords) > 0:
+		fmt.Fprintf(&b, "%s\n", ChordLine(t.Chords, t.Options))
+	}
+	return b.String()
+}
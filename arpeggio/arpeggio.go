@@ -0,0 +1,197 @@
+// Package arpeggio turns chords into sequences of note events.
+package arpeggio
+
+import (
+	"math/rand"
+	"sort"
+
+	"github.com/go-audio/music/theory"
+)
+
+// Pattern defines the order in which the chord keys are played.
+type Pattern int
+
+const (
+	// Up plays the keys from the lowest to the highest.
+	Up Pattern = iota
+	// Down plays the keys from the highest to the lowest.
+	Down
+	// UpDown goes up then down without repeating the highest and lowest keys.
+	UpDown
+	// Random picks a random key at each step, see Arpeggiator.Seed.
+	Random
+	// AsPlayed plays the keys in the order of the chord keys.
+	AsPlayed
+	// Octaves plays each key, lowest first, followed by the same key an
+	// octave higher.
+	Octaves
+)
+
+var patternNames = map[Pattern]string{
+	Up:       "Up",
+	Down:     "Down",
+	UpDown:   "Up Down",
+	Random:   "Random",
+	AsPlayed: "As Played",
+	Octaves:  "Octaves",
+}
+
+func (p Pattern) String() string {
+	if name, ok := patternNames[p]; ok {
+		return name
+	}
+	return "Unknown"
+}
+
+// Defaults used for the zero values of the arpeggiator settings.
+const (
+	// DefaultRate is a sixteenth note at 96 ticks per quarter (see
+	// theory.NewTimeline).
+	DefaultRate     = 24
+	DefaultGate     = 0.5
+	DefaultVelocity = 100
+)
+
+// Arpeggiator plays the keys of chords one after the other. Zero values use
+// the defaults.
+type Arpeggiator struct {
+	Pattern Pattern
+	// Rate is the number of ticks between two notes.
+	Rate int
+	// Gate is the length of the notes relative to the rate, 1 plays legato.
+	Gate float64
+	// OctaveRange is the number of octaves the pattern spans, the chord keys
+	// are repeated an octave higher for each extra octave.
+	OctaveRange int
+	Velocity    int
+	Channel     int
+	// Seed is the seed of the random generator used by the Random pattern, a
+	// given seed always generates the same sequence.
+	Seed int64
+}
+
+// New returns an arpeggiator playing the pattern at the passed rate (in ticks)
+// using the default gate and velocity.
+func New(p Pattern, rate int) *Arpeggiator {
+	return &Arpeggiator{Pattern: p, Rate: rate}
+}
+
+// Notes returns the note events arpeggiating the chord from the start tick for
+// the duration (in ticks). Notes don't last past the end of the duration.
+func (a *Arpeggiator) Notes(c *theory.Chord, start, duration int) []theory.NoteEvent {
+	return a.notes(c, start, duration, rand.New(rand.NewSource(a.Seed)))
+}
+
+// Spans returns the note events arpeggiating each of the timed chords, the
+// pattern restarts on each chord change.
+func (a *Arpeggiator) Spans(spans theory.ChordSpans) []theory.NoteEvent {
+	rnd := rand.New(rand.NewSource(a.Seed))
+	notes := []theory.NoteEvent{}
+	for _, span := range spans {
+		notes = append(notes, a.notes(span.Chord, span.Start, span.Duration, rnd)...)
+	}
+	return notes
+}
+
+// Sequence returns one cycle of the pattern for the chord. The Random
+// pattern returns the keys it picks from.
+func (a *Arpeggiator) Sequence(c *theory.Chord) []int {
+	if c == nil || len(c.Keys) == 0 {
+		return nil
+	}
+	octaves := a.OctaveRange
+	if octaves < 1 {
+		octaves = 1
+	}
+	var keys []int
+	if a.Pattern == AsPlayed {
+		keys = spread(c.Keys, octaves)
+	} else {
+		keys = spread(sortedKeys(c.Keys), octaves)
+	}
+
+	switch a.Pattern {
+	case Down:
+		reverse(keys)
+	case UpDown:
+		for i := len(keys) - 2; i > 0; i-- {
+			keys = append(keys, keys[i])
+		}
+	case Octaves:
+		pairs := make([]int, 0, len(keys)*2)
+		for _, k := range keys {
+			pairs = append(pairs, k, k+12)
+		}
+		keys = pairs
+	}
+	return keys
+}
+
+func (a *Arpeggiator) notes(c *theory.Chord, start, duration int, rnd *rand.Rand) []theory.NoteEvent {
+	seq := a.Sequence(c)
+	if len(seq) == 0 || duration <= 0 {
+		return nil
+	}
+	rate := a.Rate
+	if rate <= 0 {
+		rate = DefaultRate
+	}
+	gate := a.Gate
+	if gate <= 0 {
+		gate = DefaultGate
+	}
+	velocity := a.Velocity
+	if velocity <= 0 {
+		velocity = DefaultVelocity
+	}
+	length := int(float64(rate)*gate + 0.5)
+	if length < 1 {
+		length = 1
+	}
+
+	end := start + duration
+	var notes []theory.NoteEvent
+	for step, tick := 0, start; tick < end; step, tick = step+1, tick+rate {
+		key := seq[step%len(seq)]
+		if a.Pattern == Random {
+			key = seq[rnd.Intn(len(seq))]
+		}
+		n := theory.NoteEvent{Key: key, Start: tick, Duration: length, Velocity: velocity, Channel: a.Channel}
+		if n.End() > end {
+			n.Duration = end - tick
+		}
+		notes = append(notes, n)
+	}
+	return notes
+}
+
+// spread repeats the keys an octave higher for each octave of the range.
+func spread(keys []int, octaves int) []int {
+	out := make([]int, 0, len(keys)*octaves)
+	for o := 0; o < octaves; o++ {
+		for _, k := range keys {
+			out = append(out, k+o*12)
+		}
+	}
+	return out
+}
+
+// sortedKeys returns the keys sorted by pitch without duplicates.
+func sortedKeys(keys []int) []int {
+	sorted := make([]int, 0, len(keys))
+	seen := map[int]bool{}
+	for _, k := range keys {
+		if !seen[k] {
+			seen[k] = true
+			sorted = append(sorted, k)
+		}
+	}
+	sort.Ints(sorted)
+	return sorted
+}
+
+func reverse(keys []int) {
+	for i, j := 0, len(keys)-1; i < j; i, j = i+1, j-1 {
+		keys[i], keys[j] = keys[j], keys[i]
+	}
+}
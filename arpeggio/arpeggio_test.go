@@ -0,0 +1,145 @@
+package arpeggio
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-audio/midi"
+	"github.com/go-audio/music/theory"
+)
+
+func keys(notes []theory.NoteEvent) []int {
+	out := make([]int, len(notes))
+	for i, n := range notes {
+		out[i] = n.Key
+	}
+	return out
+}
+
+func TestArpeggiator_Sequence(t *testing.T) {
+	c, e, g := midi.KeyInt("C", 3), midi.KeyInt("E", 3), midi.KeyInt("G", 3)
+	// first inversion, played E G C
+	chord := &theory.Chord{Keys: []int{e, g, c + 12}}
+	tests := []struct {
+		name    string
+		pattern Pattern
+		octaves int
+		want    []int
+	}{
+		{"up", Up, 1, []int{e, g, c + 12}},
+		{"down", Down, 1, []int{c + 12, g, e}},
+		{"up down", UpDown, 1, []int{e, g, c + 12, g}},
+		{"as played", AsPlayed, 1, []int{e, g, c + 12}},
+		{"octaves", Octaves, 1, []int{e, e + 12, g, g + 12, c + 12, c + 24}},
+		{"up 2 octaves", Up, 2, []int{e, g, c + 12, e + 12, g + 12, c + 24}},
+		{"up down 2 octaves", UpDown, 2, []int{e, g, c + 12, e + 12, g + 12, c + 24, g + 12, e + 12, c + 12, g}},
+		{"down 2 octaves", Down, 2, []int{c + 24, g + 12, e + 12, c + 12, g, e}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &Arpeggiator{Pattern: tt.pattern, OctaveRange: tt.octaves}
+			if got := a.Sequence(chord); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	// as played keeps the order of the keys
+	unsorted := &theory.Chord{Keys: []int{g, c, e}}
+	if got := (&Arpeggiator{Pattern: AsPlayed}).Sequence(unsorted); !reflect.DeepEqual(got, []int{g, c, e}) {
+		t.Errorf("expected the keys as played, got %v", got)
+	}
+	if got := (&Arpeggiator{Pattern: Up}).Sequence(unsorted); !reflect.DeepEqual(got, []int{c, e, g}) {
+		t.Errorf("expected sorted keys, got %v", got)
+	}
+}
+
+func TestArpeggiator_Notes(t *testing.T) {
+	chord := theory.NewChordFromAbbrev("Cmaj").Transpose(36)
+	a := New(Up, 96)
+	a.Gate = 0.75
+	a.Velocity = 90
+	a.Channel = 2
+	notes := a.Notes(chord, 384, 384)
+	want := []theory.NoteEvent{
+		{Key: 60, Start: 384, Duration: 72, Velocity: 90, Channel: 2},
+		{Key: 64, Start: 480, Duration: 72, Velocity: 90, Channel: 2},
+		{Key: 67, Start: 576, Duration: 72, Velocity: 90, Channel: 2},
+		{Key: 60, Start: 672, Duration: 72, Velocity: 90, Channel: 2},
+	}
+	if !reflect.DeepEqual(notes, want) {
+		t.Errorf("expected %v, got %v", want, notes)
+	}
+
+	// defaults: sixteenth notes, half gate, notes cut at the end
+	notes = (&Arpeggiator{Gate: 1}).Notes(chord, 0, 60)
+	want = []theory.NoteEvent{
+		{Key: 60, Start: 0, Duration: 24, Velocity: DefaultVelocity},
+		{Key: 64, Start: 24, Duration: 24, Velocity: DefaultVelocity},
+		{Key: 67, Start: 48, Duration: 12, Velocity: DefaultVelocity},
+	}
+	if !reflect.DeepEqual(notes, want) {
+		t.Errorf("expected %v, got %v", want, notes)
+	}
+	if notes := (&Arpeggiator{}).Notes(chord, 0, 24); notes[0].Duration != 12 {
+		t.Errorf("expected the default gate to halve the notes, got %d", notes[0].Duration)
+	}
+
+	if notes := (&Arpeggiator{}).Notes(nil, 0, 96); notes != nil {
+		t.Errorf("expected no notes for a nil chord, got %v", notes)
+	}
+}
+
+func TestArpeggiator_Random(t *testing.T) {
+	chord := theory.NewChordFromAbbrev("CMaj7").Transpose(36)
+	a := &Arpeggiator{Pattern: Random, Seed: 42, OctaveRange: 2}
+	first := keys(a.Notes(chord, 0, 384))
+	if len(first) != 16 {
+		t.Fatalf("expected 16 notes, got %d", len(first))
+	}
+	if again := keys(a.Notes(chord, 0, 384)); !reflect.DeepEqual(first, again) {
+		t.Errorf("expected the same seed to give the same notes, got %v and %v", first, again)
+	}
+	pool := a.Sequence(chord)
+	for _, k := range first {
+		var found bool
+		for _, p := range pool {
+			found = found || p == k
+		}
+		if !found {
+			t.Errorf("%d isn't a chord key", k)
+		}
+	}
+	a.Seed = 7
+	if other := keys(a.Notes(chord, 0, 384)); reflect.DeepEqual(first, other) {
+		t.Errorf("expected a different seed to give different notes, got %v", other)
+	}
+}
+
+func TestArpeggiator_Spans(t *testing.T) {
+	spans := theory.NewChordSpans(theory.Chords{
+		theory.NewChordFromAbbrev("Amin").Transpose(36),
+		theory.NewChordFromAbbrev("Fmaj").Transpose(36),
+	}, 192)
+	notes := New(UpDown, 48).Spans(spans)
+	want := []int{
+		69, 72, 76, 72,
+		65, 69, 72, 69,
+	}
+	if got := keys(notes); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	// the pattern restarts on the chord change
+	if notes[4].Start != 192 {
+		t.Errorf("expected the second chord to start at 192, got %d", notes[4].Start)
+	}
+}
+
+func TestPattern_String(t *testing.T) {
+	if got := UpDown.String(); got != "Up Down" {
+		t.Errorf("expected Up Down, got %s", got)
+	}
+	if got := Pattern(42).String(); got != "Unknown" {
+		t.Errorf("expected Unknown, got %s", got)
+	}
+}
@@ -0,0 +1,302 @@
+// Package bassline generates bass lines following chord progressions.
+package bassline
+
+import (
+	"github.com/go-audio/music/instrument"
+	"github.com/go-audio/music/theory"
+)
+
+// Style defines how the bass line is built.
+type Style int
+
+const (
+	// RootOnly plays the bass note of the chord (its root unless the chord is
+	// inverted) on every beat.
+	RootOnly Style = iota
+	// RootFifth alternates the bass note and the fifth of the chord.
+	RootFifth
+	// Walking plays a note per beat, starting each chord on its bass note and
+	// walking through chord and scale tones to a chromatic approach tone a
+	// half step from the next bass note.
+	Walking
+	// Pedal holds the tonic of the scale (or the bass note of the first chord
+	// without a scale) under every chord.
+	Pedal
+)
+
+var styleNames = map[Style]string{
+	RootOnly:  "Root Only",
+	RootFifth: "Root Fifth",
+	Walking:   "Walking",
+	Pedal:     "Pedal",
+}
+
+func (s Style) String() string {
+	if name, ok := styleNames[s]; ok {
+		return name
+	}
+	return "Unknown"
+}
+
+// Defaults used for the zero values of the generator settings.
+const (
+	// DefaultTicksPerBeat is a quarter note using the default resolution
+	// of theory.NewTimeline.
+	DefaultTicksPerBeat = 96
+	DefaultVelocity     = 100
+)
+
+// Generator builds bass lines. Zero values use the defaults.
+type Generator struct {
+	Style Style
+	// Scale is the key of the progression, the passing tones of walking bass
+	// lines are picked from it. Without a scale only chord tones are used.
+	Scale *theory.Scale
+	// Low and High are the lowest and highest keys of the line, the two
+	// lowest octaves of instrument.Bass by default.
+	Low, High int
+	// TicksPerBeat is the duration of a beat in ticks.
+	TicksPerBeat int
+	Velocity     int
+	Channel      int
+}
+
+// New returns a generator using the style, in the key of the scale (which can
+// be nil).
+func New(style Style, s *theory.Scale) *Generator {
+	return &Generator{Style: style, Scale: s}
+}
+
+// Chords returns the bass line of the chords, each chord lasting the passed
+// number of beats.
+func (g *Generator) Chords(chords theory.Chords, beats int) []theory.NoteEvent {
+	return g.Notes(theory.NewChordSpans(chords, beats*g.beat()))
+}
+
+// Notes returns the bass line following the timed chords.
+func (g *Generator) Notes(spans theory.ChordSpans) []theory.NoteEvent {
+	notes := []theory.NoteEvent{}
+	if len(spans) == 0 {
+		return notes
+	}
+	low, high := g.bounds()
+	beat := g.beat()
+
+	pedal := -1
+	if g.Style == Pedal {
+		pedal = bassPitchClass(spans[0].Chord)
+		if g.Scale != nil {
+			pedal = mod12(g.Scale.Root)
+		}
+		pedal = nearest(pedal, low, low, high)
+	}
+
+	prev := -1
+	for i, span := range spans {
+		if span.Chord == nil || len(span.Chord.Keys) == 0 || span.Duration <= 0 {
+			continue
+		}
+		if g.Style == Pedal {
+			notes = append(notes, g.note(pedal, span.Start, span.Duration))
+			continue
+		}
+		bass := bassPitchClass(span.Chord)
+		var root int
+		if prev < 0 {
+			root = nearest(bass, low, low, high)
+		} else {
+			root = nearest(bass, prev, low, high)
+		}
+
+		// a note is played on each beat started by the chord
+		beats := (span.Duration + beat - 1) / beat
+		keys := make([]int, beats)
+		switch g.Style {
+		case RootFifth:
+			fifth := nearest(chordFifth(span.Chord), root+7, low, high)
+			for b := range keys {
+				keys[b] = root
+				if b%2 == 1 {
+					keys[b] = fifth
+				}
+			}
+		case Walking:
+			// the line of the last chord walks back to the first one
+			next := spans[0].Chord
+			if i+1 < len(spans) && spans[i+1].Chord != nil && len(spans[i+1].Chord.Keys) > 0 {
+				next = spans[i+1].Chord
+			}
+			keys = g.walk(span.Chord, root, bassPitchClass(next), beats)
+		default:
+			for b := range keys {
+				keys[b] = root
+			}
+		}
+
+		for b, k := range keys {
+			start := span.Start + b*beat
+			duration := beat
+			if b == len(keys)-1 {
+				duration = span.End() - start
+			}
+			notes = append(notes, g.note(k, start, duration))
+		}
+		// walking lines lead to the next bass note, others stay around the
+		// first root
+		prev = root
+		if g.Style == Walking {
+			prev = keys[len(keys)-1]
+		}
+	}
+	return notes
+}
+
+// walk returns the keys of a walking line starting on the root, climbing
+// through chord and scale tones towards the fifth (or down to the fourth below
+// when the fifth is out of range) and ending on a chromatic approach tone a
+// half step from the next bass note.
+func (g *Generator) walk(c *theory.Chord, root, next, beats int) []int {
+	keys := []int{root}
+	if beats == 1 {
+		return keys
+	}
+	low, high := g.bounds()
+	peak := root + 7
+	if peak >= high {
+		peak = root - 5
+	}
+
+	chordTones := map[int]bool{}
+	for _, k := range c.Keys {
+		chordTones[mod12(k)] = true
+	}
+	prev := root
+	for b := 1; b < beats-1; b++ {
+		aim := root + (peak-root)*b/(beats-2)
+		best, bestCost := -1, 0
+		for k := low; k <= high; k++ {
+			pc := mod12(k)
+			inScale := g.Scale != nil && g.Scale.IndexOfNote(pc) >= 0
+			if k == prev || !(chordTones[pc] || inScale) {
+				continue
+			}
+			cost := 2 * abs(k-aim)
+			if !chordTones[pc] {
+				cost++
+			}
+			// keep moving towards the peak
+			if (k-prev)*(peak-root) < 0 {
+				cost += 3
+			}
+			if best < 0 || cost < bestCost {
+				best, bestCost = k, cost
+			}
+		}
+		if best < 0 {
+			best = prev
+		}
+		keys = append(keys, best)
+		prev = best
+	}
+
+	// approach the next bass note from the side of the last note
+	target := nearest(next, prev, low, high)
+	approach := -1
+	for _, k := range []int{target - 1, target + 1} {
+		if k < low || k > high || k == prev {
+			continue
+		}
+		if approach < 0 || abs(k-prev) < abs(approach-prev) {
+			approach = k
+		}
+	}
+	if approach < 0 {
+		approach = target - 1
+	}
+	return append(keys, approach)
+}
+
+func (g *Generator) note(key, start, duration int) theory.NoteEvent {
+	velocity := g.Velocity
+	if velocity <= 0 {
+		velocity = DefaultVelocity
+	}
+	return theory.NoteEvent{Key: key, Start: start, Duration: duration, Velocity: velocity, Channel: g.Channel}
+}
+
+func (g *Generator) beat() int {
+	if g.TicksPerBeat <= 0 {
+		return DefaultTicksPerBeat
+	}
+	return g.TicksPerBeat
+}
+
+func (g *Generator) bounds() (low, high int) {
+	low, high = g.Low, g.High
+	if low == 0 && high == 0 {
+		low = instrument.Bass.Low
+		high = low + 24
+	}
+	// the range needs at least an octave to fit every pitch class
+	if high-low < 11 {
+		high = low + 11
+	}
+	return low, high
+}
+
+// bassPitchClass returns the pitch class of the lowest key of the chord.
+func bassPitchClass(c *theory.Chord) int {
+	lowest := c.Keys[0]
+	for _, k := range c.Keys {
+		if k < lowest {
+			lowest = k
+		}
+	}
+	return mod12(lowest)
+}
+
+// chordFifth returns the pitch class of the fifth of the chord (diminished or
+// augmented if that's what the chord has), a perfect fifth above the root if
+// the chord has no fifth.
+func chordFifth(c *theory.Chord) int {
+	root := c.Copy().Def().RootInt()
+	if root < 0 {
+		root = bassPitchClass(c)
+	}
+	tones := map[int]bool{}
+	for _, k := range c.Keys {
+		tones[mod12(k-root)] = true
+	}
+	for _, hs := range []int{7, 6, 8} {
+		if tones[hs] {
+			return mod12(root + hs)
+		}
+	}
+	return mod12(root + 7)
+}
+
+// nearest returns the key of the pitch class closest to the passed key, within
+// the range.
+func nearest(pc, key, low, high int) int {
+	best := -1
+	for k := low; k <= high; k++ {
+		if mod12(k) != pc {
+			continue
+		}
+		if best < 0 || abs(k-key) < abs(best-key) {
+			best = k
+		}
+	}
+	return best
+}
+
+func mod12(n int) int {
+	return ((n % 12) + 12) % 12
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
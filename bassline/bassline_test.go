@@ -0,0 +1,159 @@
+package bassline
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-audio/midi"
+	"github.com/go-audio/music/theory"
+)
+
+func progression(names ...string) theory.Chords {
+	chords := make(theory.Chords, len(names))
+	for i, n := range names {
+		chords[i] = theory.NewChordFromAbbrev(n).Transpose(36)
+	}
+	return chords
+}
+
+func keys(notes []theory.NoteEvent) []int {
+	out := make([]int, len(notes))
+	for i, n := range notes {
+		out[i] = n.Key
+	}
+	return out
+}
+
+func TestGenerator_Chords(t *testing.T) {
+	cMajor := &theory.Scale{Root: midi.KeyInt("C", 3) % 12, Def: theory.ScaleDefMap[theory.MajorScale]}
+	c, e, g, a := midi.KeyInt("C", 1), midi.KeyInt("E", 1), midi.KeyInt("G", 1), midi.KeyInt("A", 0)
+	tests := []struct {
+		name   string
+		style  Style
+		scale  *theory.Scale
+		chords theory.Chords
+		beats  int
+		want   []int
+	}{
+		{name: "root only",
+			style: RootOnly, chords: progression("Cmaj", "Amin"), beats: 2,
+			want: []int{c, c, a, a},
+		},
+		{name: "root fifth",
+			style: RootFifth, chords: progression("Cmaj", "Amin"), beats: 4,
+			want: []int{c, g, c, g, a, e, a, e},
+		},
+		{name: "diminished fifth",
+			style: RootFifth, chords: progression("Bmb5"), beats: 2,
+			want: []int{midi.KeyInt("B", 0), midi.KeyInt("F", 1)},
+		},
+		{name: "walking",
+			style: Walking, chords: progression("Cmaj", "Amin"), beats: 4,
+			// C E G G# into A, then back to C through B
+			want: []int{c, e, g, g + 1, a + 12, e, c, c - 1},
+		},
+		{name: "walking two beats",
+			style: Walking, chords: progression("Cmaj", "Fmaj"), beats: 2,
+			want: []int{c, e, midi.KeyInt("F", 1), c + 1},
+		},
+		{name: "pedal on the tonic",
+			style: Pedal, scale: cMajor, chords: progression("Fmaj", "Gmaj", "Cmaj"), beats: 4,
+			want: []int{c, c, c},
+		},
+		{name: "pedal without scale",
+			style: Pedal, chords: progression("Dmin", "Gmaj"), beats: 4,
+			want: []int{midi.KeyInt("D", 1), midi.KeyInt("D", 1)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := keys(New(tt.style, tt.scale).Chords(tt.chords, tt.beats))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestGenerator_Walking(t *testing.T) {
+	s := &theory.Scale{Root: midi.KeyInt("C", 3) % 12, Def: theory.ScaleDefMap[theory.MajorScale]}
+	chords := progression("Cmaj", "Amin", "Dmin", "Gmaj", "Fmaj", "Emin")
+	gen := New(Walking, s)
+	gen.Low, gen.High = midi.KeyInt("E", 0), midi.KeyInt("E", 2)
+	notes := gen.Chords(chords, 4)
+	if len(notes) != 24 {
+		t.Fatalf("expected 24 notes, got %d", len(notes))
+	}
+	for i, n := range notes {
+		if n.Key < gen.Low || n.Key > gen.High {
+			t.Errorf("note %d (%d) is out of range", i, n.Key)
+		}
+		if n.Start != i*96 || n.Duration != 96 {
+			t.Errorf("note %d: expected %d+96, got %d+%d", i, i*96, n.Start, n.Duration)
+		}
+	}
+	for i, c := range chords {
+		bar := notes[i*4 : i*4+4]
+		if bar[0].Key%12 != c.Keys[0]%12 {
+			t.Errorf("chord %d: expected the line to start on the root, got %d", i, bar[0].Key)
+		}
+		for _, n := range bar[1:3] {
+			var chordTone bool
+			for _, k := range c.Keys {
+				chordTone = chordTone || k%12 == n.Key%12
+			}
+			if !chordTone && s.IndexOfNote(n.Key) < 0 {
+				t.Errorf("chord %d: %d is neither a chord tone nor in scale", i, n.Key)
+			}
+		}
+		// the last note is a half step from the next root, the last chord
+		// leads back to the first one
+		next := notes[0]
+		if i+1 < len(chords) {
+			next = notes[(i+1)*4]
+		}
+		if d := (bar[3].Key - next.Key + 12) % 12; d != 1 && d != 11 {
+			t.Errorf("chord %d: expected %d to approach %d", i, bar[3].Key, next.Key)
+		}
+	}
+}
+
+func TestGenerator_Notes(t *testing.T) {
+	// C/E plays its bass note
+	inverted := &theory.Chord{Keys: []int{midi.KeyInt("E", 2), midi.KeyInt("G", 2), midi.KeyInt("C", 3)}}
+	spans := theory.ChordSpans{
+		{Chord: inverted, Start: 0, Duration: 144},
+		{Chord: theory.NewChordFromAbbrev("Gmaj"), Start: 192, Duration: 96},
+	}
+	gen := &Generator{Style: RootOnly, Velocity: 80, Channel: 1}
+	want := []theory.NoteEvent{
+		{Key: midi.KeyInt("E", 0), Start: 0, Duration: 96, Velocity: 80, Channel: 1},
+		// the last note lasts until the end of the chord
+		{Key: midi.KeyInt("E", 0), Start: 96, Duration: 48, Velocity: 80, Channel: 1},
+		{Key: midi.KeyInt("G", 0), Start: 192, Duration: 96, Velocity: 80, Channel: 1},
+	}
+	if got := gen.Notes(spans); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	// the line stays in range
+	gen = &Generator{Style: RootFifth, Low: midi.KeyInt("C", 2), High: midi.KeyInt("B", 2)}
+	for _, k := range keys(gen.Chords(progression("Gmaj", "Amin"), 2)) {
+		if k < gen.Low || k > gen.High {
+			t.Errorf("%d is out of range", k)
+		}
+	}
+
+	if got := New(Walking, nil).Notes(nil); len(got) != 0 {
+		t.Errorf("expected no notes, got %v", got)
+	}
+}
+
+func TestStyle_String(t *testing.T) {
+	if got := RootFifth.String(); got != "Root Fifth" {
+		t.Errorf("expected Root Fifth, got %s", got)
+	}
+	if got := Style(42).String(); got != "Unknown" {
+		t.Errorf("expected Unknown, got %s", got)
+	}
+}
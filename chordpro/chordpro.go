@@ -0,0 +1,177 @@
+// Package chordpro parses and renders lead sheets written using the ChordPro
+// format (https://www.chordpro.org) or as chords over lyrics plain text.
+package chordpro
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/go-audio/music/theory"
+)
+
+// Song is a lead sheet: metadata and sections of lyrics with chords.
+type Song struct {
+	Title    string
+	Subtitle string
+	Artist   string
+	// Key is the key of the song as written ({key: Em}), empty if unknown.
+	Key string
+	// Meta contains the other metadata directives (capo, tempo...).
+	Meta     map[string]string
+	Sections []*Section
+}
+
+// Section is a part of the song such as a verse or the chorus.
+type Section struct {
+	// Kind is the kind of section (verse, chorus, bridge, tab, grid...), empty
+	// for lines outside of a section.
+	Kind string
+	// Label is the label of the section as written ("Verse 1").
+	Label string
+	Lines []Line
+}
+
+// Line is a line of lyrics with the chords played over them.
+type Line struct {
+	Lyrics string
+	Chords []ChordAt
+	// Comment is set for comment lines ({comment: ...}).
+	Comment string
+}
+
+// ChordAt is a chord played at a position in the lyrics.
+type ChordAt struct {
+	// Text is the chord as written.
+	Text string
+	// Symbol is the parsed chord symbol, nil if Text isn't a chord symbol
+	// (N.C., %...).
+	Symbol *theory.ChordSymbol
+	// Position is the index of the character of the lyrics the chord is
+	// played on (in runes).
+	Position int
+}
+
+// newChordAt returns a chord at the passed position, parsing its symbol.
+func newChordAt(text string, pos int) ChordAt {
+	c := ChordAt{Text: text, Position: pos}
+	if cs, err := theory.ParseChordSymbol(text); err == nil {
+		c.Symbol = cs
+	}
+	return c
+}
+
+// Symbols returns the chord symbols of the song, in order.
+func (s *Song) Symbols() []*theory.ChordSymbol {
+	symbols := []*theory.ChordSymbol{}
+	for _, section := range s.Sections {
+		for _, line := range section.Lines {
+			for _, c := range line.Chords {
+				if c.Symbol != nil {
+					symbols = append(symbols, c.Symbol)
+				}
+			}
+		}
+	}
+	return symbols
+}
+
+// Chords returns the chords of the song, in order.
+func (s *Song) Chords() theory.Chords {
+	chords := theory.Chords{}
+	for _, cs := range s.Symbols() {
+		chords = append(chords, cs.Chord())
+	}
+	return chords
+}
+
+// section returns the last section of the song, adding an untitled one if
+// needed.
+func (s *Song) section() *Section {
+	if len(s.Sections) == 0 {
+		s.Sections = append(s.Sections, &Section{})
+	}
+	return s.Sections[len(s.Sections)-1]
+}
+
+// Transpose returns a copy of the song transposed by the passed number of half
+// steps. The chords are spelled using the new key (or the key of the first
+// chord if the song key isn't set).
+func (s *Song) Transpose(halfSteps int) *Song {
+	tonic := s.tonic()
+	if tonic == nil {
+		return s.TransposeInterval(theory.Interval{HalfSteps: halfSteps})
+	}
+	def := theory.ScaleDefMap[theory.MajorScale]
+	if suffix := tonic.Def.Suffix(); strings.HasPrefix(suffix, "m") && !strings.HasPrefix(suffix, "maj") {
+		def = theory.ScaleDefMap[theory.NaturalMinorScale]
+	}
+	fromC, _ := theory.NoteNameInterval("C", tonic.Root)
+	target := &theory.Scale{Root: ((fromC.HalfSteps+halfSteps)%12 + 12) % 12, Def: def}
+	iv, _ := theory.NoteNameInterval(tonic.Root, target.NoteNames()[0])
+	return s.TransposeInterval(iv)
+}
+
+// TransposeToKey returns a copy of the song transposed to the passed key
+// (such as "Bb" or "F#m"). Songs without key are transposed using their first
+// chord. ErrInvalidChordSymbol is returned if the key can't be parsed.
+func (s *Song) TransposeToKey(key string) (*Song, error) {
+	to, err := theory.ParseChordSymbol(key)
+	if err != nil {
+		return nil, err
+	}
+	tonic := s.tonic()
+	if tonic == nil {
+		t := s.TransposeInterval(theory.PerfectUnison)
+		t.Key = key
+		return t, nil
+	}
+	iv, _ := theory.NoteNameInterval(tonic.Root, to.Root)
+	t := s.TransposeInterval(iv)
+	t.Key = key
+	return t, nil
+}
+
+// tonic returns the symbol of the song key, or of the first chord, nil if
+// neither is known.
+func (s *Song) tonic() *theory.ChordSymbol {
+	if cs, err := theory.ParseChordSymbol(s.Key); err == nil {
+		return cs
+	}
+	if symbols := s.Symbols(); len(symbols) > 0 {
+		return symbols[0]
+	}
+	return nil
+}
+
+// TransposeInterval returns a copy of the song with all its chords (and key)
+// transposed by the interval. Chords that couldn't be parsed are kept as is.
+func (s *Song) TransposeInterval(iv theory.Interval) *Song {
+	t := &Song{Title: s.Title, Subtitle: s.Subtitle, Artist: s.Artist, Key: s.Key, Meta: map[string]string{}}
+	for k, v := range s.Meta {
+		t.Meta[k] = v
+	}
+	if cs, err := theory.ParseChordSymbol(s.Key); err == nil {
+		t.Key = cs.TransposeInterval(iv).String()
+	}
+	for _, section := range s.Sections {
+		ts := &Section{Kind: section.Kind, Label: section.Label}
+		for _, line := range section.Lines {
+			tl := Line{Lyrics: line.Lyrics, Comment: line.Comment}
+			for _, c := range line.Chords {
+				if c.Symbol != nil {
+					c.Symbol = c.Symbol.TransposeInterval(iv)
+					c.Text = c.Symbol.String()
+				}
+				tl.Chords = append(tl.Chords, c)
+			}
+			ts.Lines = append(ts.Lines, tl)
+		}
+		t.Sections = append(t.Sections, ts)
+	}
+	return t
+}
+
+// runeLen returns the number of runes of the string.
+func runeLen(s string) int {
+	return utf8.RuneCountInString(s)
+}
@@ -0,0 +1,256 @@
+package chordpro
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const amazingGrace = `{title: Amazing Grace}
+{artist: John Newton}
+{key: G}
+{capo: 2}
+# a comment for the maintainers
+
+{start_of_verse: Verse 1}
+A[G]mazing [G7]grace how [C]sweet the [G]sound
+That [G]saved a [Em]wretch like [D]me
+{end_of_verse}
+
+{soc}
+{comment: slowly}
+I [G]once was [D/F#]lost [N.C.]
+{eoc}
+`
+
+func chordTexts(s *Song) []string {
+	texts := []string{}
+	for _, sec := range s.Sections {
+		for _, l := range sec.Lines {
+			for _, c := range l.Chords {
+				texts = append(texts, c.Text)
+			}
+		}
+	}
+	return texts
+}
+
+func TestParse(t *testing.T) {
+	song, err := Parse(strings.NewReader(amazingGrace))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if song.Title != "Amazing Grace" || song.Artist != "John Newton" || song.Key != "G" {
+		t.Errorf("unexpected metadata %q %q %q", song.Title, song.Artist, song.Key)
+	}
+	if song.Meta["capo"] != "2" {
+		t.Errorf("expected the capo to be set, got %v", song.Meta)
+	}
+	if len(song.Sections) != 2 {
+		t.Fatalf("expected 2 sections, got %d", len(song.Sections))
+	}
+	verse, chorus := song.Sections[0], song.Sections[1]
+	if verse.Kind != "verse" || verse.Label != "Verse 1" || chorus.Kind != "chorus" {
+		t.Errorf("unexpected sections %+v %+v", verse, chorus)
+	}
+	first := verse.Lines[0]
+	if first.Lyrics != "Amazing grace how sweet the sound" {
+		t.Errorf("unexpected lyrics %q", first.Lyrics)
+	}
+	positions := []int{}
+	for _, c := range first.Chords {
+		positions = append(positions, c.Position)
+	}
+	if want := []int{1, 8, 18, 28}; !reflect.DeepEqual(positions, want) {
+		t.Errorf("expected chords at %v, got %v", want, positions)
+	}
+	if chorus.Lines[0].Comment != "slowly" {
+		t.Errorf("expected a comment, got %+v", chorus.Lines[0])
+	}
+	names := []string{}
+	for _, c := range song.Chords() {
+		names = append(names, c.Def().String())
+	}
+	want := []string{"G Major", "G Seventh", "C Major", "G Major", "G Major", "E Minor", "D Major", "G Major", "D Major"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("expected chords %v, got %v", want, names)
+	}
+	// N.C. isn't a chord but is kept
+	if texts := chordTexts(song); texts[len(texts)-1] != "N.C." {
+		t.Errorf("expected N.C. to be kept, got %v", texts)
+	}
+}
+
+func TestSong_WriteChordPro(t *testing.T) {
+	song, err := Parse(strings.NewReader(amazingGrace))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{title: Amazing Grace}
+{artist: John Newton}
+{key: G}
+{capo: 2}
+
+{start_of_verse: Verse 1}
+A[G]mazing [G7]grace how [C]sweet the [G]sound
+That [G]saved a [Em]wretch like [D]me
+{end_of_verse}
+
+{start_of_chorus}
+{comment: slowly}
+I [G]once was [D/F#]lost [N.C.]
+{end_of_chorus}
+`
+	if got := song.String(); got != want {
+		t.Errorf("expected:\n%s\ngot:\n%s", want, got)
+	}
+	again, err := Parse(strings.NewReader(song.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(again, song) {
+		t.Errorf("expected the song to round trip")
+	}
+}
+
+func TestSong_Transpose(t *testing.T) {
+	song, err := Parse(strings.NewReader(amazingGrace))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		song func() *Song
+		key  string
+		want []string
+	}{
+		{"down a whole step", func() *Song { return song.Transpose(-2) }, "F",
+			[]string{"F", "F7", "Bb", "F", "F", "Dm", "C", "F", "C/E", "N.C."}},
+		{"up a half step", func() *Song { return song.Transpose(1) }, "Ab",
+			[]string{"Ab", "Ab7", "Db", "Ab", "Ab", "Fm", "Eb", "Ab", "Eb/G", "N.C."}},
+		{"to E", func() *Song {
+			s, err := song.TransposeToKey("E")
+			if err != nil {
+				t.Fatal(err)
+			}
+			return s
+		}, "E",
+			[]string{"E", "E7", "A", "E", "E", "C#m", "B", "E", "B/D#", "N.C."}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.song()
+			if got.Key != tt.key {
+				t.Errorf("expected the key to be %s, got %s", tt.key, got.Key)
+			}
+			if texts := chordTexts(got); !reflect.DeepEqual(texts, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, texts)
+			}
+		})
+	}
+	// the original song isn't changed
+	if texts := chordTexts(song); texts[0] != "G" {
+		t.Errorf("expected the original song to be unchanged, got %v", texts)
+	}
+	if _, err := song.TransposeToKey("X"); err == nil {
+		t.Errorf("expected an error for an invalid key")
+	}
+}
+
+const leadSheet = `Amazing Grace
+
+Verse:
+ G           G7     C         G
+Amazing grace how sweet the sound
+    G       Em         D
+That saved a wretch like me
+
+Chorus:
+G   D/F#   N.C.
+`
+
+func TestParseText(t *testing.T) {
+	song, err := ParseText(strings.NewReader(leadSheet))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if song.Title != "Amazing Grace" {
+		t.Errorf("expected the title to be set, got %q", song.Title)
+	}
+	if len(song.Sections) != 2 || song.Sections[0].Kind != "verse" || song.Sections[1].Kind != "chorus" {
+		t.Fatalf("unexpected sections %+v", song.Sections)
+	}
+	first := song.Sections[0].Lines[0]
+	if first.Lyrics != "Amazing grace how sweet the sound" {
+		t.Errorf("unexpected lyrics %q", first.Lyrics)
+	}
+	positions := []int{}
+	for _, c := range first.Chords {
+		positions = append(positions, c.Position)
+	}
+	if want := []int{1, 13, 20, 30}; !reflect.DeepEqual(positions, want) {
+		t.Errorf("expected chords at %v, got %v", want, positions)
+	}
+	if want := []string{"G", "G7", "C", "G", "G", "Em", "D", "G", "D/F#", "N.C."}; !reflect.DeepEqual(chordTexts(song), want) {
+		t.Errorf("expected %v, got %v", want, chordTexts(song))
+	}
+
+	// converting to ChordPro places the chords in the lyrics
+	if got, want := first.chordPro(), "A[G]mazing grace[G7] how sw[C]eet the so[G]und"; got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+	buf := bytes.NewBuffer(nil)
+	if err := song.WriteText(buf); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != leadSheet {
+		t.Errorf("expected the lead sheet to round trip, got:\n%s", got)
+	}
+}
+
+func TestParseText_roundTrip(t *testing.T) {
+	text := "Intro:\nG    C    G\n\nVerse 1:\nG        C\nAmazing grace\n"
+	song, err := ParseText(strings.NewReader(text))
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := bytes.NewBuffer(nil)
+	if err := song.WriteChordPro(buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "{start_of_intro: Intro}") {
+		t.Errorf("expected the intro to be written as a section, got\n%s", buf)
+	}
+	parsed, err := Parse(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kinds := []string{}
+	for _, sec := range parsed.Sections {
+		kinds = append(kinds, sec.Kind+" "+sec.Label)
+	}
+	if want := []string{"intro Intro", "verse Verse 1"}; !reflect.DeepEqual(kinds, want) {
+		t.Errorf("expected the sections %q, got %q", want, kinds)
+	}
+	if want := []string{"G", "C", "G", "G", "C"}; !reflect.DeepEqual(chordTexts(parsed), want) {
+		t.Errorf("expected %v, got %v", want, chordTexts(parsed))
+	}
+}
+
+func TestParseText_barsAndHeaders(t *testing.T) {
+	song, err := ParseText(strings.NewReader("[Bridge]\n| Am | F  G |\nThis isn't a chord line: Am\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(song.Sections) != 1 || song.Sections[0].Label != "Bridge" {
+		t.Fatalf("unexpected sections %+v", song.Sections)
+	}
+	lines := song.Sections[0].Lines
+	if want := []string{"Am", "F", "G"}; !reflect.DeepEqual(chordTexts(song), want) {
+		t.Errorf("expected %v, got %v", want, chordTexts(song))
+	}
+	if len(lines) != 1 || lines[0].Lyrics != "This isn't a chord line: Am" {
+		t.Errorf("expected the lyrics to follow the chords, got %+v", lines)
+	}
+}
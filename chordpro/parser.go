@@ -0,0 +1,243 @@
+package chordpro
+
+import (
+	"bufio"
+	"io"
+	"strings"
+
+	"github.com/go-audio/music/theory"
+)
+
+// sectionDirectives map the short forms of the directives starting sections
+// to the kind of section. Other sections are started by start_of_<kind>
+// directives and ended by end_of_<kind> ones.
+var sectionDirectives = map[string]string{
+	"soc": "chorus",
+	"sov": "verse",
+	"sob": "bridge",
+	"sot": "tab",
+	"sog": "grid",
+}
+
+var endDirectives = map[string]bool{
+	"eoc": true,
+	"eov": true,
+	"eob": true,
+	"eot": true,
+	"eog": true,
+}
+
+// Parse reads a ChordPro song. Chords are written between brackets in the
+// lyrics ([G]Amazing [G7]grace), directives between braces ({title: ...}).
+func Parse(r io.Reader) (*Song, error) {
+	song := &Song{Meta: map[string]string{}}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "#"):
+			// comment for the file maintainers
+		case strings.HasPrefix(trimmed, "{") && strings.HasSuffix(trimmed, "}"):
+			song.directive(trimmed[1 : len(trimmed)-1])
+		default:
+			sec := song.section()
+			if trimmed == "" && len(sec.Lines) == 0 {
+				continue
+			}
+			sec.Lines = append(sec.Lines, parseLyrics(line))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	song.trim()
+	return song, nil
+}
+
+// directive applies a ChordPro directive (without braces) to the song.
+func (s *Song) directive(d string) {
+	name, value := d, ""
+	if i := strings.IndexAny(d, ": "); i >= 0 {
+		name, value = d[:i], strings.TrimSpace(d[i+1:])
+	}
+	name = strings.ToLower(strings.TrimSpace(name))
+	kind, ok := sectionDirectives[name]
+	if !ok && strings.HasPrefix(name, "start_of_") && len(name) > len("start_of_") {
+		kind, ok = strings.TrimPrefix(name, "start_of_"), true
+	}
+	if ok {
+		s.Sections = append(s.Sections, &Section{Kind: kind, Label: value})
+		return
+	}
+	if endDirectives[name] || (strings.HasPrefix(name, "end_of_") && len(name) > len("end_of_")) {
+		// following lines are out of any section
+		s.Sections = append(s.Sections, &Section{})
+		return
+	}
+	switch name {
+	case "title", "t":
+		s.Title = value
+	case "subtitle", "st":
+		s.Subtitle = value
+	case "artist":
+		s.Artist = value
+	case "key":
+		s.Key = value
+	case "comment", "c", "comment_italic", "ci", "comment_box", "cb", "highlight":
+		sec := s.section()
+		sec.Lines = append(sec.Lines, Line{Comment: value})
+	default:
+		s.Meta[name] = value
+	}
+}
+
+// parseLyrics extracts the bracketed chords of a ChordPro line of lyrics.
+func parseLyrics(line string) Line {
+	l := Line{}
+	var lyrics strings.Builder
+	for {
+		open := strings.Index(line, "[")
+		if open < 0 {
+			break
+		}
+		end := strings.Index(line[open:], "]")
+		if end < 0 {
+			break
+		}
+		lyrics.WriteString(line[:open])
+		l.Chords = append(l.Chords, newChordAt(line[open+1:open+end], runeLen(lyrics.String())))
+		line = line[open+end+1:]
+	}
+	lyrics.WriteString(line)
+	l.Lyrics = lyrics.String()
+	return l
+}
+
+// trim removes the empty sections and the trailing empty lines of the
+// sections.
+func (s *Song) trim() {
+	sections := []*Section{}
+	for _, sec := range s.Sections {
+		for len(sec.Lines) > 0 {
+			last := sec.Lines[len(sec.Lines)-1]
+			if last.Lyrics != "" || last.Comment != "" || len(last.Chords) > 0 {
+				break
+			}
+			sec.Lines = sec.Lines[:len(sec.Lines)-1]
+		}
+		if len(sec.Lines) > 0 || sec.Kind != "" {
+			sections = append(sections, sec)
+		}
+	}
+	s.Sections = sections
+}
+
+// sectionHeaders are the names of the sections recognized in plain text lead
+// sheets ("Chorus:", "[Verse 2]").
+var sectionHeaders = []string{"verse", "chorus", "bridge", "intro", "outro", "pre-chorus", "interlude", "solo", "refrain", "coda", "tag", "instrumental"}
+
+// ParseText reads a plain text lead sheet where chords are written on the
+// line above the lyrics they are played on. Lines only made of chord symbols
+// (and bar lines, N.C. or %) are chord lines, section headers such as
+// "Chorus:" or "[Verse 2]" start sections and the first line, if not a chord
+// or section line, is used as the title.
+func ParseText(r io.Reader) (*Song, error) {
+	song := &Song{Meta: map[string]string{}}
+	scanner := bufio.NewScanner(r)
+	var pending *Line
+	flush := func() {
+		if pending != nil {
+			sec := song.section()
+			sec.Lines = append(sec.Lines, *pending)
+			pending = nil
+		}
+	}
+	first := true
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		trimmed := strings.TrimSpace(line)
+		if chords, ok := parseChordLine(line); ok {
+			flush()
+			pending = &Line{Chords: chords}
+			first = false
+			continue
+		}
+		if kind, label, ok := sectionHeader(trimmed); ok {
+			flush()
+			song.Sections = append(song.Sections, &Section{Kind: kind, Label: label})
+			first = false
+			continue
+		}
+		if first && trimmed != "" {
+			song.Title = trimmed
+			first = false
+			continue
+		}
+		if pending != nil {
+			// lyrics of the chord line
+			pending.Lyrics = line
+			flush()
+			continue
+		}
+		sec := song.section()
+		if trimmed == "" && len(sec.Lines) == 0 {
+			continue
+		}
+		sec.Lines = append(sec.Lines, Line{Lyrics: line})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	flush()
+	song.trim()
+	return song, nil
+}
+
+// parseChordLine returns the chords of a chords line with their column, false
+// if the line isn't a chord line.
+func parseChordLine(line string) ([]ChordAt, bool) {
+	chords := []ChordAt{}
+	var symbols int
+	runes := []rune(line)
+	for i := 0; i < len(runes); {
+		if runes[i] == ' ' || runes[i] == '\t' {
+			i++
+			continue
+		}
+		start := i
+		for i < len(runes) && runes[i] != ' ' && runes[i] != '\t' {
+			i++
+		}
+		token := string(runes[start:i])
+		switch token {
+		case "|", "||", "%", "N.C.", "NC", "/", "-":
+			if token != "|" && token != "||" {
+				chords = append(chords, ChordAt{Text: token, Position: start})
+			}
+			continue
+		}
+		if _, err := theory.ParseChordSymbol(token); err != nil {
+			return nil, false
+		}
+		symbols++
+		chords = append(chords, newChordAt(token, start))
+	}
+	return chords, symbols > 0
+}
+
+// sectionHeader reports if the line is a section header, returning its kind
+// and label.
+func sectionHeader(line string) (kind, label string, ok bool) {
+	label = strings.TrimSuffix(strings.TrimPrefix(strings.TrimSuffix(line, ":"), "["), "]")
+	if strings.HasPrefix(line, "[") != strings.HasSuffix(line, "]") || (!strings.HasPrefix(line, "[") && !strings.HasSuffix(line, ":")) {
+		return "", "", false
+	}
+	lower := strings.ToLower(label)
+	for _, h := range sectionHeaders {
+		if strings.HasPrefix(lower, h) {
+			return h, label, true
+		}
+	}
+	return "", "", false
+}
@@ -0,0 +1,131 @@
+package chordpro
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// WriteChordPro writes the song using the ChordPro format.
+func (s *Song) WriteChordPro(w io.Writer) error {
+	buf := bytes.NewBuffer(nil)
+	meta := [][2]string{{"title", s.Title}, {"subtitle", s.Subtitle}, {"artist", s.Artist}, {"key", s.Key}}
+	names := make([]string, 0, len(s.Meta))
+	for name := range s.Meta {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		meta = append(meta, [2]string{name, s.Meta[name]})
+	}
+	for _, m := range meta {
+		if m[1] != "" {
+			fmt.Fprintf(buf, "{%s: %s}\n", m[0], m[1])
+		}
+	}
+	for _, sec := range s.Sections {
+		buf.WriteString("\n")
+		if sec.Kind != "" {
+			if sec.Label != "" {
+				fmt.Fprintf(buf, "{start_of_%s: %s}\n", sec.Kind, sec.Label)
+			} else {
+				fmt.Fprintf(buf, "{start_of_%s}\n", sec.Kind)
+			}
+		}
+		for _, line := range sec.Lines {
+			if line.Comment != "" {
+				fmt.Fprintf(buf, "{comment: %s}\n", line.Comment)
+				continue
+			}
+			buf.WriteString(line.chordPro() + "\n")
+		}
+		if sec.Kind != "" {
+			fmt.Fprintf(buf, "{end_of_%s}\n", sec.Kind)
+		}
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// chordPro returns the lyrics with the chords inserted between brackets.
+func (l Line) chordPro() string {
+	lyrics := []rune(l.Lyrics)
+	var out strings.Builder
+	var pos int
+	for _, c := range l.Chords {
+		for pos < c.Position {
+			if pos < len(lyrics) {
+				out.WriteRune(lyrics[pos])
+			} else {
+				out.WriteRune(' ')
+			}
+			pos++
+		}
+		out.WriteString("[" + c.Text + "]")
+	}
+	if pos < len(lyrics) {
+		out.WriteString(string(lyrics[pos:]))
+	}
+	return out.String()
+}
+
+// WriteText writes the song as a plain text lead sheet with the chords on the
+// line above the lyrics.
+func (s *Song) WriteText(w io.Writer) error {
+	buf := bytes.NewBuffer(nil)
+	if s.Title != "" {
+		buf.WriteString(s.Title + "\n")
+	}
+	for i, sec := range s.Sections {
+		if i > 0 || s.Title != "" {
+			buf.WriteString("\n")
+		}
+		if sec.Kind != "" {
+			label := sec.Label
+			if label == "" {
+				label = strings.ToUpper(sec.Kind[:1]) + sec.Kind[1:]
+			}
+			buf.WriteString(label + ":\n")
+		}
+		for _, line := range sec.Lines {
+			if line.Comment != "" {
+				buf.WriteString(line.Comment + "\n")
+				continue
+			}
+			if len(line.Chords) > 0 {
+				buf.WriteString(line.chordLine() + "\n")
+			}
+			if line.Lyrics != "" || len(line.Chords) == 0 {
+				buf.WriteString(line.Lyrics + "\n")
+			}
+		}
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// chordLine returns the chords placed above the lyrics, chords too close to
+// the previous one are shifted to the right.
+func (l Line) chordLine() string {
+	var out strings.Builder
+	var col int
+	for i, c := range l.Chords {
+		pos := c.Position
+		if i > 0 && pos <= col {
+			pos = col + 1
+		}
+		out.WriteString(strings.Repeat(" ", pos-col))
+		out.WriteString(c.Text)
+		col = pos + runeLen(c.Text)
+	}
+	return out.String()
+}
+
+// String returns the song using the ChordPro format.
+func (s *Song) String() string {
+	buf := bytes.NewBuffer(nil)
+	s.WriteChordPro(buf)
+	return buf.String()
+}
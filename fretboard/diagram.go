@@ -0,0 +1,213 @@
+package fretboard
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-audio/music/theory"
+)
+
+// Style is the set of characters used to draw text diagrams.
+type Style int
+
+const (
+	// ASCII draws diagrams using ASCII characters only.
+	ASCII Style = iota
+	// Unicode draws diagrams using box drawing characters.
+	Unicode
+)
+
+// diagramChars are the characters used by a style.
+type diagramChars struct {
+	muted, open, note, root string
+	// nut, top, fret and bottom are the left, middle and right characters of
+	// the horizontal lines, the nut also has its own wire
+	nut, top, fret, bottom string
+	str, wire              string
+	neckNut, neckFret      string
+}
+
+var styleChars = map[Style]diagramChars{
+	ASCII: {
+		muted: "x", open: "o", note: "*", root: "R",
+		nut: "====", top: "+++", fret: "+++", bottom: "+++", str: "|", wire: "-",
+		neckNut: "|", neckFret: "|",
+	},
+	Unicode: {
+		muted: "×", open: "○", note: "●", root: "◉",
+		nut: "╒╤╕═", top: "┌┬┐", fret: "├┼┤", bottom: "└┴┘", str: "│", wire: "─",
+		neckNut: "‖", neckFret: "│",
+	},
+}
+
+// minDiagramFrets is the smallest number of frets drawn by chord diagrams.
+const minDiagramFrets = 4
+
+// diagramRange returns the first fret and the number of frets drawn for the
+// shape. Shapes fitting in the first frets are drawn from the nut.
+func (s Shape) diagramRange() (first, frets int) {
+	frets = s.Stretch() + 1
+	if frets < minDiagramFrets {
+		frets = minDiagramFrets
+	}
+	highest := s.LowestFret() + s.Stretch()
+	if highest <= frets {
+		return 1, frets
+	}
+	return s.LowestFret(), frets
+}
+
+// Diagram draws the shape as a chord diagram, strings are vertical with the
+// lowest on the left and the nut on top. Diagrams not starting at the nut
+// are labeled with their first fret (5fr).
+//
+//	x     o   o
+//	===========
+//	| | | | * |
+//	+-+-+-+-+-+
+//	| | * | | |
+//	+-+-+-+-+-+
+//	| * | | | |
+//	+-+-+-+-+-+
+//	| | | | | |
+//	+-+-+-+-+-+
+func (s Shape) Diagram(style Style) string {
+	chars := styleChars[style]
+	n := len(s.Frets)
+	if n == 0 {
+		return ""
+	}
+	first, frets := s.diagramRange()
+	b := &strings.Builder{}
+
+	// open and muted strings
+	header := make([]string, n)
+	for i, fret := range s.Frets {
+		switch fret {
+		case Muted:
+			header[i] = chars.muted
+		case 0:
+			header[i] = chars.open
+		default:
+			header[i] = " "
+		}
+	}
+	b.WriteString(strings.TrimRight(strings.Join(header, " "), " ") + "\n")
+
+	if first == 1 {
+		b.WriteString(boxLine(chars.nut, runeAt(chars.nut, 3), n) + "\n")
+	} else {
+		b.WriteString(boxLine(chars.top, chars.wire, n) + "\n")
+	}
+	for fret := first; fret < first+frets; fret++ {
+		row := make([]string, n)
+		for i, f := range s.Frets {
+			row[i] = chars.str
+			if f == fret {
+				row[i] = chars.note
+			}
+		}
+		line := strings.Join(row, " ")
+		if fret == first && first > 1 {
+			line += fmt.Sprintf(" %dfr", first)
+		}
+		b.WriteString(line + "\n")
+		if fret < first+frets-1 {
+			b.WriteString(boxLine(chars.fret, chars.wire, n) + "\n")
+		} else {
+			b.WriteString(boxLine(chars.bottom, chars.wire, n) + "\n")
+		}
+	}
+	return b.String()
+}
+
+// boxLine draws a horizontal line across n strings using the left, middle
+// and right characters of ends.
+func boxLine(ends, wire string, n int) string {
+	left, middle, right := runeAt(ends, 0), runeAt(ends, 1), runeAt(ends, 2)
+	if n == 1 {
+		return middle
+	}
+	return left + strings.Repeat(wire+middle, n-2) + wire + right
+}
+
+// runeAt returns the i-th character of s.
+func runeAt(s string, i int) string {
+	return string([]rune(s)[i])
+}
+
+// ScaleDiagram draws the notes of the scale between two frets (inclusive)
+// as a neck diagram, strings are horizontal with the highest string on top
+// as in tablatures. Roots are drawn differently from the other notes.
+//
+//	    0   1   2   3
+//	E  -R-|---|-*-|---|
+//	B  ---|-R-|---|-*-|
+func (f *Fretboard) ScaleDiagram(s *theory.Scale, from, to int, style Style) string {
+	chars := styleChars[style]
+	labels := map[Position]string{}
+	for p, m := range f.scaleMarks(s, from, to) {
+		labels[p] = chars.note
+		if m.root {
+			labels[p] = chars.root
+		}
+	}
+	return f.neckDiagram(labels, from, to, style)
+}
+
+// mark is a note drawn on a neck diagram.
+type mark struct {
+	label string
+	root  bool
+}
+
+// scaleMarks returns the notes of the scale between two frets, labeled with
+// their names.
+func (f *Fretboard) scaleMarks(s *theory.Scale, from, to int) map[Position]mark {
+	marks := map[Position]mark{}
+	if s == nil {
+		return marks
+	}
+	spelling := s.Spelling()
+	for _, p := range f.PitchClassPositions(s.Notes(), to) {
+		if p.Fret < from {
+			continue
+		}
+		key := f.Key(p.String, p.Fret)
+		marks[p] = mark{label: theory.NoteName(key, spelling), root: mod12(key) == mod12(s.Root)}
+	}
+	return marks
+}
+
+// neckDiagram draws labels on the neck between two frets, labels are one or
+// two characters long.
+func (f *Fretboard) neckDiagram(marks map[Position]string, from, to int, style Style) string {
+	chars := styleChars[style]
+	header := "   "
+	for fret := from; fret <= to; fret++ {
+		header += fmt.Sprintf(" %-3d", fret)
+	}
+	b := &strings.Builder{}
+	b.WriteString(strings.TrimRight(header, " ") + "\n")
+	for str := f.Strings() - 1; str >= 0; str-- {
+		b.WriteString(fmt.Sprintf("%-3s", theory.NoteName(f.Key(str, 0), theory.SharpSpelling)))
+		for fret := from; fret <= to; fret++ {
+			cell := chars.wire + chars.wire + chars.wire
+			if label, ok := marks[Position{String: str, Fret: fret}]; ok {
+				switch len([]rune(label)) {
+				case 1:
+					cell = chars.wire + label + chars.wire
+				default:
+					cell = label + chars.wire
+				}
+			}
+			sep := chars.neckFret
+			if fret == 0 {
+				sep = chars.neckNut
+			}
+			b.WriteString(cell + sep)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,99 @@
+package fretboard
+
+import (
+	"testing"
+
+	"github.com/go-audio/music/theory"
+)
+
+func TestShape_Diagram(t *testing.T) {
+	tests := []struct {
+		name  string
+		tab   string
+		style Style
+		want  string
+	}{
+		{"open C", "x32010", ASCII, `x     o   o
+===========
+| | | | * |
++-+-+-+-+-+
+| | * | | |
++-+-+-+-+-+
+| * | | | |
++-+-+-+-+-+
+| | | | | |
++-+-+-+-+-+
+`},
+		{"unicode", "xx0232", Unicode, `× × ○
+╒═╤═╤═╤═╤═╕
+│ │ │ │ │ │
+├─┼─┼─┼─┼─┤
+│ │ │ ● │ ●
+├─┼─┼─┼─┼─┤
+│ │ │ │ ● │
+├─┼─┼─┼─┼─┤
+│ │ │ │ │ │
+└─┴─┴─┴─┴─┘
+`},
+		{"up the neck", "x-x-10-10-10-8", ASCII, `x x
++-+-+-+-+-+
+| | | | | * 8fr
++-+-+-+-+-+
+| | | | | |
++-+-+-+-+-+
+| | * * * |
++-+-+-+-+-+
+| | | | | |
++-+-+-+-+-+
+`},
+		{"wide stretch", "x-5-9-x-x-x", ASCII, `x     x x x
++-+-+-+-+-+
+| * | | | | 5fr
++-+-+-+-+-+
+| | | | | |
++-+-+-+-+-+
+| | | | | |
++-+-+-+-+-+
+| | | | | |
++-+-+-+-+-+
+| | * | | |
++-+-+-+-+-+
+`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := ParseShape(tt.tab)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := s.Diagram(tt.style); got != tt.want {
+				t.Errorf("expected\n%s\ngot\n%s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestFretboard_ScaleDiagram(t *testing.T) {
+	f := New(StandardTuning, 15)
+	s := &theory.Scale{Root: 9, Def: theory.ScaleDefMap[theory.MinorPentatonicScale]}
+	want := `    5   6   7   8
+E  -R-|---|---|-*-|
+B  -*-|---|---|-*-|
+G  -*-|---|-*-|---|
+D  -*-|---|-R-|---|
+A  -*-|---|-*-|---|
+E  -R-|---|---|-*-|
+`
+	if got := f.ScaleDiagram(s, 5, 8, ASCII); got != want {
+		t.Errorf("expected\n%s\ngot\n%s", want, got)
+	}
+	want = `    0   1   2
+A  ─◉─‖───│───│
+E  ─●─‖───│───│
+C  ─●─‖───│─●─│
+G  ─●─‖───│─◉─│
+`
+	if got := New(UkuleleTuning, 12).ScaleDiagram(s, 0, 2, Unicode); got != want {
+		t.Errorf("expected\n%s\ngot\n%s", want, got)
+	}
+}
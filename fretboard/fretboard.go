@@ -0,0 +1,115 @@
+// Package fretboard models fretted instruments such as guitars, basses and
+// ukuleles and finds playable chord shapes on them.
+package fretboard
+
+import (
+	"github.com/go-audio/midi"
+)
+
+// Tuning lists the open string keys of a fretted instrument, from the lowest
+// string (the 6th string of a guitar) to the highest.
+type Tuning struct {
+	Name    string
+	Strings []int
+}
+
+// Common tunings. Octaves follow the midi package numbering, the low E of a
+// guitar (E2 in scientific pitch notation) is midi.KeyInt("E", 1).
+var (
+	StandardTuning = Tuning{Name: "Standard", Strings: []int{
+		midi.KeyInt("E", 1), midi.KeyInt("A", 1), midi.KeyInt("D", 2),
+		midi.KeyInt("G", 2), midi.KeyInt("B", 2), midi.KeyInt("E", 3),
+	}}
+	DropDTuning = Tuning{Name: "Drop D", Strings: []int{
+		midi.KeyInt("D", 1), midi.KeyInt("A", 1), midi.KeyInt("D", 2),
+		midi.KeyInt("G", 2), midi.KeyInt("B", 2), midi.KeyInt("E", 3),
+	}}
+	DADGADTuning = Tuning{Name: "DADGAD", Strings: []int{
+		midi.KeyInt("D", 1), midi.KeyInt("A", 1), midi.KeyInt("D", 2),
+		midi.KeyInt("G", 2), midi.KeyInt("A", 2), midi.KeyInt("D", 3),
+	}}
+	OpenGTuning = Tuning{Name: "Open G", Strings: []int{
+		midi.KeyInt("D", 1), midi.KeyInt("G", 1), midi.KeyInt("D", 2),
+		midi.KeyInt("G", 2), midi.KeyInt("B", 2), midi.KeyInt("D", 3),
+	}}
+	BassTuning = Tuning{Name: "Bass", Strings: []int{
+		midi.KeyInt("E", 0), midi.KeyInt("A", 0), midi.KeyInt("D", 1), midi.KeyInt("G", 1),
+	}}
+	// UkuleleTuning is the re-entrant GCEA tuning, the G string is higher than
+	// the C string.
+	UkuleleTuning = Tuning{Name: "Ukulele", Strings: []int{
+		midi.KeyInt("G", 3), midi.KeyInt("C", 3), midi.KeyInt("E", 3), midi.KeyInt("A", 3),
+	}}
+)
+
+// Fretboard is a fretted instrument using a tuning, with a number of frets and
+// an optional capo.
+type Fretboard struct {
+	Tuning Tuning
+	// Frets is the number of frets of the instrument.
+	Frets int
+	// Capo is the fret the capo is placed on, 0 without a capo. Frets of
+	// positions and shapes are relative to the capo.
+	Capo int
+}
+
+// New returns a fretboard using the tuning and number of frets.
+func New(tuning Tuning, frets int) *Fretboard {
+	return &Fretboard{Tuning: tuning, Frets: frets}
+}
+
+// Position is a fret on a string, strings are indexed from the lowest (0).
+type Position struct {
+	String int
+	Fret   int
+}
+
+// Strings returns the number of strings of the instrument.
+func (f *Fretboard) Strings() int {
+	return len(f.Tuning.Strings)
+}
+
+// MaxFret returns the highest fret playable above the capo.
+func (f *Fretboard) MaxFret() int {
+	return f.Frets - f.Capo
+}
+
+// Key returns the MIDI key played on the string at the fret (relative to the
+// capo).
+func (f *Fretboard) Key(str, fret int) int {
+	return f.Tuning.Strings[str] + f.Capo + fret
+}
+
+// Positions returns the positions playing the passed key, from the lowest
+// string.
+func (f *Fretboard) Positions(key int) []Position {
+	var positions []Position
+	for s := range f.Tuning.Strings {
+		fret := key - f.Key(s, 0)
+		if fret >= 0 && fret <= f.MaxFret() {
+			positions = append(positions, Position{String: s, Fret: fret})
+		}
+	}
+	return positions
+}
+
+// PitchClassPositions returns all the positions, up to the passed fret,
+// playing one of the pitch classes (0-11).
+func (f *Fretboard) PitchClassPositions(pcs []int, maxFret int) []Position {
+	if maxFret > f.MaxFret() {
+		maxFret = f.MaxFret()
+	}
+	var positions []Position
+	for s := range f.Tuning.Strings {
+		for fret := 0; fret <= maxFret; fret++ {
+			pc := f.Key(s, fret) % 12
+			for _, p := range pcs {
+				if p%12 == pc {
+					positions = append(positions, Position{String: s, Fret: fret})
+					break
+				}
+			}
+		}
+	}
+	return positions
+}
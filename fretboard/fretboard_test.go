@@ -0,0 +1,55 @@
+package fretboard
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-audio/midi"
+)
+
+func TestFretboard_Key(t *testing.T) {
+	f := New(StandardTuning, 22)
+	tests := []struct {
+		name      string
+		capo      int
+		str, fret int
+		want      int
+	}{
+		{"low E", 0, 0, 0, midi.KeyInt("E", 1)},
+		{"middle C", 0, 4, 1, midi.KeyInt("C", 3)},
+		{"high A", 0, 5, 17, midi.KeyInt("A", 4)},
+		{"capo open string", 3, 0, 0, midi.KeyInt("G", 1)},
+		{"capo fret", 3, 2, 2, midi.KeyInt("G", 2)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f.Capo = tt.capo
+			if got := f.Key(tt.str, tt.fret); got != tt.want {
+				t.Errorf("expected %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestFretboard_Positions(t *testing.T) {
+	f := New(StandardTuning, 12)
+	want := []Position{{1, 10}, {2, 5}, {3, 0}}
+	if got := f.Positions(midi.KeyInt("G", 2)); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	// the G string is under the capo
+	f.Capo = 2
+	want = []Position{{1, 8}, {2, 3}}
+	if got := f.Positions(midi.KeyInt("G", 2)); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if got := f.MaxFret(); got != 10 {
+		t.Errorf("expected 10 frets above the capo, got %d", got)
+	}
+
+	bass := New(BassTuning, 4)
+	want = []Position{{0, 0}, {0, 3}, {2, 2}, {3, 0}}
+	if got := bass.PitchClassPositions([]int{7, 4}, 4); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
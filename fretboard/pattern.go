@@ -0,0 +1,265 @@
+package fretboard
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/go-audio/music/theory"
+)
+
+// PatternNote is a note of a scale pattern.
+type PatternNote struct {
+	Position
+	Key int
+	// Degree is the index of the note in the scale, see Scale.IndexOfNote.
+	Degree int
+	// Label names the degree relative to the major scale (1, b3, #4...).
+	Label string
+}
+
+// Pattern is a fingering of a scale on the fretboard, such as a CAGED
+// position. Notes are sorted by string, from the lowest, and by fret.
+type Pattern struct {
+	Name  string
+	Notes []PatternNote
+}
+
+// FretRange returns the lowest and highest frets of the pattern.
+func (p Pattern) FretRange() (lowest, highest int) {
+	for i, n := range p.Notes {
+		if i == 0 || n.Fret < lowest {
+			lowest = n.Fret
+		}
+		if n.Fret > highest {
+			highest = n.Fret
+		}
+	}
+	return lowest, highest
+}
+
+// majorHalfSteps are the half steps between the tonic and the degrees of the
+// major scale.
+var majorHalfSteps = []int{0, 2, 4, 5, 7, 9, 11}
+
+// chromaticLabels name the degrees of scales that don't have seven notes by
+// their half steps from the tonic.
+var chromaticLabels = []string{"1", "b2", "2", "b3", "3", "4", "b5", "5", "b6", "6", "b7", "7"}
+
+// DegreeLabel names the degree of the key in the scale relative to the major
+// scale: 1 b3 4 5 b7 for a minor pentatonic, 1 2 3 #4 5 6 7 for a Lydian
+// scale. An empty string is returned if the key isn't in the scale.
+func DegreeLabel(s *theory.Scale, key int) string {
+	idx := s.IndexOfNote(mod12(key))
+	if idx < 0 {
+		return ""
+	}
+	hs := mod12(key - s.Root)
+	if len(s.Notes()) != 7 {
+		return chromaticLabels[hs]
+	}
+	diff := hs - majorHalfSteps[idx]
+	if diff > 6 {
+		diff -= 12
+	} else if diff < -6 {
+		diff += 12
+	}
+	label := strconv.Itoa(idx + 1)
+	for ; diff < 0; diff++ {
+		label = "b" + label
+	}
+	for ; diff > 0; diff-- {
+		label = "#" + label
+	}
+	return label
+}
+
+// cagedShapes are the CAGED positions: the string holding the root used as
+// anchor and the frets covered around it.
+var cagedShapes = []struct {
+	name     string
+	str      int
+	low, top int
+}{
+	{"C", 1, -3, 0},
+	{"A", 1, -1, 2},
+	{"G", 0, -3, 0},
+	{"E", 0, -1, 2},
+	{"D", 2, -1, 2},
+}
+
+// CAGED returns the five CAGED positions of the scale, named after the open
+// chord shape built on the scale tonic and sorted by fret. Minor scales use
+// the positions of their relative major (the A minor pentatonic box starting
+// on the 5th fret is the G position). Each position covers four frets around
+// a root on the 6th, 5th or 4th string, notes one fret away are added so no
+// degree is skipped. The positions are meant for six-string instruments using
+// standard tuning (or a similar tuning).
+func (f *Fretboard) CAGED(s *theory.Scale) []Pattern {
+	if s == nil || f.Strings() < 3 {
+		return nil
+	}
+	tonic := s.Root
+	if s.IsMinor() {
+		tonic += 3
+	}
+	var patterns []Pattern
+	for _, shape := range cagedShapes {
+		root := mod12(tonic - f.Key(shape.str, 0))
+		// positions going more than a fret below the nut are played an
+		// octave higher
+		if root+shape.low < -1 {
+			root += 12
+		}
+		from, to := root+shape.low, root+shape.top
+		if from < 0 {
+			from = 0
+		}
+		if to > f.MaxFret() {
+			continue
+		}
+		patterns = append(patterns, Pattern{Name: shape.name, Notes: f.boxNotes(s, from, to)})
+	}
+	sort.SliceStable(patterns, func(i, j int) bool {
+		li, _ := patterns[i].FretRange()
+		lj, _ := patterns[j].FretRange()
+		return li < lj
+	})
+	return patterns
+}
+
+// boxNotes returns the notes of the scale between two frets, adding the
+// missing scale keys one fret beyond the box.
+func (f *Fretboard) boxNotes(s *theory.Scale, from, to int) []PatternNote {
+	var notes []PatternNote
+	keys := map[int]bool{}
+	perString := make([]int, f.Strings())
+	for _, p := range f.PitchClassPositions(s.Notes(), to) {
+		if p.Fret >= from {
+			notes = append(notes, f.patternNote(s, p))
+			keys[f.Key(p.String, p.Fret)] = true
+			perString[p.String]++
+		}
+	}
+	if len(notes) == 0 {
+		return nil
+	}
+	lowest, highest := notes[0].Key, notes[0].Key
+	for _, n := range notes {
+		if n.Key < lowest {
+			lowest = n.Key
+		}
+		if n.Key > highest {
+			highest = n.Key
+		}
+	}
+	for key := lowest; key <= highest; key++ {
+		if keys[key] || s.IndexOfNote(mod12(key)) < 0 {
+			continue
+		}
+		// play the missing key one fret away from the box, on the string
+		// having the fewest notes (the highest one in case of tie)
+		best := -1
+		for str := f.Strings() - 1; str >= 0; str-- {
+			fret := key - f.Key(str, 0)
+			if fret == from-1 && fret >= 0 || fret == to+1 && fret <= f.MaxFret() {
+				if best < 0 || perString[str] < perString[best] {
+					best = str
+				}
+			}
+		}
+		if best >= 0 {
+			notes = append(notes, f.patternNote(s, Position{String: best, Fret: key - f.Key(best, 0)}))
+			perString[best]++
+		}
+	}
+	sortPatternNotes(notes)
+	return notes
+}
+
+// ThreeNotesPerString returns the three notes per string patterns of the
+// scale, one starting on each degree of the scale on the lowest string,
+// sorted by fret. Patterns that don't fit on the fretboard are skipped.
+func (f *Fretboard) ThreeNotesPerString(s *theory.Scale) []Pattern {
+	if s == nil || f.Strings() == 0 {
+		return nil
+	}
+	var patterns []Pattern
+	for degree, pc := range s.Notes() {
+		start := f.Key(0, 0) + mod12(pc-f.Key(0, 0))
+		run := s.Run(start, 3*f.Strings()-1)
+		// start an octave higher if a string would need a negative fret
+		if !f.fitsThreePerString(run) {
+			for i := range run {
+				run[i] += 12
+			}
+		}
+		if !f.fitsThreePerString(run) {
+			continue
+		}
+		p := Pattern{Name: strconv.Itoa(degree + 1)}
+		for i, key := range run {
+			str := i / 3
+			p.Notes = append(p.Notes, f.patternNote(s, Position{String: str, Fret: key - f.Key(str, 0)}))
+		}
+		patterns = append(patterns, p)
+	}
+	sort.SliceStable(patterns, func(i, j int) bool {
+		li, _ := patterns[i].FretRange()
+		lj, _ := patterns[j].FretRange()
+		return li < lj
+	})
+	return patterns
+}
+
+// fitsThreePerString reports whether the run can be played three notes per
+// string.
+func (f *Fretboard) fitsThreePerString(run []int) bool {
+	for i, key := range run {
+		fret := key - f.Key(i/3, 0)
+		if fret < 0 || fret > f.MaxFret() {
+			return false
+		}
+	}
+	return true
+}
+
+func (f *Fretboard) patternNote(s *theory.Scale, p Position) PatternNote {
+	key := f.Key(p.String, p.Fret)
+	return PatternNote{
+		Position: p,
+		Key:      key,
+		Degree:   s.IndexOfNote(mod12(key)),
+		Label:    DegreeLabel(s, key),
+	}
+}
+
+func sortPatternNotes(notes []PatternNote) {
+	sort.Slice(notes, func(i, j int) bool {
+		if notes[i].String != notes[j].String {
+			return notes[i].String < notes[j].String
+		}
+		return notes[i].Fret < notes[j].Fret
+	})
+}
+
+// PatternDiagram draws the pattern on the neck using the degree labels, see
+// ScaleDiagram for the layout.
+func (f *Fretboard) PatternDiagram(p Pattern, style Style) string {
+	labels := map[Position]string{}
+	for _, n := range p.Notes {
+		labels[n.Position] = n.Label
+	}
+	from, to := p.FretRange()
+	return f.neckDiagram(labels, from, to, style)
+}
+
+// PatternSVG draws the pattern on the neck using the degree labels, see
+// ScaleSVG for the layout.
+func (f *Fretboard) PatternSVG(p Pattern) string {
+	marks := map[Position]mark{}
+	for _, n := range p.Notes {
+		marks[n.Position] = mark{label: n.Label, root: n.Degree == 0}
+	}
+	from, to := p.FretRange()
+	return f.neckSVG(marks, from, to)
+}
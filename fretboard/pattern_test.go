@@ -0,0 +1,142 @@
+package fretboard
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-audio/midi"
+	"github.com/go-audio/music/theory"
+)
+
+func TestDegreeLabel(t *testing.T) {
+	tests := []struct {
+		name  string
+		scale theory.ScaleName
+		root  string
+		want  []string
+	}{
+		{"major", theory.MajorScale, "C", []string{"1", "2", "3", "4", "5", "6", "7"}},
+		{"natural minor", theory.NaturalMinorScale, "A", []string{"1", "2", "b3", "4", "5", "b6", "b7"}},
+		{"lydian", theory.LydianScale, "F", []string{"1", "2", "3", "#4", "5", "6", "7"}},
+		{"locrian", theory.LocrianScale, "B", []string{"1", "b2", "b3", "4", "b5", "b6", "b7"}},
+		{"minor pentatonic", theory.MinorPentatonicScale, "E", []string{"1", "b3", "4", "5", "b7"}},
+		{"blues", theory.BluesScale, "A", []string{"1", "b3", "4", "b5", "5", "b7"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &theory.Scale{Root: midi.KeyInt(tt.root, 0), Def: theory.ScaleDefMap[tt.scale]}
+			var got []string
+			for _, n := range s.Notes() {
+				got = append(got, DegreeLabel(s, n+36))
+			}
+			if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+	s := &theory.Scale{Root: 0, Def: theory.ScaleDefMap[theory.MajorScale]}
+	if got := DegreeLabel(s, 61); got != "" {
+		t.Errorf("expected no label for C#, got %s", got)
+	}
+}
+
+func TestFretboard_CAGED(t *testing.T) {
+	f := New(StandardTuning, 15)
+	tests := []struct {
+		name   string
+		scale  *theory.Scale
+		names  string
+		ranges [][2]int
+	}{
+		{"C major", &theory.Scale{Root: 0, Def: theory.ScaleDefMap[theory.MajorScale]},
+			"C A G E D", [][2]int{{0, 3}, {1, 5}, {4, 8}, {7, 10}, {8, 12}}},
+		{"E major", &theory.Scale{Root: 4, Def: theory.ScaleDefMap[theory.MajorScale]},
+			"E D C A G", [][2]int{{0, 2}, {0, 4}, {4, 7}, {5, 9}, {8, 12}}},
+		{"A minor pentatonic", &theory.Scale{Root: 9, Def: theory.ScaleDefMap[theory.MinorPentatonicScale]},
+			"C A G E D", [][2]int{{0, 3}, {2, 5}, {5, 8}, {7, 10}, {9, 13}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patterns := f.CAGED(tt.scale)
+			var names []string
+			for i, p := range patterns {
+				names = append(names, p.Name)
+				if low, high := p.FretRange(); i < len(tt.ranges) && (low != tt.ranges[i][0] || high != tt.ranges[i][1]) {
+					t.Errorf("%s position: expected frets %v, got %d-%d", p.Name, tt.ranges[i], low, high)
+				}
+				// every note of the scale is played in each position
+				degrees := map[int]bool{}
+				for _, n := range p.Notes {
+					if n.Degree < 0 || n.Label != DegreeLabel(tt.scale, n.Key) || f.Key(n.String, n.Fret) != n.Key {
+						t.Errorf("invalid note %+v", n)
+					}
+					degrees[n.Degree] = true
+				}
+				if len(degrees) != len(tt.scale.Notes()) {
+					t.Errorf("%s position: expected %d degrees, got %d", p.Name, len(tt.scale.Notes()), len(degrees))
+				}
+			}
+			if got := strings.Join(names, " "); got != tt.names {
+				t.Errorf("expected positions %s, got %s", tt.names, got)
+			}
+		})
+	}
+}
+
+func TestFretboard_ThreeNotesPerString(t *testing.T) {
+	f := New(StandardTuning, 15)
+	s := &theory.Scale{Root: midi.KeyInt("G", 0), Def: theory.ScaleDefMap[theory.MajorScale]}
+	patterns := f.ThreeNotesPerString(s)
+	if len(patterns) != 7 {
+		t.Fatalf("expected 7 patterns, got %d", len(patterns))
+	}
+	for _, p := range patterns {
+		if len(p.Notes) != 18 {
+			t.Fatalf("pattern %s: expected 18 notes, got %d", p.Name, len(p.Notes))
+		}
+		for i, n := range p.Notes {
+			if n.String != i/3 {
+				t.Errorf("pattern %s: note %d on string %d", p.Name, i, n.String)
+			}
+			// the notes follow the scale upwards
+			if i > 0 && n.Key <= p.Notes[i-1].Key {
+				t.Errorf("pattern %s: note %d isn't above the previous one", p.Name, i)
+			}
+		}
+	}
+	first := patterns[0]
+	if first.Name != "6" || first.Notes[0].Fret != 0 || first.Notes[0].Label != "6" {
+		t.Errorf("expected the first pattern to start on the open E, got %s %+v", first.Name, first.Notes[0])
+	}
+	g := patterns[1]
+	if g.Name != "7" || g.Notes[0].Fret != 2 || g.Notes[3].Label != "3" {
+		t.Errorf("unexpected second pattern %s %+v", g.Name, g.Notes[:4])
+	}
+}
+
+func TestFretboard_PatternDiagram(t *testing.T) {
+	f := New(StandardTuning, 15)
+	s := &theory.Scale{Root: 9, Def: theory.ScaleDefMap[theory.MinorPentatonicScale]}
+	p := f.CAGED(s)[2]
+	want := `    5   6   7   8
+E  -1-|---|---|b3-|
+B  -5-|---|---|b7-|
+G  b3-|---|-4-|---|
+D  b7-|---|-1-|---|
+A  -4-|---|-5-|---|
+E  -1-|---|---|b3-|
+`
+	if got := f.PatternDiagram(p, ASCII); got != want {
+		t.Errorf("expected\n%s\ngot\n%s", want, got)
+	}
+	svg := f.PatternSVG(p)
+	if got := svgElements(t, svg); got["circle"] != 12 {
+		t.Errorf("expected 12 notes, got %v", got)
+	}
+	if n := strings.Count(svg, `fill="firebrick"`); n != 3 {
+		t.Errorf("expected 3 roots, got %d", n)
+	}
+	if !strings.Contains(svg, ">b7</text>") {
+		t.Errorf("expected degree labels:\n%s", svg)
+	}
+}
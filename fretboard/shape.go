@@ -0,0 +1,351 @@
+package fretboard
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-audio/music/theory"
+)
+
+// Muted marks a string that isn't played in a shape.
+const Muted = -1
+
+// Shape is a chord fingering: the fret played on each string, from the lowest
+// string, Muted for strings that aren't played. Frets are relative to the
+// capo, 0 being the open string.
+type Shape struct {
+	Frets []int
+}
+
+// String returns the shape using the tab notation (x32010), frets are
+// separated by dashes when one of them is above 9 (x-10-12-12-12-10).
+func (s Shape) String() string {
+	sep := ""
+	for _, fret := range s.Frets {
+		if fret > 9 {
+			sep = "-"
+		}
+	}
+	parts := make([]string, len(s.Frets))
+	for i, fret := range s.Frets {
+		if fret < 0 {
+			parts[i] = "x"
+			continue
+		}
+		parts[i] = strconv.Itoa(fret)
+	}
+	return strings.Join(parts, sep)
+}
+
+// Keys returns the keys played by the shape on the fretboard, from the lowest
+// string.
+func (s Shape) Keys(f *Fretboard) []int {
+	var keys []int
+	for str, fret := range s.Frets {
+		if fret >= 0 {
+			keys = append(keys, f.Key(str, fret))
+		}
+	}
+	return keys
+}
+
+// Chord returns the chord played by the shape with its keys sorted by pitch.
+func (s Shape) Chord(f *Fretboard) *theory.Chord {
+	keys := s.Keys(f)
+	sort.Ints(keys)
+	return &theory.Chord{Keys: keys}
+}
+
+// Played returns the number of strings played.
+func (s Shape) Played() int {
+	var n int
+	for _, fret := range s.Frets {
+		if fret >= 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// Open returns the number of open strings played.
+func (s Shape) Open() int {
+	var n int
+	for _, fret := range s.Frets {
+		if fret == 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// LowestFret returns the lowest fretted fret, 0 if all the played strings are
+// open.
+func (s Shape) LowestFret() int {
+	lowest := 0
+	for _, fret := range s.Frets {
+		if fret > 0 && (lowest == 0 || fret < lowest) {
+			lowest = fret
+		}
+	}
+	return lowest
+}
+
+// Stretch returns the number of frets between the lowest and highest fretted
+// notes.
+func (s Shape) Stretch() int {
+	var highest int
+	for _, fret := range s.Frets {
+		if fret > highest {
+			highest = fret
+		}
+	}
+	if highest == 0 {
+		return 0
+	}
+	return highest - s.LowestFret()
+}
+
+// Fingers returns the number of fingers needed to play the shape, notes on
+// the lowest fret are barred by a single finger unless an open string is
+// played between them.
+func (s Shape) Fingers() int {
+	lowest := s.LowestFret()
+	first, last := -1, -1
+	var fretted, barred int
+	for i, fret := range s.Frets {
+		if fret > 0 {
+			fretted++
+			if fret == lowest {
+				barred++
+				if first < 0 {
+					first = i
+				}
+				last = i
+			}
+		}
+	}
+	if barred < 2 {
+		return fretted
+	}
+	for i := first + 1; i < last; i++ {
+		if s.Frets[i] == 0 {
+			return fretted
+		}
+	}
+	return fretted - barred + 1
+}
+
+// InnerMuted returns the number of muted strings between played strings.
+func (s Shape) InnerMuted() int {
+	first, last := -1, -1
+	for i, fret := range s.Frets {
+		if fret >= 0 {
+			if first < 0 {
+				first = i
+			}
+			last = i
+		}
+	}
+	var n int
+	for i := first + 1; i < last; i++ {
+		if s.Frets[i] < 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// cost ranks the shapes, the easiest to play first: small stretches, most
+// strings played, open strings (when close to the nut), few fingers and low
+// positions.
+func (s Shape) cost() float64 {
+	cost := float64(2*s.Stretch()) +
+		float64(2*(len(s.Frets)-s.Played())) +
+		float64(3*s.InnerMuted()) +
+		0.5*float64(s.Fingers()) +
+		0.75*float64(s.LowestFret())
+	if s.LowestFret() <= 4 {
+		cost -= float64(s.Open())
+	}
+	return cost
+}
+
+// ShapeOptions restrict the shapes returned by the shape finder.
+type ShapeOptions struct {
+	// MaxStretch is the largest number of frets between the lowest and highest
+	// fretted notes.
+	MaxStretch int
+	// MaxFingers is the largest number of fingers used, barres count as one
+	// finger.
+	MaxFingers int
+	// MinStrings is the smallest number of strings played, 0 means all the
+	// strings but two (three strings at least).
+	MinStrings int
+	// InnerMutes allows muting strings between played strings, by default
+	// only the lowest strings can be muted.
+	InnerMutes bool
+	// Limit is the maximum number of shapes returned, 0 for no limit.
+	Limit int
+}
+
+// DefaultShapeOptions are the options used when none are passed.
+var DefaultShapeOptions = ShapeOptions{MaxStretch: 3, MaxFingers: 4}
+
+// ChordShapes returns the shapes playing the chord, easiest first. All the
+// pitch classes of the chord are played (the fifth can be omitted from
+// chords of four notes or more) and the lowest note is the
+// lowest key of the chord so inversions are respected.
+func (f *Fretboard) ChordShapes(c *theory.Chord, opts *ShapeOptions) []Shape {
+	if c == nil || len(c.Keys) == 0 {
+		return nil
+	}
+	bass := c.Keys[0]
+	for _, k := range c.Keys {
+		if k < bass {
+			bass = k
+		}
+	}
+	bass %= 12
+	root := c.Copy().Def().RootInt()
+	if root < 0 {
+		root = bass
+	}
+	return f.shapes(pitchClasses(c.Keys), bass, root, opts)
+}
+
+// DefinitionShapes returns the shapes playing the chord definition in root
+// position, the definition root must be set.
+func (f *Fretboard) DefinitionShapes(def *theory.ChordDefinition, opts *ShapeOptions) []Shape {
+	root := def.RootInt()
+	if root < 0 {
+		return nil
+	}
+	keys := []int{root}
+	for _, hs := range def.HalfSteps {
+		keys = append(keys, keys[len(keys)-1]+int(hs))
+	}
+	return f.shapes(pitchClasses(keys), root, root, opts)
+}
+
+func (f *Fretboard) shapes(pcs []int, bass, root int, opts *ShapeOptions) []Shape {
+	o := DefaultShapeOptions
+	if opts != nil {
+		o = *opts
+	}
+	n := f.Strings()
+	if o.MinStrings == 0 {
+		o.MinStrings = n - 2
+		if o.MinStrings < 3 {
+			o.MinStrings = 3
+		}
+	}
+	if o.MinStrings > n {
+		o.MinStrings = n
+	}
+	// the fifth is often omitted from four note chords and larger chords
+	optional := -1
+	if len(pcs) >= 4 {
+		optional = (root + 7) % 12
+	}
+
+	seen := map[string]bool{}
+	var found []Shape
+	frets := make([]int, n)
+	var walk func(str, start int)
+	walk = func(str, start int) {
+		if str == n {
+			s := Shape{Frets: append([]int(nil), frets...)}
+			if f.playable(s, pcs, bass, optional, o) && !seen[s.String()] {
+				seen[s.String()] = true
+				found = append(found, s)
+			}
+			return
+		}
+		frets[str] = Muted
+		walk(str+1, start)
+		candidates := []int{0}
+		for fret := start; fret <= start+o.MaxStretch && fret <= f.MaxFret(); fret++ {
+			if fret > 0 {
+				candidates = append(candidates, fret)
+			}
+		}
+		for _, fret := range candidates {
+			if includes(pcs, f.Key(str, fret)%12) {
+				frets[str] = fret
+				walk(str+1, start)
+			}
+		}
+	}
+	for start := 1; start <= f.MaxFret(); start++ {
+		walk(0, start)
+	}
+
+	sort.SliceStable(found, func(i, j int) bool {
+		ci, cj := found[i].cost(), found[j].cost()
+		if ci != cj {
+			return ci < cj
+		}
+		return found[i].String() < found[j].String()
+	})
+	if o.Limit > 0 && len(found) > o.Limit {
+		found = found[:o.Limit]
+	}
+	return found
+}
+
+// playable reports whether the shape plays the chord with the options.
+func (f *Fretboard) playable(s Shape, pcs []int, bass, optional int, o ShapeOptions) bool {
+	if s.Played() < o.MinStrings || s.Stretch() > o.MaxStretch || s.Fingers() > o.MaxFingers {
+		return false
+	}
+	if !o.InnerMutes {
+		// only the lowest strings can be muted
+		var playing bool
+		for _, fret := range s.Frets {
+			if fret >= 0 {
+				playing = true
+			} else if playing {
+				return false
+			}
+		}
+	}
+	keys := s.Keys(f)
+	lowest := keys[0]
+	for _, k := range keys {
+		if k < lowest {
+			lowest = k
+		}
+	}
+	if lowest%12 != bass {
+		return false
+	}
+	played := pitchClasses(keys)
+	for _, pc := range pcs {
+		if pc != optional && !includes(played, pc) {
+			return false
+		}
+	}
+	return true
+}
+
+// pitchClasses returns the distinct pitch classes of the keys.
+func pitchClasses(keys []int) []int {
+	var pcs []int
+	for _, k := range keys {
+		pc := ((k % 12) + 12) % 12
+		if !includes(pcs, pc) {
+			pcs = append(pcs, pc)
+		}
+	}
+	return pcs
+}
+
+func includes(ints []int, v int) bool {
+	for _, i := range ints {
+		if i == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,110 @@
+package fretboard
+
+import (
+	"testing"
+
+	"github.com/go-audio/midi"
+	"github.com/go-audio/music/theory"
+)
+
+func TestShape(t *testing.T) {
+	tests := []struct {
+		shape                                  Shape
+		str                                    string
+		played, open, lowest, stretch, fingers int
+	}{
+		{Shape{Frets: []int{-1, 3, 2, 0, 1, 0}}, "x32010", 5, 2, 1, 2, 3},
+		{Shape{Frets: []int{1, 3, 3, 2, 1, 1}}, "133211", 6, 0, 1, 2, 4},
+		// the open A string prevents the barre
+		{Shape{Frets: []int{1, 0, 3, 2, 1, 1}}, "103211", 6, 1, 1, 2, 5},
+		{Shape{Frets: []int{-1, 10, 12, 12, 12, 10}}, "x-10-12-12-12-10", 5, 0, 10, 2, 4},
+		{Shape{Frets: []int{0, 0, 0, 0}}, "0000", 4, 4, 0, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.str, func(t *testing.T) {
+			s := tt.shape
+			if got := s.String(); got != tt.str {
+				t.Errorf("String() = %s", got)
+			}
+			got := []int{s.Played(), s.Open(), s.LowestFret(), s.Stretch(), s.Fingers()}
+			want := []int{tt.played, tt.open, tt.lowest, tt.stretch, tt.fingers}
+			if !intsEqual(got, want) {
+				t.Errorf("expected played, open, lowest, stretch, fingers %v, got %v", want, got)
+			}
+		})
+	}
+	s := Shape{Frets: []int{-1, 3, 2, 0, 1, 0}}
+	if got := s.Chord(New(StandardTuning, 15)).Def().String(); got != "C Major" {
+		t.Errorf("expected C Major, got %s", got)
+	}
+}
+
+func TestFretboard_ChordShapes(t *testing.T) {
+	guitar := New(StandardTuning, 15)
+	capo := New(StandardTuning, 15)
+	capo.Capo = 2
+	tests := []struct {
+		name  string
+		f     *Fretboard
+		chord *theory.Chord
+		want  []string
+	}{
+		{"C", guitar, theory.NewChordFromAbbrev("Cmaj"), []string{"x32010", "x32013"}},
+		{"G", guitar, theory.NewChordFromAbbrev("Gmaj"), []string{"320003", "320033"}},
+		{"D", guitar, theory.NewChordFromAbbrev("Dmaj"), []string{"xx0232"}},
+		{"E", guitar, theory.NewChordFromAbbrev("Emaj"), []string{"022100"}},
+		{"Am", guitar, theory.NewChordFromAbbrev("Amin"), []string{"x02210"}},
+		{"C7", guitar, theory.NewChordFromAbbrev("C7"), []string{"x32310"}},
+		{"F", guitar, theory.NewChordFromAbbrev("Fmaj"), []string{"133211", "xx3211"}},
+		{"C/E", guitar, &theory.Chord{Keys: []int{midi.KeyInt("E", 1), midi.KeyInt("G", 1), midi.KeyInt("C", 2)}}, []string{"032010"}},
+		{"Bm with a capo", capo, theory.NewChordFromAbbrev("Bmin"), []string{"x02210"}},
+		{"ukulele C", New(UkuleleTuning, 12), theory.NewChordFromAbbrev("Cmaj"), []string{"0003"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shapes := tt.f.ChordShapes(tt.chord, nil)
+			if len(shapes) < len(tt.want) {
+				t.Fatalf("expected at least %d shapes, got %v", len(tt.want), shapes)
+			}
+			for i, w := range tt.want {
+				if got := shapes[i].String(); got != w {
+					t.Errorf("shape %d: expected %s, got %s (%v)", i, w, got, shapes)
+				}
+			}
+			for _, s := range shapes {
+				if s.Stretch() > DefaultShapeOptions.MaxStretch || s.Fingers() > DefaultShapeOptions.MaxFingers {
+					t.Errorf("%s isn't playable", s)
+				}
+			}
+		})
+	}
+}
+
+func TestFretboard_DefinitionShapes(t *testing.T) {
+	f := New(StandardTuning, 15)
+	def := theory.ChordDefs[0].WithRoot("A")
+	shapes := f.DefinitionShapes(def, &ShapeOptions{MaxStretch: 2, MaxFingers: 4, MinStrings: 6, Limit: 3})
+	if len(shapes) != 3 {
+		t.Fatalf("expected 3 shapes, got %v", shapes)
+	}
+	for _, s := range shapes {
+		if s.Played() != 6 || s.Chord(f).Def().String() != "A Major" {
+			t.Errorf("unexpected shape %s", s)
+		}
+	}
+	if got := f.DefinitionShapes(theory.ChordDefs[0], nil); got != nil {
+		t.Errorf("expected no shapes without root, got %v", got)
+	}
+}
+
+func intsEqual(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
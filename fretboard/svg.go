@@ -0,0 +1,123 @@
+package fretboard
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/go-audio/music/theory"
+)
+
+// SVG layout, in pixels
+const (
+	svgStringSpacing = 20
+	svgFretSpacing   = 24
+	svgMargin        = 20
+	svgNoteRadius    = 7
+	svgNeckFretWidth = 40
+)
+
+// SVG draws the shape as a chord diagram using the same layout as Diagram.
+// The title, such as the chord name, is drawn above the diagram when set.
+func (s Shape) SVG(title string) string {
+	n := len(s.Frets)
+	first, frets := s.diagramRange()
+	top := svgMargin + 16
+	if title != "" {
+		top += 20
+	}
+	width := 2*svgMargin + (n-1)*svgStringSpacing + 30
+	height := top + frets*svgFretSpacing + svgMargin
+	x := func(str int) int { return svgMargin + str*svgStringSpacing }
+	y := func(fret int) int { return top + (fret-first)*svgFretSpacing }
+
+	b := &strings.Builder{}
+	svgHeader(b, width, height)
+	if title != "" {
+		fmt.Fprintf(b, `<text x="%d" y="%d" text-anchor="middle" font-size="16">%s</text>`+"\n",
+			x(0)+(n-1)*svgStringSpacing/2, svgMargin+6, html.EscapeString(title))
+	}
+	// strings and frets
+	for str := 0; str < n; str++ {
+		fmt.Fprintf(b, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="black"/>`+"\n", x(str), y(first), x(str), y(first+frets))
+	}
+	for fret := first; fret <= first+frets; fret++ {
+		strokeWidth := 1
+		if fret == first && first == 1 {
+			strokeWidth = 4
+		}
+		fmt.Fprintf(b, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="black" stroke-width="%d"/>`+"\n", x(0), y(fret), x(n-1), y(fret), strokeWidth)
+	}
+	if first > 1 {
+		fmt.Fprintf(b, `<text x="%d" y="%d" font-size="12">%dfr</text>`+"\n", x(n-1)+8, y(first)+svgFretSpacing/2+4, first)
+	}
+	// notes, open and muted strings
+	for str, fret := range s.Frets {
+		switch {
+		case fret == Muted:
+			fmt.Fprintf(b, `<text x="%d" y="%d" text-anchor="middle" font-size="14">×</text>`+"\n", x(str), y(first)-6)
+		case fret == 0:
+			fmt.Fprintf(b, `<circle cx="%d" cy="%d" r="5" fill="none" stroke="black"/>`+"\n", x(str), y(first)-10)
+		default:
+			fmt.Fprintf(b, `<circle cx="%d" cy="%d" r="%d" fill="black"/>`+"\n", x(str), y(fret)-svgFretSpacing/2, svgNoteRadius)
+		}
+	}
+	b.WriteString("</svg>\n")
+	return b.String()
+}
+
+// ScaleSVG draws the notes of the scale between two frets (inclusive) using
+// the same layout as ScaleDiagram, notes are labeled with their names and
+// roots are highlighted.
+func (f *Fretboard) ScaleSVG(s *theory.Scale, from, to int) string {
+	return f.neckSVG(f.scaleMarks(s, from, to), from, to)
+}
+
+// neckSVG draws the marks on the neck between two frets.
+func (f *Fretboard) neckSVG(marks map[Position]mark, from, to int) string {
+	n := f.Strings()
+	left := svgMargin + 20
+	width := left + (to-from+1)*svgNeckFretWidth + svgMargin
+	height := 2*svgMargin + (n-1)*svgStringSpacing + 20
+	// the highest string is on top
+	y := func(str int) int { return svgMargin + (n-1-str)*svgStringSpacing }
+	// x returns the right edge of a fret, notes are drawn in its middle
+	x := func(fret int) int { return left + (fret-from+1)*svgNeckFretWidth }
+
+	b := &strings.Builder{}
+	svgHeader(b, width, height)
+	for str := 0; str < n; str++ {
+		fmt.Fprintf(b, `<text x="%d" y="%d" text-anchor="end" font-size="12">%s</text>`+"\n",
+			svgMargin+10, y(str)+4, theory.NoteName(f.Key(str, 0), theory.SharpSpelling))
+		fmt.Fprintf(b, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="black"/>`+"\n", left, y(str), x(to), y(str))
+	}
+	for fret := from; fret <= to; fret++ {
+		strokeWidth := 1
+		if fret == 0 {
+			strokeWidth = 4
+		}
+		fmt.Fprintf(b, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="black" stroke-width="%d"/>`+"\n", x(fret), y(n-1), x(fret), y(0), strokeWidth)
+		fmt.Fprintf(b, `<text x="%d" y="%d" text-anchor="middle" font-size="12">%d</text>`+"\n", x(fret)-svgNeckFretWidth/2, y(0)+24, fret)
+	}
+	for str := n - 1; str >= 0; str-- {
+		for fret := from; fret <= to; fret++ {
+			m, ok := marks[Position{String: str, Fret: fret}]
+			if !ok {
+				continue
+			}
+			fill := "black"
+			if m.root {
+				fill = "firebrick"
+			}
+			cx, cy := x(fret)-svgNeckFretWidth/2, y(str)
+			fmt.Fprintf(b, `<circle cx="%d" cy="%d" r="%d" fill="%s"/>`+"\n", cx, cy, svgNoteRadius+1, fill)
+			fmt.Fprintf(b, `<text x="%d" y="%d" text-anchor="middle" font-size="9" fill="white">%s</text>`+"\n", cx, cy+3, html.EscapeString(m.label))
+		}
+	}
+	b.WriteString("</svg>\n")
+	return b.String()
+}
+
+func svgHeader(b *strings.Builder, width, height int) {
+	fmt.Fprintf(b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif">`+"\n", width, height, width, height)
+}
@@ -0,0 +1,59 @@
+package fretboard
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/go-audio/music/theory"
+)
+
+// svgElements counts the elements of an SVG document by name.
+func svgElements(t *testing.T, svg string) map[string]int {
+	counts := map[string]int{}
+	d := xml.NewDecoder(strings.NewReader(svg))
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			return counts
+		}
+		if err != nil {
+			t.Fatalf("invalid SVG: %s\n%s", err, svg)
+		}
+		if el, ok := tok.(xml.StartElement); ok {
+			counts[el.Name.Local]++
+		}
+	}
+}
+
+func TestShape_SVG(t *testing.T) {
+	s, _ := ParseShape("x32010")
+	svg := s.SVG("C <major>")
+	got := svgElements(t, svg)
+	// 3 notes and 2 open strings, 6 strings and 5 frets
+	if got["svg"] != 1 || got["circle"] != 5 || got["line"] != 11 || got["text"] != 2 {
+		t.Errorf("unexpected elements %v", got)
+	}
+	if !strings.Contains(svg, "C &lt;major&gt;") {
+		t.Errorf("expected an escaped title:\n%s", svg)
+	}
+	s, _ = ParseShape("x-x-10-10-10-8")
+	if svg := s.SVG(""); !strings.Contains(svg, ">8fr</text>") {
+		t.Errorf("expected a fret label:\n%s", svg)
+	}
+}
+
+func TestFretboard_ScaleSVG(t *testing.T) {
+	f := New(StandardTuning, 15)
+	s := &theory.Scale{Root: 9, Def: theory.ScaleDefMap[theory.MinorPentatonicScale]}
+	svg := f.ScaleSVG(s, 5, 8)
+	got := svgElements(t, svg)
+	// 12 notes labeled, string names and fret numbers
+	if got["circle"] != 12 || got["text"] != 12+6+4 {
+		t.Errorf("unexpected elements %v", got)
+	}
+	if n := strings.Count(svg, `fill="firebrick"`); n != 3 {
+		t.Errorf("expected 3 roots, got %d", n)
+	}
+}
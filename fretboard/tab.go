@@ -0,0 +1,128 @@
+package fretboard
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-audio/music/theory"
+)
+
+var (
+	// ErrInvalidTab is returned when parsing a fingering that isn't written
+	// using the tab notation.
+	ErrInvalidTab = errors.New("invalid tab fingering")
+	// ErrStringCount is returned when a fingering doesn't have a fret for
+	// each string of the instrument.
+	ErrStringCount = errors.New("fingering doesn't match the number of strings")
+)
+
+// ParseShape parses a fingering written using the tab notation, from the
+// lowest string: x32010, or x-10-12-12-12-10 (dashes, spaces or commas) when
+// frets above 9 are used. Muted strings are marked with x.
+func ParseShape(tab string) (Shape, error) {
+	tab = strings.TrimSpace(tab)
+	if tab == "" {
+		return Shape{}, ErrInvalidTab
+	}
+	var parts []string
+	if strings.ContainsAny(tab, "- ,") {
+		parts = strings.FieldsFunc(tab, func(r rune) bool {
+			return r == '-' || r == ' ' || r == ','
+		})
+	} else {
+		parts = strings.Split(tab, "")
+	}
+	s := Shape{Frets: make([]int, len(parts))}
+	for i, p := range parts {
+		if p == "x" || p == "X" {
+			s.Frets[i] = Muted
+			continue
+		}
+		fret, err := strconv.Atoi(p)
+		if err != nil || fret < 0 {
+			return Shape{}, fmt.Errorf("%s - %q", ErrInvalidTab, tab)
+		}
+		s.Frets[i] = fret
+	}
+	return s, nil
+}
+
+// Identify returns the chord sounding when playing the tab fingering on the
+// fretboard, with its keys sorted by pitch, and its lead sheet symbol using
+// a slash for the bass of inversions (C/E). The symbol is empty if the chord
+// isn't identified.
+func (f *Fretboard) Identify(tab string) (*theory.Chord, string, error) {
+	s, err := ParseShape(tab)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(s.Frets) != f.Strings() {
+		return nil, "", fmt.Errorf("%s - %d frets for %d strings", ErrStringCount, len(s.Frets), f.Strings())
+	}
+	c := s.Chord(f)
+	c.Spelling = s.identified(f).Spelling
+	return c, s.Name(f), nil
+}
+
+// Name returns the lead sheet symbol of the chord played by the shape, an
+// empty string if the chord isn't identified.
+func (s Shape) Name(f *Fretboard) string {
+	return s.identified(f).Symbol()
+}
+
+// Def returns the definition of the chord played by the shape.
+func (s Shape) Def(f *Fretboard) *theory.ChordDefinition {
+	return s.identified(f).Copy().Def()
+}
+
+// identified returns the chord played by the shape in closed position from
+// its bass, each pitch class once, so the definition rooted on the bass is
+// preferred (Am7 rather than C6/A). When the chord isn't identified, the
+// omitted fifth of a root is added.
+func (s Shape) identified(f *Fretboard) *theory.Chord {
+	keys := s.Keys(f)
+	if len(keys) == 0 {
+		return &theory.Chord{}
+	}
+	sort.Ints(keys)
+	bass := keys[0]
+	closed := []int{bass}
+	for _, k := range keys[1:] {
+		k = bass + mod12(k-bass)
+		if !includes(closed, k) {
+			closed = append(closed, k)
+		}
+	}
+	sort.Ints(closed)
+	c := withSpelling(&theory.Chord{Keys: closed})
+	if c.Copy().Def().RootInt() >= 0 || len(closed) < 3 {
+		return c
+	}
+	for _, root := range closed {
+		fifth := bass + mod12(root+7-bass)
+		if includes(closed, fifth) {
+			continue
+		}
+		complete := append(append([]int(nil), closed...), fifth)
+		sort.Ints(complete)
+		candidate := withSpelling(&theory.Chord{Keys: complete})
+		if candidate.Copy().Def().RootInt() == mod12(root) {
+			return candidate
+		}
+	}
+	return c
+}
+
+// withSpelling sets the spelling of the key implied by the chord (see
+// theory.Chord.KeySpelling).
+func withSpelling(c *theory.Chord) *theory.Chord {
+	c.Spelling = c.KeySpelling()
+	return c
+}
+
+func mod12(n int) int {
+	return ((n % 12) + 12) % 12
+}
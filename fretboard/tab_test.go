@@ -0,0 +1,80 @@
+package fretboard
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseShape(t *testing.T) {
+	tests := []struct {
+		tab     string
+		want    []int
+		wantErr bool
+	}{
+		{"x32010", []int{-1, 3, 2, 0, 1, 0}, false},
+		{"X-10-12-12-12-10", []int{-1, 10, 12, 12, 12, 10}, false},
+		{"8 10 10 9 8 8", []int{8, 10, 10, 9, 8, 8}, false},
+		{"x,x,0,2,3,2", []int{-1, -1, 0, 2, 3, 2}, false},
+		{"", nil, true},
+		{"x3201o", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tab, func(t *testing.T) {
+			s, err := ParseShape(tt.tab)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(s.Frets, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, s.Frets)
+			}
+		})
+	}
+}
+
+func TestFretboard_Identify(t *testing.T) {
+	guitar := New(StandardTuning, 22)
+	capo := New(StandardTuning, 22)
+	capo.Capo = 3
+	tests := []struct {
+		name string
+		f    *Fretboard
+		tab  string
+		keys []int
+		want string
+	}{
+		{"open C", guitar, "x32010", []int{48, 52, 55, 60, 64}, "C"},
+		{"first inversion", guitar, "032010", []int{40, 48, 52, 55, 60, 64}, "C/E"},
+		{"G over B", guitar, "x20003", []int{47, 50, 55, 59, 67}, "G/B"},
+		{"minor seventh rather than sixth", guitar, "x02010", []int{45, 52, 55, 60, 64}, "Am7"},
+		{"seventh without fifth", guitar, "x32310", []int{48, 52, 58, 60, 64}, "C7"},
+		{"sus4", guitar, "xx0233", []int{50, 57, 62, 67}, "Dsus4"},
+		{"flat root", guitar, "x13331", []int{46, 53, 58, 62, 65}, "Bb"},
+		{"flat major key", guitar, "466544", []int{44, 51, 56, 60, 63, 68}, "Ab"},
+		{"sharp minor key", guitar, "466444", []int{44, 51, 56, 59, 63, 68}, "G#m"},
+		{"high frets", guitar, "x-x-10-10-10-8", []int{60, 65, 69, 72}, "F/C"},
+		{"power chord", guitar, "x355xx", []int{48, 55, 60}, "C5"},
+		{"capo", capo, "022000", []int{43, 50, 55, 58, 62, 67}, "Gm"},
+		{"unknown", guitar, "x01000", []int{45, 51, 55, 59, 64}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, name, err := tt.f.Identify(tt.tab)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(c.Keys, tt.keys) {
+				t.Errorf("expected keys %v, got %v", tt.keys, c.Keys)
+			}
+			if name != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, name)
+			}
+		})
+	}
+	if _, _, err := guitar.Identify("x3201"); err == nil {
+		t.Error("expected an error for a missing string")
+	}
+	s, _ := ParseShape("xx0212")
+	if got := s.Def(guitar).String(); got != "D Seventh" {
+		t.Errorf("expected D Seventh, got %s", got)
+	}
+}
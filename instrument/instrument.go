@@ -0,0 +1,175 @@
+// Package instrument defines the ranges and transpositions of common
+// instruments and converts between concert (sounding) and written pitches.
+package instrument
+
+import (
+	"strings"
+
+	"github.com/go-audio/midi"
+	"github.com/go-audio/music/theory"
+)
+
+// Instrument describes the range of an instrument and how its part is
+// written. Keys are MIDI keys, octaves passed to midi.KeyInt follow the midi
+// package numbering (middle C is midi.KeyInt("C", 3), C4 in scientific pitch
+// notation).
+type Instrument struct {
+	Name string
+	// Low and High are the lowest and highest sounding keys.
+	Low, High int
+	// Transposition is the interval from the written pitch to the sounding
+	// pitch: a major second down for a Bb trumpet, an octave down for a
+	// guitar.
+	Transposition theory.Interval
+	// Polyphony is the maximum number of notes played at once.
+	Polyphony int
+}
+
+var (
+	// Piano covers the 88 keys from A0 to C8.
+	Piano = &Instrument{Name: "Piano", Low: midi.KeyInt("A", -1), High: midi.KeyInt("C", 7), Polyphony: 10}
+	// Guitar sounds an octave below the written part, E2 to E6 on a 24 frets
+	// guitar.
+	Guitar = &Instrument{Name: "Guitar", Low: midi.KeyInt("E", 1), High: midi.KeyInt("E", 5),
+		Transposition: theory.PerfectOctave.Down(), Polyphony: 6}
+	// Bass is the 4 strings electric bass, sounding an octave below the
+	// written part, E1 to G4.
+	Bass = &Instrument{Name: "Bass", Low: midi.KeyInt("E", 0), High: midi.KeyInt("G", 3),
+		Transposition: theory.PerfectOctave.Down(), Polyphony: 4}
+	// BbTrumpet sounds a major second below the written part, E3 to Bb5.
+	BbTrumpet = &Instrument{Name: "Bb Trumpet", Low: midi.KeyInt("E", 2), High: midi.KeyInt("A#", 4),
+		Transposition: theory.MajorSecond.Down(), Polyphony: 1}
+	// BbClarinet sounds a major second below the written part, D3 to Bb6.
+	BbClarinet = &Instrument{Name: "Bb Clarinet", Low: midi.KeyInt("D", 2), High: midi.KeyInt("A#", 5),
+		Transposition: theory.MajorSecond.Down(), Polyphony: 1}
+	// EbAltoSax sounds a major sixth below the written part, Db3 to Ab5.
+	EbAltoSax = &Instrument{Name: "Eb Alto Sax", Low: midi.KeyInt("C#", 2), High: midi.KeyInt("G#", 4),
+		Transposition: theory.MajorSixth.Down(), Polyphony: 1}
+	// BbTenorSax sounds a major ninth below the written part, Ab2 to E5.
+	BbTenorSax = &Instrument{Name: "Bb Tenor Sax", Low: midi.KeyInt("G#", 1), High: midi.KeyInt("E", 4),
+		Transposition: theory.PerfectOctave.Add(theory.MajorSecond).Down(), Polyphony: 1}
+	// FHorn sounds a perfect fifth below the written part, B1 to F5.
+	FHorn = &Instrument{Name: "F Horn", Low: midi.KeyInt("B", 0), High: midi.KeyInt("F", 4),
+		Transposition: theory.PerfectFifth.Down(), Polyphony: 1}
+	// Flute ranges from C4 to D7.
+	Flute = &Instrument{Name: "Flute", Low: midi.KeyInt("C", 3), High: midi.KeyInt("D", 6), Polyphony: 1}
+	// Violin ranges from G3 to E7, double stops allow playing two notes.
+	Violin = &Instrument{Name: "Violin", Low: midi.KeyInt("G", 2), High: midi.KeyInt("E", 6), Polyphony: 2}
+	// Viola ranges from C3 to E6.
+	Viola = &Instrument{Name: "Viola", Low: midi.KeyInt("C", 2), High: midi.KeyInt("E", 5), Polyphony: 2}
+	// Cello ranges from C2 to A5.
+	Cello = &Instrument{Name: "Cello", Low: midi.KeyInt("C", 1), High: midi.KeyInt("A", 4), Polyphony: 2}
+	// DoubleBass sounds an octave below the written part, E1 to G4.
+	DoubleBass = &Instrument{Name: "Double Bass", Low: midi.KeyInt("E", 0), High: midi.KeyInt("G", 3),
+		Transposition: theory.PerfectOctave.Down(), Polyphony: 2}
+
+	// Instruments lists all the known instruments.
+	Instruments = []*Instrument{
+		Piano, Guitar, Bass,
+		BbTrumpet, BbClarinet, EbAltoSax, BbTenorSax, FHorn, Flute,
+		Violin, Viola, Cello, DoubleBass,
+	}
+)
+
+// Find returns the instrument with the passed name, ignoring case.
+func Find(name string) (*Instrument, bool) {
+	for _, i := range Instruments {
+		if strings.EqualFold(i.Name, name) {
+			return i, true
+		}
+	}
+	return nil, false
+}
+
+// IsTransposing reports whether the part of the instrument is written at a
+// different pitch than it sounds. Instruments sounding an octave away from
+// their part (guitar, bass) are transposing.
+func (i *Instrument) IsTransposing() bool {
+	return i.Transposition.HalfSteps != 0
+}
+
+// InRange reports whether the sounding key can be played by the instrument.
+func (i *Instrument) InRange(key int) bool {
+	return key >= i.Low && key <= i.High
+}
+
+// OutOfRange returns the sounding keys the instrument can't play.
+func (i *Instrument) OutOfRange(keys []int) []int {
+	var out []int
+	for _, k := range keys {
+		if !i.InRange(k) {
+			out = append(out, k)
+		}
+	}
+	return out
+}
+
+// Fits reports whether the instrument can play the sounding keys at once:
+// they are all in range and there are no more than its polyphony.
+func (i *Instrument) Fits(keys []int) bool {
+	return len(keys) <= i.Polyphony && len(i.OutOfRange(keys)) == 0
+}
+
+// FitsChord reports whether the instrument can play the chord voicing.
+func (i *Instrument) FitsChord(c *theory.Chord) bool {
+	return c != nil && i.Fits(c.Keys)
+}
+
+// Written converts a concert key to the key written in the part.
+func (i *Instrument) Written(key int) int {
+	return key - i.Transposition.HalfSteps
+}
+
+// Sounding converts a key written in the part to the concert key.
+func (i *Instrument) Sounding(written int) int {
+	return written + i.Transposition.HalfSteps
+}
+
+// WrittenRange returns the lowest and highest keys written in the part.
+func (i *Instrument) WrittenRange() (low, high int) {
+	return i.Written(i.Low), i.Written(i.High)
+}
+
+// WrittenNoteName returns the name of the note written in the part for the
+// passed concert note name, keeping the spelling consistent with the
+// transposition: a concert Bb is written C for a Bb trumpet, a concert Eb is
+// written C for an Eb alto sax.
+func (i *Instrument) WrittenNoteName(name string) string {
+	return theory.TransposeNoteName(name, i.Transposition.Down())
+}
+
+// SoundingNoteName returns the concert name of a note written in the part.
+func (i *Instrument) SoundingNoteName(name string) string {
+	return theory.TransposeNoteName(name, i.Transposition)
+}
+
+// WrittenChord returns the chord as written in the part.
+func (i *Instrument) WrittenChord(c *theory.Chord) *theory.Chord {
+	return c.TransposeInterval(i.Transposition.Down())
+}
+
+// SoundingChord returns the concert chord of a chord written in the part.
+func (i *Instrument) SoundingChord(written *theory.Chord) *theory.Chord {
+	return written.TransposeInterval(i.Transposition)
+}
+
+// WrittenKey returns the key (scale) of the part for a concert key: a piece
+// in concert Bb major is written in C major for a Bb trumpet.
+func (i *Instrument) WrittenKey(s *theory.Scale) *theory.Scale {
+	if s == nil {
+		return nil
+	}
+	return &theory.Scale{Root: mod12(s.Root - i.Transposition.HalfSteps), Def: s.Def}
+}
+
+// SoundingKey returns the concert key of a part written in the passed key.
+func (i *Instrument) SoundingKey(written *theory.Scale) *theory.Scale {
+	if written == nil {
+		return nil
+	}
+	return &theory.Scale{Root: mod12(written.Root + i.Transposition.HalfSteps), Def: written.Def}
+}
+
+func mod12(n int) int {
+	return ((n % 12) + 12) % 12
+}
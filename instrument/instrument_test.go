@@ -0,0 +1,128 @@
+package instrument
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-audio/midi"
+	"github.com/go-audio/music/theory"
+)
+
+func TestInstrument_ranges(t *testing.T) {
+	tests := []struct {
+		inst      *Instrument
+		low, high int
+		// written range
+		wLow, wHigh int
+	}{
+		{Piano, 21, 108, 21, 108},
+		{Guitar, 40, 88, 52, 100},
+		{BbTrumpet, 52, 82, 54, 84},
+		{EbAltoSax, 49, 80, 58, 89},
+		{BbTenorSax, 44, 76, 58, 90},
+		{FHorn, 35, 77, 42, 84},
+		{DoubleBass, 28, 67, 40, 79},
+	}
+	for _, tt := range tests {
+		t.Run(tt.inst.Name, func(t *testing.T) {
+			if tt.inst.Low != tt.low || tt.inst.High != tt.high {
+				t.Errorf("expected sounding range %d-%d, got %d-%d", tt.low, tt.high, tt.inst.Low, tt.inst.High)
+			}
+			if low, high := tt.inst.WrittenRange(); low != tt.wLow || high != tt.wHigh {
+				t.Errorf("expected written range %d-%d, got %d-%d", tt.wLow, tt.wHigh, low, high)
+			}
+			if got := tt.inst.Sounding(tt.inst.Written(60)); got != 60 {
+				t.Errorf("expected the conversion to round trip, got %d", got)
+			}
+		})
+	}
+	if Piano.IsTransposing() || !Guitar.IsTransposing() || !FHorn.IsTransposing() {
+		t.Error("unexpected transposing instruments")
+	}
+	if i, ok := Find("eb alto sax"); !ok || i != EbAltoSax {
+		t.Errorf("expected to find the alto sax, got %v", i)
+	}
+	if _, ok := Find("theremin"); ok {
+		t.Error("didn't expect to find a theremin")
+	}
+}
+
+func TestInstrument_Fits(t *testing.T) {
+	cmaj := theory.NewChordFromAbbrev("Cmaj").Transpose(36)
+	tests := []struct {
+		name  string
+		inst  *Instrument
+		keys  []int
+		fits  bool
+		out   []int
+		chord bool
+	}{
+		{"piano chord", Piano, cmaj.Keys, true, nil, true},
+		{"trumpet can't play chords", BbTrumpet, cmaj.Keys, false, nil, true},
+		{"trumpet note", BbTrumpet, []int{midi.KeyInt("G", 3)}, true, nil, false},
+		{"too low for the trumpet", BbTrumpet, []int{midi.KeyInt("C", 2)}, false, []int{48}, false},
+		{"violin double stop", Violin, []int{55, 62}, true, nil, false},
+		{"bass out of range", Bass, []int{26, 40, 70}, false, []int{26, 70}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.inst.Fits(tt.keys); got != tt.fits {
+				t.Errorf("expected Fits to be %t", tt.fits)
+			}
+			if got := tt.inst.OutOfRange(tt.keys); !reflect.DeepEqual(got, tt.out) {
+				t.Errorf("expected out of range keys %v, got %v", tt.out, got)
+			}
+			if tt.chord {
+				if got := tt.inst.FitsChord(&theory.Chord{Keys: tt.keys}); got != tt.fits {
+					t.Errorf("expected FitsChord to be %t", tt.fits)
+				}
+			}
+		})
+	}
+	if Piano.FitsChord(nil) {
+		t.Error("a nil chord doesn't fit")
+	}
+}
+
+func TestInstrument_Written(t *testing.T) {
+	tests := []struct {
+		inst             *Instrument
+		concert, written string
+		chord, wChord    string
+		key, wKey        string
+	}{
+		{BbTrumpet, "Bb", "C", "Bbmaj", "Cmaj", "Bb", "C"},
+		{BbClarinet, "F#", "G#", "F#min", "G#min", "A", "B"},
+		{EbAltoSax, "Eb", "C", "Ebmaj", "Cmaj", "Eb", "C"},
+		{EbAltoSax, "C", "A", "C7", "A7", "F", "D"},
+		{FHorn, "F", "C", "Dmaj", "Amaj", "Bb", "F"},
+		{FHorn, "Ab", "Eb", "Abmaj", "Ebmaj", "Db", "Ab"},
+		{Guitar, "E", "E", "Emaj", "Emaj", "E", "E"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.inst.Name+" "+tt.concert, func(t *testing.T) {
+			if got := tt.inst.WrittenNoteName(tt.concert); got != tt.written {
+				t.Errorf("expected %s to be written %s, got %s", tt.concert, tt.written, got)
+			}
+			if got := tt.inst.SoundingNoteName(tt.written); got != tt.concert {
+				t.Errorf("expected %s to sound %s, got %s", tt.written, tt.concert, got)
+			}
+			c := theory.NewChordFromAbbrev(tt.chord).Transpose(36)
+			written := tt.inst.WrittenChord(c)
+			if got := written.Copy().Def().Root + written.Copy().Def().Abbrev; got != tt.wChord {
+				t.Errorf("expected %s to be written %s, got %s", tt.chord, tt.wChord, got)
+			}
+			if !reflect.DeepEqual(tt.inst.SoundingChord(written).Keys, c.Keys) {
+				t.Errorf("expected the chord to round trip")
+			}
+			s := &theory.Scale{Root: midi.KeyInt(tt.key, 0), Def: theory.ScaleDefMap[theory.MajorScale]}
+			wKey := tt.inst.WrittenKey(s)
+			if got := wKey.NoteNames()[0]; got != tt.wKey {
+				t.Errorf("expected %s major to be written %s major, got %s", tt.key, tt.wKey, got)
+			}
+			if got := tt.inst.SoundingKey(wKey); got.Root != s.Root%12 {
+				t.Errorf("expected the key to round trip, got %v", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+// Package notation holds what the text notation packages (abc, lilypond)
+// share: the meter of the rendered music, the spelling of the notes and the
+// slicing of the notes at bar lines so each package only writes its syntax.
+package notation
+
+import (
+	"sort"
+
+	"github.com/go-audio/music/theory"
+)
+
+// Meter is the resolution and time signature of the rendered music.
+type Meter struct {
+	// TicksPerQuarter is the resolution of the timed events (96 by default).
+	TicksPerQuarter int
+	// TimeSignature is the numerator and denominator of the time signature
+	// (4/4 when not set).
+	TimeSignature [2]int
+}
+
+// Ticks returns the number of ticks per quarter note.
+func (m Meter) Ticks() int {
+	if m.TicksPerQuarter <= 0 {
+		return 96
+	}
+	return m.TicksPerQuarter
+}
+
+// Signature returns the numerator and denominator of the time signature.
+func (m Meter) Signature() (int, int) {
+	if m.TimeSignature[0] <= 0 || m.TimeSignature[1] <= 0 {
+		return 4, 4
+	}
+	return m.TimeSignature[0], m.TimeSignature[1]
+}
+
+// MeasureLength returns the length of a measure in ticks.
+func (m Meter) MeasureLength() int {
+	beats, beatType := m.Signature()
+	return beats * m.Ticks() * 4 / beatType
+}
+
+// Note is a timed note with its spelled name.
+type Note struct {
+	theory.NoteEvent
+	Name string
+}
+
+// SpellNotes names the notes using the note names of the scale (see
+// theory.Scale.SpellKey).
+func SpellNotes(notes []theory.NoteEvent, s *theory.Scale) []Note {
+	spelled := make([]Note, len(notes))
+	for i, n := range notes {
+		spelled[i] = Note{NoteEvent: n, Name: s.SpellKey(n.Key)}
+	}
+	return spelled
+}
+
+// ChordNotes returns the notes playing the chord spans using their voicing,
+// spelled relative to each chord root. Spans without a chord are silent.
+func ChordNotes(spans theory.ChordSpans) []Note {
+	notes := []Note{}
+	for _, span := range spans {
+		if span.Chord == nil {
+			continue
+		}
+		names := span.Chord.NoteNames()
+		for i, k := range span.Chord.Keys {
+			notes = append(notes, Note{
+				NoteEvent: theory.NoteEvent{Key: k, Start: span.Start, Duration: span.Duration},
+				Name:      names[i],
+			})
+		}
+	}
+	return notes
+}
+
+// Slice is a period during which the same notes sound.
+type Slice struct {
+	From, To int
+	// Bar is set when the slice starts a measure, except for the first one.
+	Bar bool
+	// Notes are the sounding notes, lowest first, notes doubling a key are
+	// dropped.
+	Notes []Note
+}
+
+// Slices cuts the notes wherever a note starts or stops, at the bar lines and
+// at the passed marks (such as chord symbols). Notes sounding over several
+// slices are meant to be tied.
+func (m Meter) Slices(notes []Note, marks ...int) []Slice {
+	measure := m.MeasureLength()
+	var end int
+	for _, n := range notes {
+		if n.End() > end {
+			end = n.End()
+		}
+	}
+	bounds := map[int]bool{0: true, end: true}
+	for _, n := range notes {
+		bounds[n.Start] = true
+		bounds[n.End()] = true
+	}
+	for _, tick := range marks {
+		if tick < end {
+			bounds[tick] = true
+		}
+	}
+	for t := measure; t < end; t += measure {
+		bounds[t] = true
+	}
+	ticks := []int{}
+	for t := range bounds {
+		ticks = append(ticks, t)
+	}
+	sort.Ints(ticks)
+
+	slices := make([]Slice, 0, len(ticks))
+	for i := 0; i+1 < len(ticks); i++ {
+		s := Slice{From: ticks[i], To: ticks[i+1]}
+		s.Bar = s.From > 0 && s.From%measure == 0
+		seen := map[int]bool{}
+		for _, n := range notes {
+			if n.Start <= s.From && n.End() > s.From && n.Duration > 0 && !seen[n.Key] {
+				seen[n.Key] = true
+				s.Notes = append(s.Notes, n)
+			}
+		}
+		sort.SliceStable(s.Notes, func(i, j int) bool { return s.Notes[i].Key < s.Notes[j].Key })
+		slices = append(slices, s)
+	}
+	return slices
+}
+
+// Split returns the lengths (in 64th notes, picked from the passed lengths
+// sorted longest first) of the notes to tie to express a duration in ticks.
+// Durations shorter than a 64th note are dropped.
+func Split(ticks, ticksPerQuarter int, lengths []int) []int {
+	length := ticks * 16 / ticksPerQuarter
+	values := []int{}
+	for _, l := range lengths {
+		for length >= l {
+			values = append(values, l)
+			length -= l
+		}
+	}
+	return values
+}
@@ -0,0 +1,72 @@
+package notation
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-audio/midi"
+	"github.com/go-audio/music/theory"
+)
+
+func TestMeter_Slices(t *testing.T) {
+	c, e := midi.KeyInt("C", 3), midi.KeyInt("E", 3)
+	notes := []Note{
+		// a half note crossing the bar line in 3/4
+		{NoteEvent: theory.NoteEvent{Key: c, Start: 192, Duration: 192}, Name: "C"},
+		{NoteEvent: theory.NoteEvent{Key: e, Start: 288, Duration: 96}, Name: "E"},
+		// doubled key
+		{NoteEvent: theory.NoteEvent{Key: e, Start: 288, Duration: 96}, Name: "E"},
+	}
+	m := Meter{TimeSignature: [2]int{3, 4}}
+	type slice struct {
+		from, to int
+		bar      bool
+		keys     []int
+	}
+	want := []slice{
+		{0, 192, false, nil},
+		{192, 288, false, []int{c}},
+		{288, 384, true, []int{c, e}},
+	}
+	var got []slice
+	for _, s := range m.Slices(notes) {
+		var keys []int
+		for _, n := range s.Notes {
+			keys = append(keys, n.Key)
+		}
+		got = append(got, slice{s.From, s.To, s.Bar, keys})
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	// marks cut the notes
+	if got := len(m.Slices(notes, 240)); got != 4 {
+		t.Errorf("expected 4 slices with a mark, got %d", got)
+	}
+}
+
+func TestSplit(t *testing.T) {
+	lengths := []int{64, 32, 16, 8, 4, 2, 1}
+	// a dotted half note
+	if got := Split(288, 96, lengths); !reflect.DeepEqual(got, []int{32, 16}) {
+		t.Errorf("expected [32 16], got %v", got)
+	}
+	if got := Split(2, 96, lengths); len(got) != 0 {
+		t.Errorf("expected durations shorter than a 64th note to be dropped, got %v", got)
+	}
+}
+
+func TestChordNotes(t *testing.T) {
+	spans := theory.ChordSpans{
+		{Chord: nil, Start: 0, Duration: 96},
+		{Chord: &theory.Chord{Keys: []int{58, 62, 65}, Spelling: theory.FlatSpelling}, Start: 96, Duration: 96},
+	}
+	var names []string
+	for _, n := range ChordNotes(spans) {
+		names = append(names, n.Name)
+	}
+	if want := []string{"Bb", "D", "F"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("expected %v, got %v", want, names)
+	}
+}
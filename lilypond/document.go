@@ -0,0 +1,40 @@
+package lilypond
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-audio/music/theory"
+)
+
+// Document is a LilyPond file showing chord names above a staff playing the
+// chords and/or a melody.
+type Document struct {
+	Title string
+	Options
+	// Chords are shown as chord names and played on their own staff.
+	Chords theory.ChordSpans
+	// Melody is shown on a staff above the chords.
+	Melody []theory.NoteEvent
+}
+
+// String returns the LilyPond source of the document.
+func (d *Document) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "\\version %q\n\n", Version)
+	if d.Title != "" {
+		fmt.Fprintf(&b, "\\header {\n  title = %q\n}\n\n", d.Title)
+	}
+	b.WriteString("\\score {\n  <<\n")
+	if len(d.Chords) > 0 {
+		fmt.Fprintf(&b, "    \\new ChordNames %s\n", ChordMode(d.Chords, d.Options))
+	}
+	if len(d.Melody) > 0 {
+		fmt.Fprintf(&b, "    %s\n", NoteStaff(d.Melody, d.Options))
+	}
+	if len(d.Chords) > 0 {
+		fmt.Fprintf(&b, "    %s\n", ChordStaff(d.Chords, d.Options))
+	}
+	b.WriteString("  >>\n  \\layout { }\n}\n")
+	return b.String()
+}
@@ -0,0 +1,164 @@
+// Package lilypond renders chords, notes and scales of the theory package as
+// LilyPond source so they can be engraved.
+package lilypond
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-audio/music/internal/notation"
+	"github.com/go-audio/music/theory"
+)
+
+// Version is the LilyPond version written in the documents.
+const Version = "2.22.0"
+
+// chordModifiers are the \chordmode modifiers of the theory.ChordDefs.
+var chordModifiers = map[string]string{
+	"maj": "", "min": "m", "mb5": "dim", "aug": "aug", "5": "1.5",
+	"m7": "m7", "min7": "m7", "Maj7": "maj7", "7": "7",
+	"sus2": "sus2", "sus4": "sus4", "majb5": "3.5-", "tri": "dim7",
+	"6": "6", "6sus4": "6.4^3", "6add9": "6.9",
+	"m6": "m6", "min6": "m6", "m6add9": "m6.9", "min6add9": "m6.9",
+	"7sus4": "7sus4", "7#5": "7.5+", "7b5": "7.5-", "7#9": "7.9+", "7b9": "7.9-",
+	"7#5#9": "7.5+.9+", "7#5b9": "7.5+.9-", "7b5b9": "7.5-.9-",
+	"7add11": "7.11", "7add13": "7.13", "7#11": "7.11+",
+	"Maj7b5": "maj7.5-", "Maj7#5": "maj7.5+", "Maj7#11": "maj7.11+", "Maj7add13": "maj7.13",
+	"m7b5": "m7.5-", "m7b9": "m7.9-", "m7add11": "m7.11", "m7add13": "m7.13",
+	"m-Maj7": "m7+", "m-Maj7add11": "m7+.11", "m-Maj7add13": "m7+.13",
+	"9": "9", "9sus4": "9sus4", "add9": "9^7", "9#5": "9.5+", "9b5": "9.5-",
+	"9#11": "9.11+", "9b13": "9.13-",
+	"Maj9": "maj9", "Maj9sus4": "maj9sus4", "Maj9#5": "maj9.5+", "Maj9#11": "maj9.11+",
+	"m9": "m9", "madd9": "m9^7", "m9b5": "m9.5-", "m9-Maj7": "m7+.9",
+	"11": "11", "11b9": "11.9-", "Maj11": "maj11", "m11": "m11", "m-Maj11": "m7+.9.11",
+	"13": "13", "13#9": "13.9+", "13b9": "13.9-", "13b5b9": "13.5-.9-",
+	"Maj13": "maj13", "min13": "m13", "m-Maj13": "m7+.9.13",
+}
+
+// PitchName returns the LilyPond name of a spelled note (Bb gives bes, F#
+// gives fis).
+func PitchName(name string) string {
+	if name == "" {
+		return ""
+	}
+	letter := strings.ToLower(name[:1])
+	var out strings.Builder
+	out.WriteString(letter)
+	for _, r := range name[1:] {
+		switch r {
+		case '#':
+			out.WriteString("is")
+		case 'b':
+			// es is contracted to s after a vowel (as, es)
+			if out.Len() == 1 && (letter == "a" || letter == "e") {
+				out.WriteString("s")
+			} else {
+				out.WriteString("es")
+			}
+		}
+	}
+	return out.String()
+}
+
+// Pitch returns the absolute LilyPond pitch of a MIDI key spelled using the
+// passed note name, c' being middle C (midi.KeyInt("C", 3)).
+func Pitch(key int, name string) string {
+	// B#2 and C3 are in different octaves even if they sound the same
+	octave := theory.SpelledOctave(key, name) - 2
+	marks := strings.Repeat("'", max(octave, 0)) + strings.Repeat(",", max(-octave, 0))
+	return PitchName(name) + marks
+}
+
+// durations are the LilyPond durations expressed in 64th notes, longest
+// first.
+var durations = map[int]string{
+	64: "1", 48: "2.", 32: "2", 24: "4.", 16: "4", 12: "8.",
+	8: "8", 6: "16.", 4: "16", 2: "32", 1: "64",
+}
+
+// lengths are the keys of durations, longest first.
+var lengths = []int{64, 48, 32, 24, 16, 12, 8, 6, 4, 2, 1}
+
+// Durations returns the LilyPond durations to tie to express a duration in
+// ticks (for instance 5 eighth notes give 2 and 8). Durations shorter than a
+// 64th note are dropped.
+func Durations(ticks, ticksPerQuarter int) []string {
+	values := []string{}
+	for _, l := range notation.Split(ticks, ticksPerQuarter, lengths) {
+		values = append(values, durations[l])
+	}
+	return values
+}
+
+// ChordName returns the \chordmode name of the chord lasting the passed
+// duration (bes1:maj7, c4/e...). Unknown chords are written as a simultaneous
+// group of notes and missing chords as a rest.
+func ChordName(c *theory.Chord, duration string) string {
+	if c == nil || len(c.Keys) == 0 {
+		return "r" + duration
+	}
+	def := c.Copy().Def()
+	modifier, ok := chordModifiers[def.Abbrev]
+	if !ok || def.Root == "" {
+		return chordNotes(c) + duration
+	}
+	name := PitchName(def.Root) + duration
+	if modifier != "" {
+		name += ":" + modifier
+	}
+	if symbol := c.Symbol(); strings.Contains(symbol, "/") {
+		name += "/" + PitchName(symbol[strings.Index(symbol, "/")+1:])
+	}
+	return name
+}
+
+// chordNotes returns the spelled keys of the chord as a group of notes such as
+// <c' e' g'>.
+func chordNotes(c *theory.Chord) string {
+	names := c.NoteNames()
+	order := make([]int, len(c.Keys))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return c.Keys[order[i]] < c.Keys[order[j]] })
+	pitches := make([]string, len(order))
+	for i, idx := range order {
+		pitches[i] = Pitch(c.Keys[idx], names[idx])
+	}
+	return "<" + strings.Join(pitches, " ") + ">"
+}
+
+// keyModes are the LilyPond modes of the scales.
+var keyModes = map[theory.ScaleName]string{
+	theory.MajorScale:        `\major`,
+	theory.NaturalMinorScale: `\minor`,
+	theory.DorianScale:       `\dorian`,
+	theory.PhrygianScale:     `\phrygian`,
+	theory.LydianScale:       `\lydian`,
+	theory.MixolydianScale:   `\mixolydian`,
+	theory.LocrianScale:      `\locrian`,
+}
+
+// Key returns the \key command of a scale (\key d \minor). Scales without a
+// matching LilyPond mode use the major or minor key sharing their key
+// signature.
+func Key(s *theory.Scale) string {
+	mode, ok := keyModes[s.Def.Name]
+	if !ok {
+		name := theory.MajorScale
+		if s.IsMinor() {
+			name = theory.NaturalMinorScale
+		}
+		s = theory.NewScaleFromKeySignature(s.KeySignature(), name)
+		mode = keyModes[name]
+	}
+	return fmt.Sprintf(`\key %s %s`, PitchName(s.NoteNames()[0]), mode)
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
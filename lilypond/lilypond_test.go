@@ -0,0 +1,173 @@
+package lilypond
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-audio/midi"
+	"github.com/go-audio/music/theory"
+)
+
+func TestPitch(t *testing.T) {
+	tests := []struct {
+		key  int
+		name string
+		want string
+	}{
+		{midi.KeyInt("C", 3), "C", "c'"},
+		{midi.KeyInt("A#", 3), "Bb", "bes'"},
+		{midi.KeyInt("D#", 2), "Eb", "es"},
+		{midi.KeyInt("G#", 4), "Ab", "as''"},
+		{midi.KeyInt("F#", 1), "F#", "fis,"},
+		{midi.KeyInt("C", 3), "B#", "bis"},
+		{midi.KeyInt("B", 2), "Cb", "ces'"},
+		{midi.KeyInt("A", 0), "A", "a,,"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := Pitch(tt.key, tt.name); got != tt.want {
+				t.Errorf("Pitch(%d, %s) = %s, want %s", tt.key, tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDurations(t *testing.T) {
+	tests := []struct {
+		ticks int
+		want  []string
+	}{
+		{384, []string{"1"}},
+		{96, []string{"4"}},
+		{144, []string{"4."}},
+		{240, []string{"2", "8"}},
+		{768, []string{"1", "1"}},
+		{12, []string{"32"}},
+	}
+	for _, tt := range tests {
+		if got := Durations(tt.ticks, 96); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Durations(%d, 96) = %v, want %v", tt.ticks, got, tt.want)
+		}
+	}
+}
+
+func TestChordName(t *testing.T) {
+	tests := []struct {
+		name  string
+		chord *theory.Chord
+		want  string
+	}{
+		{"major", theory.NewChordFromAbbrev("Cmaj"), "c2"},
+		{"flat minor seventh", theory.NewChordFromAbbrev("Ebm7"), "es2:m7"},
+		{"seventh flat 9th", theory.NewChordFromAbbrev("A7b9"), "a2:7.9-"},
+		{"inversion", &theory.Chord{Keys: []int{midi.KeyInt("E", 3), midi.KeyInt("G", 3), midi.KeyInt("C", 4)}}, "c2/e"},
+		{"unknown", &theory.Chord{Keys: []int{midi.KeyInt("C", 3), midi.KeyInt("C#", 3)}}, "<c' cis'>2"},
+		{"no chord", nil, "r2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ChordName(tt.chord, "2"); got != tt.want {
+				t.Errorf("ChordName() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKey(t *testing.T) {
+	tests := []struct {
+		scale *theory.Scale
+		want  string
+	}{
+		{&theory.Scale{Root: 5, Def: theory.ScaleDefMap[theory.MajorScale]}, `\key f \major`},
+		{&theory.Scale{Root: 0, Def: theory.ScaleDefMap[theory.NaturalMinorScale]}, `\key c \minor`},
+		{&theory.Scale{Root: 2, Def: theory.ScaleDefMap[theory.DorianScale]}, `\key d \dorian`},
+		{&theory.Scale{Root: 9, Def: theory.ScaleDefMap[theory.MinorPentatonicScale]}, `\key a \minor`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := Key(tt.scale); got != tt.want {
+				t.Errorf("Key(%s) = %s, want %s", tt.scale, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChordMode(t *testing.T) {
+	chords := theory.Chords{
+		theory.NewChordFromAbbrev("Dm7"),
+		theory.NewChordFromAbbrev("G7"),
+		theory.NewChordFromAbbrev("CMaj7"),
+	}
+	spans := theory.NewChordSpans(chords, 384, 192, 576)
+	spans[1].Start += 96
+	spans[1].Duration -= 96
+	got := ChordMode(spans, Options{})
+	if want := `\chordmode { d1:m7 r4 g4:7 c1:maj7 s2 }`; got != want {
+		t.Errorf("ChordMode() = %s, want %s", got, want)
+	}
+}
+
+func TestChordMode_noChord(t *testing.T) {
+	spans := theory.ChordSpans{
+		{Chord: theory.NewChordFromAbbrev("Cmaj"), Start: 0, Duration: 384},
+		{Chord: nil, Start: 384, Duration: 192},
+		{Chord: &theory.Chord{}, Start: 576, Duration: 192},
+		{Chord: theory.NewChordFromAbbrev("G7"), Start: 768, Duration: 384},
+	}
+	got := ChordMode(spans, Options{})
+	if want := `\chordmode { c1 r2 r2 g1:7 }`; got != want {
+		t.Errorf("ChordMode() = %s, want %s", got, want)
+	}
+}
+
+func TestNoteStaff(t *testing.T) {
+	fMajor := &theory.Scale{Root: 5, Def: theory.ScaleDefMap[theory.MajorScale]}
+	notes := []theory.NoteEvent{
+		{Key: midi.KeyInt("F", 3), Start: 0, Duration: 96},
+		{Key: midi.KeyInt("A#", 3), Start: 96, Duration: 192},
+		// crosses the bar line
+		{Key: midi.KeyInt("C", 4), Start: 288, Duration: 192},
+		{Key: midi.KeyInt("E", 3), Start: 384, Duration: 96},
+		// out of the scale, spelled with flats like the key signature
+		{Key: midi.KeyInt("F#", 3), Start: 576, Duration: 96},
+	}
+	got := NoteStaff(notes, Options{Scale: fMajor, TimeSignature: [2]int{4, 4}})
+	want := `\new Staff { \clef treble \key f \major \time 4/4 f'4 bes'2 c''4~ | <e' c''>4 r4 ges'4 \bar "|." }`
+	if got != want {
+		t.Errorf("NoteStaff() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestScaleStaff(t *testing.T) {
+	eb := &theory.Scale{Root: 3, Def: theory.ScaleDefMap[theory.MajorScale]}
+	got := ScaleStaff(eb, midi.KeyInt("D#", 3), 7, Options{})
+	want := `\new Staff { \clef treble \key es \major \time 4/4 es'4 f'4 g'4 as'4 | bes'4 c''4 d''4 es''4 \bar "|." }`
+	if got != want {
+		t.Errorf("ScaleStaff() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestDocument_String(t *testing.T) {
+	chords := theory.Chords{
+		{Keys: []int{midi.KeyInt("C", 3), midi.KeyInt("D#", 3), midi.KeyInt("G", 3)}, Spelling: theory.FlatSpelling},
+		{Keys: []int{midi.KeyInt("G", 2), midi.KeyInt("B", 2), midi.KeyInt("D", 3), midi.KeyInt("F", 3)}},
+	}
+	doc := &Document{
+		Title:   "Cadence",
+		Options: Options{Scale: &theory.Scale{Root: 0, Def: theory.ScaleDefMap[theory.NaturalMinorScale]}},
+		Chords:  theory.NewChordSpans(chords, 384),
+	}
+	got := doc.String()
+	for _, want := range []string{
+		`\version "` + Version + `"`,
+		`title = "Cadence"`,
+		`\new ChordNames \chordmode { c1:m g1:7 }`,
+		`<c' es' g'>1 | <g b d' f'>1`,
+		`\key c \minor`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected the document to contain %s, got:\n%s", want, got)
+		}
+	}
+}
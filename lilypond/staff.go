@@ -0,0 +1,147 @@
+package lilypond
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-audio/music/internal/notation"
+	"github.com/go-audio/music/theory"
+)
+
+// Options are the settings used to render music expressions.
+type Options struct {
+	// TicksPerQuarter is the resolution of the timed events (96 by default).
+	TicksPerQuarter int
+	// TimeSignature is the numerator and denominator of the time signature
+	// (4/4 when not set).
+	TimeSignature [2]int
+	// Scale, if set, is used for the key signature and to spell the notes.
+	Scale *theory.Scale
+}
+
+func (o Options) meter() notation.Meter {
+	return notation.Meter{TicksPerQuarter: o.TicksPerQuarter, TimeSignature: o.TimeSignature}
+}
+
+// header returns the key and time signature commands.
+func (o Options) header() string {
+	beats, beatType := o.meter().Signature()
+	h := fmt.Sprintf(`\time %d/%d`, beats, beatType)
+	if o.Scale != nil {
+		h = Key(o.Scale) + " " + h
+	}
+	return h
+}
+
+// ChordMode returns a \chordmode expression naming the chord spans. Gaps
+// between chords and spans without a chord are written as rests.
+func ChordMode(spans theory.ChordSpans, opts Options) string {
+	sorted := make(theory.ChordSpans, len(spans))
+	copy(sorted, spans)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Start < sorted[j].Start })
+	tpq := opts.meter().Ticks()
+	items := []string{}
+	var pos int
+	for i, span := range sorted {
+		if span.Start > pos {
+			items = append(items, durationItems("r", span.Start-pos, tpq)...)
+			pos = span.Start
+		}
+		end := span.End()
+		if i+1 < len(sorted) && sorted[i+1].Start < end {
+			end = sorted[i+1].Start
+		}
+		if end <= pos {
+			continue
+		}
+		if span.Chord == nil || len(span.Chord.Keys) == 0 {
+			items = append(items, durationItems("r", end-pos, tpq)...)
+			pos = end
+			continue
+		}
+		values := Durations(end-pos, tpq)
+		for j, v := range values {
+			if j == 0 {
+				items = append(items, ChordName(span.Chord, v))
+				continue
+			}
+			// the chord name is only printed once
+			items = append(items, "s"+v)
+		}
+		pos = end
+	}
+	return `\chordmode { ` + strings.Join(items, " ") + " }"
+}
+
+// ChordStaff returns a staff expression playing the chord spans using their
+// voicing, spelled relative to each chord root.
+func ChordStaff(spans theory.ChordSpans, opts Options) string {
+	return staff(notation.ChordNotes(spans), opts)
+}
+
+// NoteStaff returns a staff expression playing the notes, spelled using the
+// scale of the options. Overlapping notes are written as tied chords.
+func NoteStaff(notes []theory.NoteEvent, opts Options) string {
+	return staff(notation.SpellNotes(notes, opts.Scale), opts)
+}
+
+// ScaleStaff returns a staff expression playing a run of the scale as quarter
+// notes, from the start note and moving the passed number of steps.
+func ScaleStaff(s *theory.Scale, start, steps int, opts Options) string {
+	opts.Scale = s
+	tpq := opts.meter().Ticks()
+	notes := []theory.NoteEvent{}
+	for i, k := range s.Run(start, steps) {
+		notes = append(notes, theory.NoteEvent{Key: k, Start: i * tpq, Duration: tpq})
+	}
+	return NoteStaff(notes, opts)
+}
+
+// staff renders the notes, cutting them (and tying them) wherever a note
+// starts or stops and at the bar lines.
+func staff(notes []notation.Note, opts Options) string {
+	meter := opts.meter()
+	items := []string{}
+	for _, slice := range meter.Slices(notes) {
+		if slice.Bar {
+			items = append(items, "|")
+		}
+		sounding := slice.Notes
+		if len(sounding) == 0 {
+			items = append(items, durationItems("r", slice.To-slice.From, meter.Ticks())...)
+			continue
+		}
+		values := Durations(slice.To-slice.From, meter.Ticks())
+		for j, v := range values {
+			last := j == len(values)-1
+			pitches := make([]string, 0, len(sounding))
+			for _, n := range sounding {
+				p := Pitch(n.Key, n.Name)
+				if last && n.End() > slice.To && len(sounding) > 1 {
+					p += "~"
+				}
+				pitches = append(pitches, p)
+			}
+			item := pitches[0] + v
+			if len(pitches) > 1 {
+				item = "<" + strings.Join(pitches, " ") + ">" + v
+			}
+			if !last || (len(sounding) == 1 && sounding[0].End() > slice.To) {
+				item += "~"
+			}
+			items = append(items, item)
+		}
+	}
+	items = append(items, `\bar "|."`)
+	return `\new Staff { \clef treble ` + opts.header() + " " + strings.Join(items, " ") + " }"
+}
+
+// durationItems returns the items of a rest or skip lasting the passed ticks.
+func durationItems(prefix string, ticks, tpq int) []string {
+	items := []string{}
+	for _, v := range Durations(ticks, tpq) {
+		items = append(items, prefix+v)
+	}
+	return items
+}
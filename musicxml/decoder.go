@@ -0,0 +1,305 @@
+package musicxml
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+
+	"github.com/go-audio/midi"
+	"github.com/go-audio/music/theory"
+)
+
+// Score is the musical content of a MusicXML score.
+type Score struct {
+	Title string
+	// Timeline contains the notes of all the parts, the notes of each part
+	// use their own channel. Its resolution is the smallest one allowing to
+	// express the divisions of all the parts.
+	Timeline *theory.Timeline
+	// Harmonies are the chord symbols of the score.
+	Harmonies []Harmony
+	// Keys are the key signatures of the score.
+	Keys []KeySignature
+}
+
+// Harmony is a chord symbol found in a score.
+type Harmony struct {
+	// Start and Duration are expressed in timeline ticks, a harmony lasts
+	// until the next harmony of the part or the end of the part.
+	Start, Duration int
+	// Root and Bass are the spelled note names of the chord root and bass (if
+	// different from the root).
+	Root, Bass string
+	Kind       Kind
+	// Chord is the chord with its root on the third octave and the bass
+	// (if any) below.
+	Chord *theory.Chord
+}
+
+// KeySignature is a key signature change found in a score.
+type KeySignature struct {
+	Start  int
+	Fifths int
+	// Mode is the MusicXML mode (major, minor, dorian...), empty if not set.
+	Mode string
+}
+
+// modeScales are the scales matching the MusicXML modes.
+var modeScales = map[string]theory.ScaleName{
+	"":           theory.MajorScale,
+	"major":      theory.MajorScale,
+	"ionian":     theory.MajorScale,
+	"minor":      theory.NaturalMinorScale,
+	"aeolian":    theory.NaturalMinorScale,
+	"dorian":     theory.DorianScale,
+	"phrygian":   theory.PhrygianScale,
+	"lydian":     theory.LydianScale,
+	"mixolydian": theory.MixolydianScale,
+	"locrian":    theory.LocrianScale,
+}
+
+// Scale returns the scale of the key signature.
+func (k KeySignature) Scale() *theory.Scale {
+	name, ok := modeScales[strings.ToLower(k.Mode)]
+	if !ok {
+		name = theory.MajorScale
+	}
+	return theory.NewScaleFromKeySignature(k.Fifths, name)
+}
+
+// ChordSpans returns the chords of the harmonies of the score.
+func (s *Score) ChordSpans() theory.ChordSpans {
+	spans := theory.ChordSpans{}
+	for _, h := range s.Harmonies {
+		spans = append(spans, theory.ChordSpan{Chord: h.Chord, Start: h.Start, Duration: h.Duration})
+	}
+	return spans
+}
+
+// Scale returns the scale of the first key signature of the score, nil if the
+// score doesn't have a key signature.
+func (s *Score) Scale() *theory.Scale {
+	if len(s.Keys) == 0 {
+		return nil
+	}
+	return s.Keys[0].Scale()
+}
+
+// Read decodes a partwise MusicXML score.
+func Read(r io.Reader) (*Score, error) {
+	doc := &xmlScore{}
+	dec := xml.NewDecoder(r)
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if start.Name.Local != "score-partwise" {
+			return nil, ErrNotPartwise
+		}
+		if err := dec.DecodeElement(doc, &start); err != nil {
+			return nil, err
+		}
+		break
+	}
+
+	score := &Score{Timeline: theory.NewTimeline(resolution(doc))}
+	if doc.Work != nil {
+		score.Title = doc.Work.Title
+	}
+	tpq := score.Timeline.TicksPerQuarter
+	for i, part := range doc.Parts {
+		p := &partReader{score: score, channel: i % 16, tpq: tpq, divisions: 1, harmonyStart: len(score.Harmonies)}
+		for _, m := range part.Measures {
+			p.readMeasure(m, i == 0)
+		}
+		p.closeHarmonies()
+	}
+	score.Timeline.Sort()
+	return score, nil
+}
+
+// resolution returns the least common multiple of the divisions used in the
+// score.
+func resolution(doc *xmlScore) int {
+	res := 1
+	for _, part := range doc.Parts {
+		for _, m := range part.Measures {
+			for _, item := range m.Items {
+				if attr, ok := item.(*xmlAttributes); ok && attr.Divisions > 0 {
+					res = res * attr.Divisions / gcd(res, attr.Divisions)
+				}
+			}
+		}
+	}
+	return res
+}
+
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+// partReader tracks the position while reading the measures of a part.
+type partReader struct {
+	score        *Score
+	channel      int
+	tpq          int
+	divisions    int
+	pos          int
+	lastStart    int
+	end          int
+	harmonyStart int
+	// ties are the indexes of the timeline notes waiting for a tied note.
+	ties map[int]int
+}
+
+func (p *partReader) ticks(duration int) int {
+	return duration * p.tpq / p.divisions
+}
+
+func (p *partReader) readMeasure(m xmlMeasure, readKeys bool) {
+	for _, item := range m.Items {
+		switch it := item.(type) {
+		case *xmlAttributes:
+			if it.Divisions > 0 {
+				p.divisions = it.Divisions
+			}
+			if it.Key != nil && readKeys {
+				p.score.Keys = append(p.score.Keys, KeySignature{Start: p.pos, Fifths: it.Key.Fifths, Mode: it.Key.Mode})
+			}
+		case *xmlBackup:
+			p.pos -= p.ticks(it.Duration)
+			if p.pos < 0 {
+				p.pos = 0
+			}
+		case *xmlForward:
+			p.move(p.ticks(it.Duration))
+		case *xmlHarmony:
+			p.readHarmony(it)
+		case *xmlNote:
+			p.readNote(it)
+		}
+	}
+}
+
+func (p *partReader) move(ticks int) {
+	p.pos += ticks
+	if p.pos > p.end {
+		p.end = p.pos
+	}
+}
+
+func (p *partReader) readNote(n *xmlNote) {
+	if n.Grace != nil {
+		return
+	}
+	duration := p.ticks(n.Duration)
+	start := p.pos
+	if n.Chord != nil {
+		start = p.lastStart
+	} else {
+		p.lastStart = start
+		p.move(duration)
+	}
+	if n.Pitch == nil || n.Rest != nil {
+		return
+	}
+	natural := naturalPitch(n.Pitch.Step)
+	if natural < 0 {
+		return
+	}
+	key := (n.Pitch.Octave+1)*12 + natural + int(n.Pitch.Alter)
+	if p.ties == nil {
+		p.ties = map[int]int{}
+	}
+	tl := p.score.Timeline
+	if idx, ok := p.ties[key]; ok && hasTie(n, "stop") && tl.Notes[idx].End() == start {
+		tl.Notes[idx].Duration += duration
+		if !hasTie(n, "start") {
+			delete(p.ties, key)
+		}
+		return
+	}
+	tl.Add(theory.NoteEvent{Key: key, Start: start, Duration: duration, Velocity: 90, Channel: p.channel})
+	if hasTie(n, "start") {
+		p.ties[key] = len(tl.Notes) - 1
+	}
+}
+
+func hasTie(n *xmlNote, tieType string) bool {
+	for _, tie := range n.Ties {
+		if tie.Type == tieType {
+			return true
+		}
+	}
+	for _, tie := range n.Tied {
+		if tie.Type == tieType {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *partReader) readHarmony(h *xmlHarmony) {
+	kind := Kind{Value: h.Kind.Value, Text: h.Kind.Text}
+	for _, d := range h.Degrees {
+		kind.Degrees = append(kind.Degrees, Degree{Value: d.Value, Alter: int(d.Alter), Type: d.Type})
+	}
+	halfSteps := kind.HalfSteps()
+	if halfSteps == nil || naturalPitch(h.Root.Step) < 0 {
+		// "none", "other" or unsupported kinds
+		return
+	}
+	root := pitchName(h.Root.Step, h.Root.Alter)
+	rootPC := pitchClass(h.Root.Step, h.Root.Alter)
+	spelling := theory.SpellingOf(root)
+	rootKey := midi.KeyInt("C", 3) + rootPC
+	chord := &theory.Chord{Spelling: spelling}
+	harmony := Harmony{Start: p.pos + p.ticks(h.Offset), Root: root, Kind: kind, Chord: chord}
+	if h.Bass != nil && naturalPitch(h.Bass.Step) >= 0 {
+		bassPC := pitchClass(h.Bass.Step, h.Bass.Alter)
+		if bassPC != rootPC {
+			harmony.Bass = pitchName(h.Bass.Step, h.Bass.Alter)
+			chord.Keys = append(chord.Keys, rootKey-12+(bassPC-rootPC+12)%12)
+		}
+	}
+	for _, hs := range halfSteps {
+		chord.Keys = append(chord.Keys, rootKey+hs)
+	}
+	p.score.Harmonies = append(p.score.Harmonies, harmony)
+	p.closeHarmonies()
+}
+
+// closeHarmonies sets the duration of the harmonies of the part, each lasting
+// until the next one.
+func (p *partReader) closeHarmonies() {
+	harmonies := p.score.Harmonies[p.harmonyStart:]
+	for i := range harmonies {
+		end := p.end
+		if i+1 < len(harmonies) {
+			end = harmonies[i+1].Start
+		}
+		harmonies[i].Duration = end - harmonies[i].Start
+	}
+}
+
+// naturalPitch returns the pitch class of a step (C to B), -1 if the step
+// isn't valid.
+func naturalPitch(step string) int {
+	if len(step) != 1 || step == " " {
+		return -1
+	}
+	return strings.Index("C D EF G A B", strings.ToUpper(step))
+}
+
+// pitchClass returns the pitch class of a step and alteration.
+func pitchClass(step string, alter float64) int {
+	return ((naturalPitch(step)+int(alter))%12 + 12) % 12
+}
@@ -0,0 +1,279 @@
+package musicxml
+
+import (
+	"encoding/xml"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/go-audio/music/theory"
+)
+
+// Encoder writes chord progressions as MusicXML scores. Each chord is written
+// as a <harmony> element followed by its spelled notes, chords crossing a bar
+// line are tied.
+type Encoder struct {
+	w io.Writer
+	// Title is the title of the score.
+	Title string
+	// PartName is the name of the part (Chords by default).
+	PartName string
+	// Divisions are the number of ticks per quarter note used by the chord
+	// spans (96 by default).
+	Divisions int
+	// TimeSignature is the numerator and denominator of the time signature
+	// (4/4 when not set).
+	TimeSignature [2]int
+	// Scale, if set, is used to write the key signature.
+	Scale *theory.Scale
+}
+
+// NewEncoder returns an encoder writing to w.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w}
+}
+
+// Write writes a MusicXML score of the chord spans using the passed
+// resolution.
+func Write(w io.Writer, spans theory.ChordSpans, divisions int, s *theory.Scale) error {
+	e := NewEncoder(w)
+	e.Divisions = divisions
+	e.Scale = s
+	return e.Encode(spans)
+}
+
+// Encode writes a MusicXML score of the chord spans.
+func (e *Encoder) Encode(spans theory.ChordSpans) error {
+	doc := e.score(spans)
+	if _, err := io.WriteString(e.w, xml.Header+doctype+"\n"); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(e.w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(e.w, "\n")
+	return err
+}
+
+func (e *Encoder) divisions() int {
+	if e.Divisions <= 0 {
+		return 96
+	}
+	return e.Divisions
+}
+
+func (e *Encoder) timeSignature() (int, int) {
+	if e.TimeSignature[0] <= 0 || e.TimeSignature[1] <= 0 {
+		return 4, 4
+	}
+	return e.TimeSignature[0], e.TimeSignature[1]
+}
+
+func (e *Encoder) score(spans theory.ChordSpans) *xmlScore {
+	name := e.PartName
+	if name == "" {
+		name = "Chords"
+	}
+	doc := &xmlScore{
+		Version:  "4.0",
+		PartList: xmlPartList{ScoreParts: []xmlScorePart{{ID: "P1", Name: name}}},
+	}
+	if e.Title != "" {
+		doc.Work = &xmlWork{Title: e.Title}
+	}
+
+	beats, beatType := e.timeSignature()
+	measureLen := beats * e.divisions() * 4 / beatType
+	attributes := &xmlAttributes{
+		Divisions: e.divisions(),
+		Time:      &xmlTime{Beats: beats, BeatType: beatType},
+		Clef:      &xmlClef{Sign: "G", Line: 2},
+	}
+	if e.Scale != nil {
+		mode := "major"
+		if e.Scale.IsMinor() {
+			mode = "minor"
+		}
+		attributes.Key = &xmlKey{Fifths: e.Scale.KeySignature(), Mode: mode}
+	}
+
+	sorted := make(theory.ChordSpans, len(spans))
+	copy(sorted, spans)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Start < sorted[j].Start })
+	var end int
+	for _, span := range sorted {
+		if span.End() > end {
+			end = span.End()
+		}
+	}
+
+	part := xmlPart{ID: "P1"}
+	measure := &xmlMeasure{Number: "1", Items: []interface{}{attributes}}
+	var pos, idx int
+	// writes the chord (nil for a rest) from pos for the passed duration,
+	// splitting it across bar lines
+	write := func(span *theory.ChordSpan, duration int) {
+		first := true
+		for duration > 0 {
+			barEnd := (pos/measureLen + 1) * measureLen
+			d := duration
+			if pos+d > barEnd {
+				d = barEnd - pos
+			}
+			last := d == duration
+			if span == nil {
+				measure.Items = append(measure.Items, restNote(d, e.divisions()))
+			} else {
+				if first {
+					measure.Items = append(measure.Items, harmony(span.Chord))
+				}
+				measure.Items = append(measure.Items, chordNotes(span.Chord, d, e.divisions(), !first, !last)...)
+			}
+			first = false
+			pos += d
+			duration -= d
+			if pos%measureLen == 0 {
+				part.Measures = append(part.Measures, *measure)
+				measure = &xmlMeasure{Number: strconv.Itoa(len(part.Measures) + 1)}
+			}
+		}
+	}
+	for pos < end {
+		for idx < len(sorted) && sorted[idx].End() <= pos {
+			idx++
+		}
+		if idx >= len(sorted) {
+			break
+		}
+		span := sorted[idx]
+		if span.Start > pos {
+			write(nil, span.Start-pos)
+			continue
+		}
+		// overlapping chords are cut by the next one
+		stop := span.End()
+		if idx+1 < len(sorted) && sorted[idx+1].Start < stop {
+			stop = sorted[idx+1].Start
+		}
+		// nil chords are silences
+		if span.Chord == nil || len(span.Chord.Keys) == 0 {
+			write(nil, stop-pos)
+		} else {
+			write(&span, stop-pos)
+		}
+		idx++
+	}
+	// complete the last measure
+	if pos%measureLen != 0 || len(part.Measures) == 0 {
+		write(nil, measureLen-pos%measureLen)
+	}
+	doc.Parts = []xmlPart{part}
+	return doc
+}
+
+// harmony returns the harmony element of a chord.
+func harmony(c *theory.Chord) *xmlHarmony {
+	def := c.Copy().Def()
+	kind := KindFor(def)
+	h := &xmlHarmony{Kind: xmlKind{Value: kind.Value, Text: kind.Text}}
+	if def.Root == "" {
+		h.Kind = xmlKind{Value: "none"}
+		if len(c.Keys) > 0 {
+			h.Root.Step, h.Root.Alter = stepAndAlter(theory.NoteName(c.Keys[0], c.Spelling))
+		}
+		return h
+	}
+	h.Root.Step, h.Root.Alter = stepAndAlter(def.Root)
+	for _, d := range kind.Degrees {
+		h.Degrees = append(h.Degrees, xmlDegree{Value: d.Value, Alter: float64(d.Alter), Type: d.Type})
+	}
+	// inversions are written with a bass note
+	names := c.NoteNames()
+	lowest := 0
+	for i, k := range c.Keys {
+		if k < c.Keys[lowest] {
+			lowest = i
+		}
+	}
+	if step, alter := stepAndAlter(names[lowest]); step != h.Root.Step || alter != h.Root.Alter {
+		h.Bass = &xmlBass{Step: step, Alter: alter}
+	}
+	return h
+}
+
+// chordNotes returns the spelled notes of the chord, lowest first.
+func chordNotes(c *theory.Chord, duration, divisions int, tieStop, tieStart bool) []interface{} {
+	names := c.NoteNames()
+	order := make([]int, len(c.Keys))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return c.Keys[order[i]] < c.Keys[order[j]] })
+
+	noteType, dots := typeOf(duration, divisions)
+	notes := []interface{}{}
+	for i, idx := range order {
+		step, alter := stepAndAlter(names[idx])
+		n := &xmlNote{
+			Pitch:    &xmlPitch{Step: step, Alter: alter, Octave: octave(c.Keys[idx], int(alter))},
+			Duration: duration,
+			Voice:    "1",
+			Type:     noteType,
+			Dots:     make([]struct{}, dots),
+		}
+		if i > 0 {
+			n.Chord = &struct{}{}
+		}
+		if tieStop {
+			n.Ties = append(n.Ties, xmlTie{Type: "stop"})
+			n.Tied = append(n.Tied, xmlTie{Type: "stop"})
+		}
+		if tieStart {
+			n.Ties = append(n.Ties, xmlTie{Type: "start"})
+			n.Tied = append(n.Tied, xmlTie{Type: "start"})
+		}
+		notes = append(notes, n)
+	}
+	return notes
+}
+
+func restNote(duration, divisions int) *xmlNote {
+	noteType, dots := typeOf(duration, divisions)
+	return &xmlNote{Rest: &struct{}{}, Duration: duration, Voice: "1", Type: noteType, Dots: make([]struct{}, dots)}
+}
+
+// octave returns the MusicXML octave (middle C being C4) of a MIDI key
+// spelled with the passed alteration (B#3 sounds like C4).
+func octave(key, alter int) int {
+	k := key - alter
+	if k < 0 {
+		return (k+1)/12 - 2
+	}
+	return k/12 - 1
+}
+
+var noteTypes = []struct {
+	name     string
+	quarters float64
+}{
+	{"whole", 4}, {"half", 2}, {"quarter", 1}, {"eighth", 0.5}, {"16th", 0.25}, {"32nd", 0.125},
+}
+
+// typeOf returns the note type and number of dots matching a duration, an
+// empty type is returned if the duration doesn't match a note type.
+func typeOf(duration, divisions int) (string, int) {
+	quarters := float64(duration) / float64(divisions)
+	for _, t := range noteTypes {
+		switch quarters {
+		case t.quarters:
+			return t.name, 0
+		case t.quarters * 1.5:
+			return t.name, 1
+		case t.quarters * 1.75:
+			return t.name, 2
+		}
+	}
+	return "", 0
+}
@@ -0,0 +1,202 @@
+package musicxml
+
+import (
+	"sort"
+
+	"github.com/go-audio/music/theory"
+)
+
+// Degree is a MusicXML chord degree added to, removed from or altering the
+// notes of a harmony kind.
+type Degree struct {
+	// Value is the degree of the chord tone (5 for the fifth, 9 for the ninth).
+	Value int
+	// Alter is the alteration in half steps (-1 for a flat ninth).
+	Alter int
+	// Type is add, alter or subtract.
+	Type string
+}
+
+// Kind is a MusicXML harmony kind with the degrees needed to describe one of
+// the theory.ChordDefs.
+type Kind struct {
+	// Value is the MusicXML kind value such as major-seventh.
+	Value string
+	// Text is the chord suffix displayed by notation software.
+	Text    string
+	Degrees []Degree
+}
+
+func add(value, alter int) Degree      { return Degree{Value: value, Alter: alter, Type: "add"} }
+func alter(value, alter int) Degree    { return Degree{Value: value, Alter: alter, Type: "alter"} }
+func subtract(value, alter int) Degree { return Degree{Value: value, Alter: alter, Type: "subtract"} }
+
+// kindAbbrevs are the abbreviations of the chord definitions matching each
+// MusicXML kind value.
+var kindAbbrevs = map[string]string{
+	"major":              "maj",
+	"minor":              "min",
+	"augmented":          "aug",
+	"diminished":         "mb5",
+	"dominant":           "7",
+	"major-seventh":      "Maj7",
+	"minor-seventh":      "m7",
+	"diminished-seventh": "tri",
+	"augmented-seventh":  "7#5",
+	"half-diminished":    "m7b5",
+	"major-minor":        "m-Maj7",
+	"major-sixth":        "6",
+	"minor-sixth":        "m6",
+	"dominant-ninth":     "9",
+	"major-ninth":        "Maj9",
+	"minor-ninth":        "m9",
+	"dominant-11th":      "11",
+	"major-11th":         "Maj11",
+	"minor-11th":         "m11",
+	"dominant-13th":      "13",
+	"major-13th":         "Maj13",
+	"minor-13th":         "min13",
+	"suspended-second":   "sus2",
+	"suspended-fourth":   "sus4",
+	"power":              "5",
+}
+
+// Kinds maps the abbreviations of theory.ChordDefs to MusicXML harmony kinds.
+var Kinds = map[string]Kind{
+	"maj":         {Value: "major"},
+	"min":         {Value: "minor", Text: "m"},
+	"mb5":         {Value: "diminished", Text: "dim"},
+	"aug":         {Value: "augmented", Text: "+"},
+	"5":           {Value: "power", Text: "5"},
+	"m7":          {Value: "minor-seventh", Text: "m7"},
+	"min7":        {Value: "minor-seventh", Text: "m7"},
+	"Maj7":        {Value: "major-seventh", Text: "Maj7"},
+	"7":           {Value: "dominant", Text: "7"},
+	"sus2":        {Value: "suspended-second", Text: "sus2"},
+	"sus4":        {Value: "suspended-fourth", Text: "sus4"},
+	"majb5":       {Value: "major", Text: "(b5)", Degrees: []Degree{alter(5, -1)}},
+	"tri":         {Value: "diminished-seventh", Text: "dim7"},
+	"6":           {Value: "major-sixth", Text: "6"},
+	"6sus4":       {Value: "major-sixth", Text: "6sus4", Degrees: []Degree{subtract(3, 0), add(4, 0)}},
+	"6add9":       {Value: "major-sixth", Text: "6/9", Degrees: []Degree{add(9, 0)}},
+	"m6":          {Value: "minor-sixth", Text: "m6"},
+	"min6":        {Value: "minor-sixth", Text: "m6"},
+	"m6add9":      {Value: "minor-sixth", Text: "m6/9", Degrees: []Degree{add(9, 0)}},
+	"min6add9":    {Value: "minor-sixth", Text: "m6/9", Degrees: []Degree{add(9, 0)}},
+	"7sus4":       {Value: "dominant", Text: "7sus4", Degrees: []Degree{subtract(3, 0), add(4, 0)}},
+	"7#5":         {Value: "augmented-seventh", Text: "7#5"},
+	"7b5":         {Value: "dominant", Text: "7b5", Degrees: []Degree{alter(5, -1)}},
+	"7#9":         {Value: "dominant", Text: "7#9", Degrees: []Degree{add(9, 1)}},
+	"7b9":         {Value: "dominant", Text: "7b9", Degrees: []Degree{add(9, -1)}},
+	"7#5#9":       {Value: "augmented-seventh", Text: "7#5#9", Degrees: []Degree{add(9, 1)}},
+	"7#5b9":       {Value: "augmented-seventh", Text: "7#5b9", Degrees: []Degree{add(9, -1)}},
+	"7b5b9":       {Value: "dominant", Text: "7b5b9", Degrees: []Degree{alter(5, -1), add(9, -1)}},
+	"7add11":      {Value: "dominant", Text: "7add11", Degrees: []Degree{add(11, 0)}},
+	"7add13":      {Value: "dominant", Text: "7add13", Degrees: []Degree{add(13, 0)}},
+	"7#11":        {Value: "dominant", Text: "7#11", Degrees: []Degree{add(11, 1)}},
+	"Maj7b5":      {Value: "major-seventh", Text: "Maj7b5", Degrees: []Degree{alter(5, -1)}},
+	"Maj7#5":      {Value: "major-seventh", Text: "Maj7#5", Degrees: []Degree{alter(5, 1)}},
+	"Maj7#11":     {Value: "major-seventh", Text: "Maj7#11", Degrees: []Degree{add(11, 1)}},
+	"Maj7add13":   {Value: "major-seventh", Text: "Maj7add13", Degrees: []Degree{add(13, 0)}},
+	"m7b5":        {Value: "half-diminished", Text: "m7b5"},
+	"m7b9":        {Value: "minor-seventh", Text: "m7b9", Degrees: []Degree{add(9, -1)}},
+	"m7add11":     {Value: "minor-seventh", Text: "m7add11", Degrees: []Degree{add(11, 0)}},
+	"m7add13":     {Value: "minor-seventh", Text: "m7add13", Degrees: []Degree{add(13, 0)}},
+	"m-Maj7":      {Value: "major-minor", Text: "m(Maj7)"},
+	"m-Maj7add11": {Value: "major-minor", Text: "m(Maj7)add11", Degrees: []Degree{add(11, 0)}},
+	"m-Maj7add13": {Value: "major-minor", Text: "m(Maj7)add13", Degrees: []Degree{add(13, 0)}},
+	"9":           {Value: "dominant-ninth", Text: "9"},
+	"9sus4":       {Value: "dominant-ninth", Text: "9sus4", Degrees: []Degree{subtract(3, 0), add(4, 0)}},
+	"add9":        {Value: "major", Text: "add9", Degrees: []Degree{add(9, 0)}},
+	"9#5":         {Value: "dominant-ninth", Text: "9#5", Degrees: []Degree{alter(5, 1)}},
+	"9b5":         {Value: "dominant-ninth", Text: "9b5", Degrees: []Degree{alter(5, -1)}},
+	"9#11":        {Value: "dominant-ninth", Text: "9#11", Degrees: []Degree{add(11, 1)}},
+	"9b13":        {Value: "dominant-ninth", Text: "9b13", Degrees: []Degree{add(13, -1)}},
+	"Maj9":        {Value: "major-ninth", Text: "Maj9"},
+	"Maj9sus4":    {Value: "major-ninth", Text: "Maj9sus4", Degrees: []Degree{subtract(3, 0), add(4, 0)}},
+	"Maj9#5":      {Value: "major-ninth", Text: "Maj9#5", Degrees: []Degree{alter(5, 1)}},
+	"Maj9#11":     {Value: "major-ninth", Text: "Maj9#11", Degrees: []Degree{add(11, 1)}},
+	"m9":          {Value: "minor-ninth", Text: "m9"},
+	"madd9":       {Value: "minor", Text: "madd9", Degrees: []Degree{add(9, 0)}},
+	"m9b5":        {Value: "minor-ninth", Text: "m9b5", Degrees: []Degree{alter(5, -1)}},
+	"m9-Maj7":     {Value: "major-minor", Text: "m9(Maj7)", Degrees: []Degree{add(9, 0)}},
+	"11":          {Value: "dominant-11th", Text: "11"},
+	"11b9":        {Value: "dominant-11th", Text: "11b9", Degrees: []Degree{alter(9, -1)}},
+	"Maj11":       {Value: "major-11th", Text: "Maj11"},
+	"m11":         {Value: "minor-11th", Text: "m11"},
+	"m-Maj11":     {Value: "major-minor", Text: "m(Maj11)", Degrees: []Degree{add(9, 0), add(11, 0)}},
+	"13":          {Value: "dominant-13th", Text: "13"},
+	"13#9":        {Value: "dominant-13th", Text: "13#9", Degrees: []Degree{alter(9, 1)}},
+	"13b9":        {Value: "dominant-13th", Text: "13b9", Degrees: []Degree{alter(9, -1)}},
+	"13b5b9":      {Value: "dominant-13th", Text: "13b5b9", Degrees: []Degree{alter(5, -1), alter(9, -1)}},
+	"Maj13":       {Value: "major-13th", Text: "Maj13"},
+	"min13":       {Value: "minor-13th", Text: "m13"},
+	"m-Maj13":     {Value: "major-minor", Text: "m(Maj13)", Degrees: []Degree{add(9, 0), add(13, 0)}},
+}
+
+// KindFor returns the MusicXML harmony kind of a chord definition. The
+// "other" kind is returned for unknown definitions.
+func KindFor(def *theory.ChordDefinition) Kind {
+	if def == nil {
+		return Kind{Value: "other"}
+	}
+	if k, ok := Kinds[def.Abbrev]; ok {
+		return k
+	}
+	return Kind{Value: "other", Text: def.Abbrev}
+}
+
+// degreeHalfSteps are the half steps above the root of the unaltered degrees
+// (the seventh being the minor seventh of dominant chords).
+var degreeHalfSteps = map[int]int{1: 0, 2: 2, 3: 4, 4: 5, 5: 7, 6: 9, 7: 10, 9: 14, 11: 17, 13: 21}
+
+// degreeOf returns the degree of a chord tone the passed number of half steps
+// above the root.
+func degreeOf(halfSteps int) int {
+	iv := theory.ChordToneInterval(halfSteps)
+	return iv.Steps + 1
+}
+
+// HalfSteps returns the half steps above the root of each note of the kind,
+// once the degrees applied (the root being 0). Nil is returned for unknown
+// kind values.
+func (k Kind) HalfSteps() []int {
+	abbrev, ok := kindAbbrevs[k.Value]
+	if !ok {
+		return nil
+	}
+	notes := []int{0}
+	for _, def := range theory.ChordDefs {
+		if def.Abbrev != abbrev {
+			continue
+		}
+		for _, hs := range def.HalfSteps {
+			notes = append(notes, notes[len(notes)-1]+int(hs))
+		}
+		break
+	}
+	for _, d := range k.Degrees {
+		natural, ok := degreeHalfSteps[d.Value]
+		if !ok {
+			continue
+		}
+		switch d.Type {
+		case "add":
+			notes = append(notes, natural+d.Alter)
+		case "alter", "subtract":
+			for i, n := range notes {
+				if degreeOf(n) != d.Value {
+					continue
+				}
+				if d.Type == "subtract" {
+					notes = append(notes[:i], notes[i+1:]...)
+				} else {
+					notes[i] = natural + d.Alter
+				}
+				break
+			}
+		}
+	}
+	sort.Ints(notes)
+	return notes
+}
@@ -0,0 +1,206 @@
+// Package musicxml reads and writes MusicXML scores using the notes and chords
+// of the theory package.
+package musicxml
+
+import (
+	"encoding/xml"
+	"errors"
+	"strings"
+)
+
+// ErrNotPartwise is returned when reading a MusicXML document which isn't a
+// partwise score (timewise scores aren't supported).
+var ErrNotPartwise = errors.New("not a partwise MusicXML score")
+
+const doctype = `<!DOCTYPE score-partwise PUBLIC "-//Recordare//DTD MusicXML 4.0 Partwise//EN" "http://www.musicxml.org/dtds/partwise.dtd">`
+
+type xmlScore struct {
+	XMLName  xml.Name    `xml:"score-partwise"`
+	Version  string      `xml:"version,attr,omitempty"`
+	Work     *xmlWork    `xml:"work,omitempty"`
+	PartList xmlPartList `xml:"part-list"`
+	Parts    []xmlPart   `xml:"part"`
+}
+
+type xmlWork struct {
+	Title string `xml:"work-title"`
+}
+
+type xmlPartList struct {
+	ScoreParts []xmlScorePart `xml:"score-part"`
+}
+
+type xmlScorePart struct {
+	ID   string `xml:"id,attr"`
+	Name string `xml:"part-name"`
+}
+
+type xmlPart struct {
+	ID       string       `xml:"id,attr"`
+	Measures []xmlMeasure `xml:"measure"`
+}
+
+// xmlMeasure keeps the elements of a measure in order since the position of
+// notes, backups, forwards and harmonies depends on the elements before them.
+type xmlMeasure struct {
+	XMLName xml.Name `xml:"measure"`
+	Number  string   `xml:"number,attr"`
+	Items   []interface{}
+}
+
+type xmlAttributes struct {
+	XMLName   xml.Name `xml:"attributes"`
+	Divisions int      `xml:"divisions,omitempty"`
+	Key       *xmlKey  `xml:"key,omitempty"`
+	Time      *xmlTime `xml:"time,omitempty"`
+	Clef      *xmlClef `xml:"clef,omitempty"`
+}
+
+type xmlKey struct {
+	Fifths int    `xml:"fifths"`
+	Mode   string `xml:"mode,omitempty"`
+}
+
+type xmlTime struct {
+	Beats    int `xml:"beats"`
+	BeatType int `xml:"beat-type"`
+}
+
+type xmlClef struct {
+	Sign string `xml:"sign"`
+	Line int    `xml:"line"`
+}
+
+type xmlNote struct {
+	XMLName  xml.Name   `xml:"note"`
+	Grace    *struct{}  `xml:"grace"`
+	Chord    *struct{}  `xml:"chord"`
+	Pitch    *xmlPitch  `xml:"pitch"`
+	Rest     *struct{}  `xml:"rest"`
+	Duration int        `xml:"duration"`
+	Ties     []xmlTie   `xml:"tie"`
+	Voice    string     `xml:"voice,omitempty"`
+	Type     string     `xml:"type,omitempty"`
+	Dots     []struct{} `xml:"dot"`
+	Tied     []xmlTie   `xml:"notations>tied"`
+}
+
+type xmlPitch struct {
+	Step   string  `xml:"step"`
+	Alter  float64 `xml:"alter,omitempty"`
+	Octave int     `xml:"octave"`
+}
+
+type xmlTie struct {
+	Type string `xml:"type,attr"`
+}
+
+type xmlBackup struct {
+	XMLName  xml.Name `xml:"backup"`
+	Duration int      `xml:"duration"`
+}
+
+type xmlForward struct {
+	XMLName  xml.Name `xml:"forward"`
+	Duration int      `xml:"duration"`
+}
+
+type xmlHarmony struct {
+	XMLName xml.Name    `xml:"harmony"`
+	Root    xmlRoot     `xml:"root"`
+	Kind    xmlKind     `xml:"kind"`
+	Bass    *xmlBass    `xml:"bass,omitempty"`
+	Degrees []xmlDegree `xml:"degree"`
+	Offset  int         `xml:"offset,omitempty"`
+}
+
+type xmlRoot struct {
+	Step  string  `xml:"root-step"`
+	Alter float64 `xml:"root-alter,omitempty"`
+}
+
+type xmlKind struct {
+	Text  string `xml:"text,attr,omitempty"`
+	Value string `xml:",chardata"`
+}
+
+type xmlBass struct {
+	Step  string  `xml:"bass-step"`
+	Alter float64 `xml:"bass-alter,omitempty"`
+}
+
+type xmlDegree struct {
+	Value int     `xml:"degree-value"`
+	Alter float64 `xml:"degree-alter"`
+	Type  string  `xml:"degree-type"`
+}
+
+// UnmarshalXML decodes the measure elements used by the package, in order.
+func (m *xmlMeasure) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	m.XMLName = start.Name
+	for _, attr := range start.Attr {
+		if attr.Name.Local == "number" {
+			m.Number = attr.Value
+		}
+	}
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.EndElement:
+			return nil
+		case xml.StartElement:
+			var item interface{}
+			switch t.Name.Local {
+			case "attributes":
+				item = &xmlAttributes{}
+			case "note":
+				item = &xmlNote{}
+			case "backup":
+				item = &xmlBackup{}
+			case "forward":
+				item = &xmlForward{}
+			case "harmony":
+				item = &xmlHarmony{}
+			default:
+				if err := d.Skip(); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := d.DecodeElement(item, &t); err != nil {
+				return err
+			}
+			m.Items = append(m.Items, item)
+		}
+	}
+}
+
+// pitchName returns the note name of a step and alteration (B and -1 give
+// Bb).
+func pitchName(step string, alter float64) string {
+	a := int(alter)
+	if a > 0 {
+		return step + strings.Repeat("#", a)
+	}
+	return step + strings.Repeat("b", -a)
+}
+
+// stepAndAlter splits a note name such as Bb into its step and alteration.
+func stepAndAlter(name string) (string, float64) {
+	if len(name) == 0 {
+		return "", 0
+	}
+	var alter float64
+	for _, r := range name[1:] {
+		switch r {
+		case '#':
+			alter++
+		case 'b':
+			alter--
+		}
+	}
+	return strings.ToUpper(name[:1]), alter
+}
@@ -0,0 +1,212 @@
+package musicxml
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-audio/midi"
+	"github.com/go-audio/music/theory"
+)
+
+const testScore = `<?xml version="1.0" encoding="UTF-8" standalone="no"?>
+<!DOCTYPE score-partwise PUBLIC "-//Recordare//DTD MusicXML 4.0 Partwise//EN" "http://www.musicxml.org/dtds/partwise.dtd">
+<score-partwise version="4.0">
+  <work><work-title>Test</work-title></work>
+  <part-list><score-part id="P1"><part-name>Piano</part-name></score-part></part-list>
+  <part id="P1">
+    <measure number="1">
+      <attributes>
+        <divisions>2</divisions>
+        <key><fifths>-2</fifths><mode>major</mode></key>
+        <time><beats>4</beats><beat-type>4</beat-type></time>
+      </attributes>
+      <harmony>
+        <root><root-step>B</root-step><root-alter>-1</root-alter></root>
+        <kind text="Maj7">major-seventh</kind>
+      </harmony>
+      <note><pitch><step>B</step><alter>-1</alter><octave>3</octave></pitch><duration>4</duration><type>half</type></note>
+      <note><chord/><pitch><step>D</step><octave>4</octave></pitch><duration>4</duration><type>half</type></note>
+      <note><chord/><pitch><step>F</step><octave>4</octave></pitch><duration>4</duration><type>half</type></note>
+      <note><chord/><pitch><step>A</step><octave>4</octave></pitch><duration>4</duration><type>half</type></note>
+      <harmony>
+        <root><root-step>C</root-step></root>
+        <kind text="7b9">dominant</kind>
+        <bass><bass-step>E</bass-step></bass>
+        <degree><degree-value>9</degree-value><degree-alter>-1</degree-alter><degree-type>add</degree-type></degree>
+      </harmony>
+      <note><pitch><step>E</step><octave>4</octave></pitch><duration>4</duration><tie type="start"/><type>half</type></note>
+      <backup><duration>8</duration></backup>
+      <note><pitch><step>B</step><alter>-1</alter><octave>2</octave></pitch><duration>8</duration><type>whole</type></note>
+    </measure>
+    <measure number="2">
+      <note><pitch><step>E</step><octave>4</octave></pitch><duration>2</duration><tie type="stop"/><type>quarter</type></note>
+      <note><rest/><duration>6</duration></note>
+    </measure>
+  </part>
+</score-partwise>
+`
+
+func TestRead(t *testing.T) {
+	score, err := Read(strings.NewReader(testScore))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if score.Title != "Test" {
+		t.Errorf("expected the Test title, got %q", score.Title)
+	}
+	if got := score.Scale().String(); got != "A# Major" {
+		t.Errorf("expected the A# Major scale (2 flats), got %s", got)
+	}
+
+	type note struct{ key, start, duration int }
+	got := []note{}
+	for _, n := range score.Timeline.Notes {
+		got = append(got, note{n.Key, n.Start, n.Duration})
+	}
+	// MusicXML's C4 is midi.KeyInt("C", 3)
+	want := []note{
+		{midi.KeyInt("A#", 1), 0, 8},
+		{midi.KeyInt("A#", 2), 0, 4},
+		{midi.KeyInt("D", 3), 0, 4},
+		{midi.KeyInt("F", 3), 0, 4},
+		{midi.KeyInt("A", 3), 0, 4},
+		{midi.KeyInt("E", 3), 4, 6},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected notes %v, got %v", want, got)
+	}
+
+	if len(score.Harmonies) != 2 {
+		t.Fatalf("expected 2 harmonies, got %d", len(score.Harmonies))
+	}
+	bbMaj7, c7b9 := score.Harmonies[0], score.Harmonies[1]
+	if bbMaj7.Root != "Bb" || bbMaj7.Start != 0 || bbMaj7.Duration != 4 {
+		t.Errorf("unexpected first harmony %+v", bbMaj7)
+	}
+	if got := bbMaj7.Chord.Def().String(); got != "Bb Major Seventh" {
+		t.Errorf("expected Bb Major Seventh, got %s", got)
+	}
+	if c7b9.Bass != "E" || c7b9.Start != 4 || c7b9.Duration != 12 {
+		t.Errorf("unexpected second harmony %+v", c7b9)
+	}
+	wantKeys := []int{midi.KeyInt("E", 2), midi.KeyInt("C", 3), midi.KeyInt("E", 3), midi.KeyInt("G", 3), midi.KeyInt("A#", 3), midi.KeyInt("C#", 4)}
+	if !reflect.DeepEqual(c7b9.Chord.Keys, wantKeys) {
+		t.Errorf("expected C7b9/E keys %v, got %v", wantKeys, c7b9.Chord.Keys)
+	}
+	if spans := score.ChordSpans(); len(spans) != 2 || spans[1].Start != 4 {
+		t.Errorf("unexpected chord spans %v", spans)
+	}
+}
+
+func TestRead_notPartwise(t *testing.T) {
+	_, err := Read(strings.NewReader(`<?xml version="1.0"?><score-timewise></score-timewise>`))
+	if err != ErrNotPartwise {
+		t.Errorf("expected ErrNotPartwise, got %v", err)
+	}
+}
+
+func TestEncoder_Encode(t *testing.T) {
+	chords := theory.Chords{
+		{Keys: []int{midi.KeyInt("D", 3), midi.KeyInt("F", 3), midi.KeyInt("A", 3), midi.KeyInt("C", 4)}},
+		{Keys: []int{midi.KeyInt("G", 3), midi.KeyInt("B", 3), midi.KeyInt("D", 4), midi.KeyInt("F", 4)}},
+		{Keys: []int{midi.KeyInt("C", 3), midi.KeyInt("E", 3), midi.KeyInt("G", 3), midi.KeyInt("B", 3)}},
+		{Keys: []int{midi.KeyInt("A", 3), midi.KeyInt("C#", 4), midi.KeyInt("E", 4), midi.KeyInt("G", 4), midi.KeyInt("A#", 4)}, Spelling: theory.FlatSpelling},
+		{Keys: []int{midi.KeyInt("G#", 3), midi.KeyInt("C", 4), midi.KeyInt("D#", 4)}, Spelling: theory.FlatSpelling},
+	}
+	// the last two chords are tied across the bar lines
+	spans := theory.NewChordSpans(chords, 384, 384, 192, 384, 192)
+	cMajor := &theory.Scale{Root: 0, Def: theory.ScaleDefMap[theory.MajorScale]}
+
+	buf := bytes.NewBuffer(nil)
+	e := NewEncoder(buf)
+	e.Title = "ii V I"
+	e.Scale = cMajor
+	if err := e.Encode(spans); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		`<kind text="m7">minor-seventh</kind>`,
+		`<kind text="7">dominant</kind>`,
+		`<kind text="Maj7">major-seventh</kind>`,
+		`<kind text="7b9">dominant</kind>`,
+		`<degree-value>9</degree-value>`,
+		`<root-step>A</root-step>`,
+		`<step>B</step>`,
+		`<fifths>0</fifths>`,
+		`<tie type="start"></tie>`,
+		`<measure number="4">`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected the output to contain %s", want)
+		}
+	}
+
+	score, err := Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := []string{}
+	for _, h := range score.Harmonies {
+		got = append(got, h.Root+KindFor(h.Chord.Def()).Text)
+	}
+	if want := []string{"Dm7", "G7", "CMaj7", "A7b9", "Ab"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected harmonies %v, got %v", want, got)
+	}
+	// the played notes are the same once tied notes are merged
+	played := score.Timeline.ChordSpans()
+	if len(played) != len(spans) {
+		t.Fatalf("expected %d chords, got %d", len(spans), len(played))
+	}
+	for i, span := range played {
+		if span.Start != spans[i].Start || span.Duration != spans[i].Duration {
+			t.Errorf("chord %d: expected %d+%d, got %d+%d", i, spans[i].Start, spans[i].Duration, span.Start, span.Duration)
+		}
+		if !reflect.DeepEqual(span.Chord.Keys, chords[i].Keys) {
+			t.Errorf("chord %d: expected keys %v, got %v", i, chords[i].Keys, span.Chord.Keys)
+		}
+	}
+}
+
+func TestWrite_nilChords(t *testing.T) {
+	cMajor := theory.NewChordFromAbbrev("Cmaj").Transpose(36)
+	spans := theory.ChordSpans{
+		{Chord: nil, Start: 0, Duration: 96},
+		{Chord: cMajor, Start: 96, Duration: 192},
+		{Chord: &theory.Chord{}, Start: 288, Duration: 96},
+	}
+	buf := bytes.NewBuffer(nil)
+	if err := Write(buf, spans, 96, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Count(buf.String(), "<rest>"); got != 2 {
+		t.Errorf("expected 2 rests, got %d", got)
+	}
+	score, err := Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(score.Harmonies) != 1 {
+		t.Fatalf("expected a single harmony, got %d", len(score.Harmonies))
+	}
+	played := score.Timeline.ChordSpans()
+	if len(played) != 1 || played[0].Start != 96 || played[0].Duration != 192 {
+		t.Errorf("expected C major from 96 for 192 ticks, got %v", played)
+	}
+}
+
+func TestKind_HalfSteps(t *testing.T) {
+	for _, def := range theory.ChordDefs {
+		t.Run(def.Abbrev, func(t *testing.T) {
+			want := []int{0}
+			for _, hs := range def.HalfSteps {
+				want = append(want, want[len(want)-1]+int(hs))
+			}
+			if got := KindFor(def).HalfSteps(); !reflect.DeepEqual(got, want) {
+				t.Errorf("expected %v, got %v", want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package pcset
+
+import (
+	"strconv"
+	"strings"
+)
+
+// SetClass describes a catalogued set class (Allen Forte's list).
+type SetClass struct {
+	// Forte is the Forte number of the set class such as 4-Z15.
+	Forte string
+	// Prime is the prime form of the set class.
+	Prime Set
+	// IntervalVector is the interval-class vector shared by all the members of
+	// the set class.
+	IntervalVector [6]int
+	// ZPartner is the Forte number of the Z-related set class if any.
+	ZPartner string
+}
+
+// Cardinality returns the number of pitch classes in the set class.
+func (sc *SetClass) Cardinality() int {
+	return len(sc.Prime)
+}
+
+// IsZ reports whether the set class has a Z-related set class.
+func (sc *SetClass) IsZ() bool {
+	return sc.ZPartner != ""
+}
+
+func (sc *SetClass) String() string {
+	return sc.Forte + " " + sc.Prime.String()
+}
+
+var (
+	// Catalog lists all the set classes of cardinality 3 to 9 indexed by their
+	// Forte number.
+	Catalog = map[string]*SetClass{}
+	// SetClasses lists all the set classes of cardinality 3 to 9 in Forte's
+	// order.
+	SetClasses []*SetClass
+
+	classByPrime = map[uint16]*SetClass{}
+)
+
+// forteTrichords to forteHexachords list one member of each set class in
+// Forte's order; the Z prefix is added when the set class has a Z partner.
+// Heptachords, octachords and nonachords are the complements of the
+// pentachords, tetrachords and trichords sharing the same ordinal number.
+var (
+	forteTrichords = []string{
+		"012", "013", "014", "015", "016", "024", "025", "026", "027", "036",
+		"037", "048",
+	}
+	forteTetrachords = []string{
+		"0123", "0124", "0134", "0125", "0126", "0127", "0145", "0156", "0167",
+		"0235", "0135", "0236", "0136", "0237", "0146", "0157", "0347", "0147",
+		"0148", "0158", "0246", "0247", "0257", "0248", "0268", "0358", "0258",
+		"0369", "0137",
+	}
+	fortePentachords = []string{
+		"01234", "01235", "01245", "01236", "01237", "01256", "01267", "02346",
+		"01246", "01346", "02347", "01356", "01248", "01257", "01268", "01347",
+		"01348", "01457", "01367", "01568", "01458", "01478", "02357", "01357",
+		"02358", "02458", "01358", "02368", "01368", "01468", "01369", "01469",
+		"02468", "02469", "02479", "01247", "03458", "01258",
+	}
+	forteHexachords = []string{
+		"012345", "012346", "012356", "012456", "012367", "012567", "012678",
+		"023457", "012357", "013457", "012457", "012467", "013467", "013458",
+		"012458", "014568", "012478", "012578", "013478", "014589", "023468",
+		"012468", "023568", "013468", "013568", "013578", "013469", "013569",
+		"023679", "013679", "014579", "024579", "023579", "013579", "02468T",
+		"012347", "012348", "012378", "023458", "012358", "012368", "012369",
+		"012568", "012569", "023469", "012469", "012479", "012579", "013479",
+		"014679",
+	}
+)
+
+func init() {
+	groups := map[int][]Set{
+		3: parseCatalog(forteTrichords),
+		4: parseCatalog(forteTetrachords),
+		5: parseCatalog(fortePentachords),
+		6: parseCatalog(forteHexachords),
+	}
+	for card := 7; card <= 9; card++ {
+		for _, s := range groups[12-card] {
+			groups[card] = append(groups[card], s.Complement())
+		}
+	}
+
+	for card := 3; card <= 9; card++ {
+		for i, s := range groups[card] {
+			sc := &SetClass{
+				Prime:          s.PrimeForm(),
+				IntervalVector: s.IntervalVector(),
+			}
+			sc.Forte = strconv.Itoa(card) + "-" + strconv.Itoa(i+1)
+			SetClasses = append(SetClasses, sc)
+			classByPrime[New(sc.Prime...).bits()] = sc
+		}
+	}
+
+	// Z-related set classes share their interval vector.
+	for _, a := range SetClasses {
+		for _, b := range SetClasses {
+			if a != b && a.IntervalVector == b.IntervalVector {
+				a.ZPartner = b.Forte
+			}
+		}
+	}
+	for _, sc := range SetClasses {
+		if sc.IsZ() {
+			sc.Forte = strings.Replace(sc.Forte, "-", "-Z", 1)
+			sc.ZPartner = strings.Replace(sc.ZPartner, "-", "-Z", 1)
+		}
+		Catalog[sc.Forte] = sc
+	}
+}
+
+func parseCatalog(entries []string) []Set {
+	sets := make([]Set, len(entries))
+	for i, entry := range entries {
+		pcs := make([]int, len(entry))
+		for j, r := range entry {
+			switch r {
+			case 'T':
+				pcs[j] = 10
+			case 'E':
+				pcs[j] = 11
+			default:
+				pcs[j] = int(r - '0')
+			}
+		}
+		sets[i] = New(pcs...)
+	}
+	return sets
+}
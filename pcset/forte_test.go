@@ -0,0 +1,54 @@
+package pcset
+
+import (
+	"testing"
+)
+
+func TestSet_ForteName(t *testing.T) {
+	tests := []struct {
+		name string
+		set  Set
+		want string
+	}{
+		{"major triad", New(0, 4, 7), "3-11"},
+		{"augmented triad", New(0, 4, 8), "3-12"},
+		{"diminished seventh", New(0, 3, 6, 9), "4-28"},
+		{"all-interval tetrachord", New(0, 1, 4, 6), "4-Z15"},
+		{"pentatonic", New(0, 2, 4, 7, 9), "5-35"},
+		{"whole tone", New(0, 2, 4, 6, 8, 10), "6-35"},
+		{"major scale", New(0, 2, 4, 5, 7, 9, 11), "7-35"},
+		{"octatonic", New(0, 1, 3, 4, 6, 7, 9, 10), "8-28"},
+		{"nonachord", New(0, 1, 2, 3, 4, 5, 6, 7, 8), "9-1"},
+		{"dyad", New(0, 7), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.set.ForteName(); got != tt.want {
+				t.Errorf("Set.ForteName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCatalog(t *testing.T) {
+	counts := map[int]int{}
+	for _, sc := range SetClasses {
+		counts[sc.Cardinality()]++
+		if Catalog[sc.Forte] != sc {
+			t.Errorf("%s isn't indexed in the catalog", sc.Forte)
+		}
+		if sc.IsZ() && Catalog[sc.ZPartner].ZPartner != sc.Forte {
+			t.Errorf("%s and %s should be Z partners", sc.Forte, sc.ZPartner)
+		}
+	}
+	want := map[int]int{3: 12, 4: 29, 5: 38, 6: 50, 7: 38, 8: 29, 9: 12}
+	for card, n := range want {
+		if counts[card] != n {
+			t.Errorf("expected %d set classes of cardinality %d, got %d", n, card, counts[card])
+		}
+	}
+	// Z-related hexachords are each other's complement
+	if got := Catalog["6-Z29"].Prime.Complement().ForteName(); got != "6-Z50" {
+		t.Errorf("expected the complement of 6-Z29 to be 6-Z50, got %s", got)
+	}
+}
@@ -0,0 +1,276 @@
+// Package pcset implements pitch-class set theory on top of the theory
+// package: normal and prime forms, Forte numbers, interval-class vectors,
+// Z-relations and transposition/inversion equivalence.
+package pcset
+
+import (
+	"strings"
+
+	"github.com/go-audio/music/theory"
+)
+
+// Set is a collection of unique pitch classes (0-11). Sets created via New (or
+// any of the From helpers) are sorted in ascending order.
+type Set []int
+
+// New returns a set containing the passed notes reduced to their pitch
+// classes. Duplicates are removed and negative notes are supported.
+func New(notes ...int) Set {
+	var seen [12]bool
+	for _, n := range notes {
+		seen[mod12(n)] = true
+	}
+	s := Set{}
+	for pc, ok := range seen {
+		if ok {
+			s = append(s, pc)
+		}
+	}
+	return s
+}
+
+// FromChord returns the pitch-class set of the chord keys.
+func FromChord(c *theory.Chord) Set {
+	if c == nil {
+		return Set{}
+	}
+	return New(c.Keys...)
+}
+
+// FromChords returns the pitch-class set of all the keys used in the chords.
+func FromChords(chords theory.Chords) Set {
+	return New(chords.UniqueNotes()...)
+}
+
+// FromScale returns the pitch-class set of the notes in the scale.
+func FromScale(s *theory.Scale) Set {
+	if s == nil {
+		return Set{}
+	}
+	return New(s.Notes()...)
+}
+
+// Cardinality returns the number of pitch classes in the set.
+func (s Set) Cardinality() int {
+	return len(s)
+}
+
+// Contains reports whether the pitch class of the passed note is in the set.
+func (s Set) Contains(note int) bool {
+	pc := mod12(note)
+	for _, n := range s {
+		if n == pc {
+			return true
+		}
+	}
+	return false
+}
+
+// Equal reports whether both sets contain the same pitch classes (the order
+// isn't taken in consideration).
+func (s Set) Equal(o Set) bool {
+	return s.bits() == o.bits()
+}
+
+// Transpose returns the set transposed by n half steps (Tn).
+func (s Set) Transpose(n int) Set {
+	out := make([]int, len(s))
+	for i, pc := range s {
+		out[i] = pc + n
+	}
+	return New(out...)
+}
+
+// Invert returns the inversion of the set around 0 (T0I).
+func (s Set) Invert() Set {
+	return s.TransposeInvert(0)
+}
+
+// TransposeInvert returns the set inverted and then transposed by n half steps
+// (TnI).
+func (s Set) TransposeInvert(n int) Set {
+	out := make([]int, len(s))
+	for i, pc := range s {
+		out[i] = n - pc
+	}
+	return New(out...)
+}
+
+// Complement returns the pitch classes not included in the set.
+func (s Set) Complement() Set {
+	bits := s.bits()
+	out := Set{}
+	for pc := 0; pc < 12; pc++ {
+		if bits&(1<<uint(pc)) == 0 {
+			out = append(out, pc)
+		}
+	}
+	return out
+}
+
+// NormalForm returns the most compact ordering of the set (Rahn's algorithm).
+// The result is ordered from the first pitch class of the compact rotation and
+// isn't transposed to 0.
+func (s Set) NormalForm() Set {
+	sorted := New(s...)
+	if len(sorted) < 2 {
+		return sorted
+	}
+	var best Set
+	for i := range sorted {
+		rotation := make(Set, len(sorted))
+		for j := range sorted {
+			rotation[j] = sorted[(i+j)%len(sorted)]
+		}
+		if best == nil || morePacked(rotation, best) {
+			best = rotation
+		}
+	}
+	return best
+}
+
+// PrimeForm returns the prime form of the set: the most compact form of the
+// set or of its inversion, transposed to start on 0.
+func (s Set) PrimeForm() Set {
+	if len(s) == 0 {
+		return Set{}
+	}
+	a := zeroed(s.NormalForm())
+	b := zeroed(s.Invert().NormalForm())
+	if morePacked(b, a) {
+		return b
+	}
+	return a
+}
+
+// IntervalVector returns the interval-class vector of the set, the number of
+// occurrences of each interval class from 1 (minor second/major seventh) to 6
+// (tritone).
+func (s Set) IntervalVector() [6]int {
+	var vec [6]int
+	u := New(s...)
+	for i := 0; i < len(u); i++ {
+		for j := i + 1; j < len(u); j++ {
+			ic := mod12(u[j] - u[i])
+			if ic > 6 {
+				ic = 12 - ic
+			}
+			vec[ic-1]++
+		}
+	}
+	return vec
+}
+
+// ForteName returns the Forte number of the set class the set belongs to
+// (for instance 3-11 for major and minor triads). An empty string is returned
+// for sets that don't have a Forte number (cardinality below 3 or above 9).
+func (s Set) ForteName() string {
+	if sc := s.SetClass(); sc != nil {
+		return sc.Forte
+	}
+	return ""
+}
+
+// SetClass returns the catalogued set class the set belongs to or nil if the
+// set cardinality isn't catalogued.
+func (s Set) SetClass() *SetClass {
+	return classByPrime[New(s.PrimeForm()...).bits()]
+}
+
+// IsTranspositionOf reports whether the set is a transposition of the other
+// set and if so, the number of half steps n such as Tn(o) = s.
+func (s Set) IsTranspositionOf(o Set) (n int, ok bool) {
+	if len(New(s...)) != len(New(o...)) {
+		return 0, false
+	}
+	for n = 0; n < 12; n++ {
+		if o.Transpose(n).Equal(s) {
+			return n, true
+		}
+	}
+	return 0, false
+}
+
+// IsInversionOf reports whether the set is an inversion of the other set and
+// if so, the index n such as TnI(o) = s.
+func (s Set) IsInversionOf(o Set) (n int, ok bool) {
+	if len(New(s...)) != len(New(o...)) {
+		return 0, false
+	}
+	for n = 0; n < 12; n++ {
+		if o.TransposeInvert(n).Equal(s) {
+			return n, true
+		}
+	}
+	return 0, false
+}
+
+// Equivalent reports whether both sets belong to the same set class, meaning
+// that one can be obtained from the other by transposition and/or inversion.
+func (s Set) Equivalent(o Set) bool {
+	return s.PrimeForm().Equal(o.PrimeForm())
+}
+
+// ZRelated reports whether both sets are Z-related: they share the same
+// interval-class vector without being transpositionally or inversionally
+// equivalent.
+func (s Set) ZRelated(o Set) bool {
+	return s.IntervalVector() == o.IntervalVector() && !s.Equivalent(o)
+}
+
+// ZPartner returns the prime form of the set class Z-related to the set or nil
+// if the set doesn't have a Z-related set class.
+func (s Set) ZPartner() Set {
+	sc := s.SetClass()
+	if sc == nil || sc.ZPartner == "" {
+		return nil
+	}
+	return Catalog[sc.ZPartner].Prime
+}
+
+// String returns the set using the common integer notation where 10 and 11
+// are written T and E, for instance [0,4,7] or [0,1,T].
+func (s Set) String() string {
+	strs := make([]string, len(s))
+	for i, pc := range s {
+		strs[i] = pcNames[mod12(pc)]
+	}
+	return "[" + strings.Join(strs, ",") + "]"
+}
+
+var pcNames = []string{"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "T", "E"}
+
+// bits returns a bitmask representation of the pitch classes in the set.
+func (s Set) bits() uint16 {
+	var b uint16
+	for _, pc := range s {
+		b |= 1 << uint(mod12(pc))
+	}
+	return b
+}
+
+// morePacked reports whether the ordered set a is more packed than b using
+// Rahn's criteria: smallest span first and then smallest intervals between the
+// first note and the notes found from the end of the set.
+func morePacked(a, b Set) bool {
+	for i := len(a) - 1; i > 0; i-- {
+		da, db := mod12(a[i]-a[0]), mod12(b[i]-b[0])
+		if da != db {
+			return da < db
+		}
+	}
+	return mod12(a[0]) < mod12(b[0])
+}
+
+// zeroed transposes the ordered set so it starts on 0.
+func zeroed(s Set) Set {
+	out := make(Set, len(s))
+	for i, pc := range s {
+		out[i] = mod12(pc - s[0])
+	}
+	return out
+}
+
+func mod12(n int) int {
+	return ((n % 12) + 12) % 12
+}
@@ -0,0 +1,160 @@
+package pcset
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-audio/midi"
+	"github.com/go-audio/music/theory"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name  string
+		notes []int
+		want  Set
+	}{
+		{"empty", nil, Set{}},
+		{"duplicates across octaves", []int{60, 64, 67, 72, 76}, Set{0, 4, 7}},
+		{"negative notes", []int{-1, -12, 13}, Set{0, 1, 11}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := New(tt.notes...); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("New() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromChordAndScale(t *testing.T) {
+	chord := &theory.Chord{Keys: []int{
+		midi.KeyInt("F#", 3),
+		midi.KeyInt("A", 3),
+		midi.KeyInt("D", 4),
+	}}
+	if got, want := FromChord(chord), (Set{2, 6, 9}); !reflect.DeepEqual(got, want) {
+		t.Errorf("FromChord() = %v, want %v", got, want)
+	}
+	scale := &theory.Scale{Root: 2, Def: theory.ScaleDefMap[theory.DorianScale]}
+	if got, want := FromScale(scale), (Set{0, 2, 4, 5, 7, 9, 11}); !reflect.DeepEqual(got, want) {
+		t.Errorf("FromScale() = %v, want %v", got, want)
+	}
+}
+
+func TestSet_NormalForm(t *testing.T) {
+	tests := []struct {
+		name string
+		set  Set
+		want Set
+	}{
+		{"C major", New(0, 4, 7), Set{0, 4, 7}},
+		{"C major 1st inversion", New(4, 7, 12), Set{0, 4, 7}},
+		{"G7", New(7, 11, 2, 5), Set{11, 2, 5, 7}},
+		{"wrapping around", New(10, 11, 1), Set{10, 11, 1}},
+		{"diminished seventh", New(3, 6, 9, 0), Set{0, 3, 6, 9}},
+		{"Rahn tie break", New(0, 1, 3, 7, 8), Set{7, 8, 0, 1, 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.set.NormalForm(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Set.NormalForm() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSet_PrimeForm(t *testing.T) {
+	tests := []struct {
+		name string
+		set  Set
+		want Set
+	}{
+		{"major triad", New(0, 4, 7), Set{0, 3, 7}},
+		{"minor triad", New(9, 0, 4), Set{0, 3, 7}},
+		{"dominant seventh", New(7, 11, 2, 5), Set{0, 2, 5, 8}},
+		{"major scale", New(0, 2, 4, 5, 7, 9, 11), Set{0, 1, 3, 5, 6, 8, 10}},
+		{"5-20", New(0, 1, 3, 7, 8), Set{0, 1, 5, 6, 8}},
+		{"6-Z29", New(0, 1, 3, 6, 8, 9), Set{0, 2, 3, 6, 7, 9}},
+		{"single note", New(5), Set{0}},
+		{"empty", Set{}, Set{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.set.PrimeForm(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Set.PrimeForm() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSet_IntervalVector(t *testing.T) {
+	tests := []struct {
+		name string
+		set  Set
+		want [6]int
+	}{
+		{"major triad", New(0, 4, 7), [6]int{0, 0, 1, 1, 1, 0}},
+		{"major scale", New(0, 2, 4, 5, 7, 9, 11), [6]int{2, 5, 4, 3, 6, 1}},
+		{"whole tone", New(0, 2, 4, 6, 8, 10), [6]int{0, 6, 0, 6, 0, 3}},
+		{"chromatic", New(0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11), [6]int{12, 12, 12, 12, 12, 6}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.set.IntervalVector(); got != tt.want {
+				t.Errorf("Set.IntervalVector() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSet_Complement(t *testing.T) {
+	if got, want := New(0, 2, 4, 5, 7, 9, 11).Complement(), (Set{1, 3, 6, 8, 10}); !reflect.DeepEqual(got, want) {
+		t.Errorf("Set.Complement() = %v, want %v", got, want)
+	}
+}
+
+func TestSet_TranspositionAndInversion(t *testing.T) {
+	cMaj, aMin, dMaj := New(0, 4, 7), New(9, 0, 4), New(2, 6, 9)
+	if n, ok := dMaj.IsTranspositionOf(cMaj); !ok || n != 2 {
+		t.Errorf("expected D major to be T2 of C major, got %d, %t", n, ok)
+	}
+	if _, ok := aMin.IsTranspositionOf(cMaj); ok {
+		t.Errorf("A minor isn't a transposition of C major")
+	}
+	if n, ok := aMin.IsInversionOf(cMaj); !ok || n != 4 {
+		t.Errorf("expected A minor to be T4I of C major, got %d, %t", n, ok)
+	}
+	if !aMin.Equivalent(cMaj) {
+		t.Errorf("expected major and minor triads to be equivalent")
+	}
+	if New(0, 3, 6).Equivalent(cMaj) {
+		t.Errorf("didn't expect a diminished triad to be equivalent to a major triad")
+	}
+	if got, want := cMaj.TransposeInvert(4), aMin; !got.Equal(want) {
+		t.Errorf("Set.TransposeInvert(4) = %v, want %v", got, want)
+	}
+}
+
+func TestSet_ZRelated(t *testing.T) {
+	allInterval := New(0, 1, 4, 6)
+	partner := New(0, 1, 3, 7)
+	if !allInterval.ZRelated(partner) {
+		t.Errorf("expected %v and %v to be Z-related", allInterval, partner)
+	}
+	if allInterval.ZRelated(allInterval.Transpose(3)) {
+		t.Errorf("a set can't be Z-related with one of its transpositions")
+	}
+	if got := allInterval.ZPartner(); !got.Equal(partner.PrimeForm()) {
+		t.Errorf("Set.ZPartner() = %v, want %v", got, partner.PrimeForm())
+	}
+	if got := New(0, 4, 7).ZPartner(); got != nil {
+		t.Errorf("Set.ZPartner() = %v, want nil", got)
+	}
+}
+
+func TestSet_String(t *testing.T) {
+	if got, want := New(11, 10, 0).String(), "[0,T,E]"; got != want {
+		t.Errorf("Set.String() = %v, want %v", got, want)
+	}
+}
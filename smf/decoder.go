@@ -0,0 +1,156 @@
+// Package smf converts Standard MIDI Files from and to the timed events of the
+// theory package.
+package smf
+
+import (
+	"io"
+	"sort"
+
+	"github.com/go-audio/midi"
+	"github.com/go-audio/music/theory"
+)
+
+// Decoder reads a Standard MIDI File and merges all its tracks and channels in
+// a single theory.Timeline.
+type Decoder struct {
+	r io.Reader
+	// IgnoreSustain disables the sustain pedal handling, notes then stop as
+	// soon as their key is released.
+	IgnoreSustain bool
+	// IncludeDrums includes the notes played on the percussion channel which
+	// are ignored by default since they aren't pitched.
+	IncludeDrums bool
+}
+
+// NewDecoder returns a decoder reading from the passed reader.
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{r: r}
+}
+
+// Timeline decodes the MIDI file and returns its notes. Notes released while
+// the sustain pedal (CC64) is held on their channel are extended until the
+// pedal is released or the same key is played again.
+func (d *Decoder) Timeline() (*theory.Timeline, error) {
+	dec := midi.NewDecoder(d.r)
+	if err := dec.Decode(); err != nil {
+		return nil, err
+	}
+	return d.timelineFromTracks(dec.Tracks, int(dec.TicksPerQuarterNote)), nil
+}
+
+// ChordSpans decodes the MIDI file and segments its notes in chords (see
+// theory.Timeline.ChordSpans).
+func (d *Decoder) ChordSpans() (theory.ChordSpans, error) {
+	tl, err := d.Timeline()
+	if err != nil {
+		return nil, err
+	}
+	return tl.ChordSpans(), nil
+}
+
+// ReadChords decodes a MIDI file using the default settings and returns its
+// timed chord sequence. Use each span Chord.Def() to identify the chords.
+func ReadChords(r io.Reader) (theory.ChordSpans, error) {
+	return NewDecoder(r).ChordSpans()
+}
+
+// TimelineFromTracks converts already decoded MIDI tracks in a timeline using
+// the default settings.
+func TimelineFromTracks(tracks []*midi.Track, ticksPerQuarter int) *theory.Timeline {
+	return (&Decoder{}).timelineFromTracks(tracks, ticksPerQuarter)
+}
+
+// absEvent is a MIDI event with its absolute position in its track. The
+// decoder's AbsTicks can't be used since it keeps counting across tracks.
+type absEvent struct {
+	tick int
+	ev   *midi.Event
+}
+
+type noteKey struct {
+	channel int
+	key     int
+}
+
+func (d *Decoder) timelineFromTracks(tracks []*midi.Track, ticksPerQuarter int) *theory.Timeline {
+	tl := theory.NewTimeline(ticksPerQuarter)
+
+	events := []absEvent{}
+	for _, track := range tracks {
+		var tick int
+		for _, ev := range track.Events {
+			tick += int(ev.TimeDelta)
+			events = append(events, absEvent{tick: tick, ev: ev})
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].tick < events[j].tick })
+
+	active := map[noteKey]*theory.NoteEvent{}
+	sustained := map[noteKey]*theory.NoteEvent{}
+	pedalDown := map[int]bool{}
+	var order []noteKey
+	var lastTick int
+
+	stop := func(k noteKey, notes map[noteKey]*theory.NoteEvent, tick int) {
+		if n, ok := notes[k]; ok {
+			n.Duration = tick - n.Start
+			tl.Add(*n)
+			delete(notes, k)
+		}
+	}
+
+	noteOn := midi.EventByteMap["NoteOn"]
+	noteOff := midi.EventByteMap["NoteOff"]
+	controlChange := midi.EventByteMap["ControlChange"]
+	tempo := midi.MetaByteMap["Tempo"]
+
+	for _, ae := range events {
+		ev, tick := ae.ev, ae.tick
+		lastTick = tick
+		channel := int(ev.MsgChan)
+		switch {
+		case ev.MsgType == midi.EventByteMap["Meta"]:
+			if ev.Cmd == tempo && ev.MsPerQuartNote > 0 {
+				tl.SetTempo(tick, 60000000/float64(ev.MsPerQuartNote))
+			}
+		case (ev.MsgType == noteOn || ev.MsgType == noteOff) && channel == theory.DrumChannel && !d.IncludeDrums:
+		case ev.MsgType == noteOn && ev.Velocity > 0:
+			k := noteKey{channel: channel, key: int(ev.Note)}
+			// a key played again ends the previous note
+			stop(k, sustained, tick)
+			stop(k, active, tick)
+			active[k] = &theory.NoteEvent{
+				Key: k.key, Start: tick, Velocity: int(ev.Velocity), Channel: channel,
+			}
+			order = append(order, k)
+		case ev.MsgType == noteOn || ev.MsgType == noteOff:
+			k := noteKey{channel: channel, key: int(ev.Note)}
+			if pedalDown[channel] && !d.IgnoreSustain {
+				if n, ok := active[k]; ok {
+					sustained[k] = n
+					delete(active, k)
+				}
+				continue
+			}
+			stop(k, active, tick)
+		case ev.MsgType == controlChange && ev.Controller == theory.SustainPedal:
+			down := ev.NewValue >= 64
+			if pedalDown[channel] && !down {
+				for k := range sustained {
+					if k.channel == channel {
+						stop(k, sustained, tick)
+					}
+				}
+			}
+			pedalDown[channel] = down
+		}
+	}
+
+	// close the notes still playing at the end of the file
+	for _, k := range order {
+		stop(k, sustained, lastTick)
+		stop(k, active, lastTick)
+	}
+	tl.Sort()
+	return tl
+}
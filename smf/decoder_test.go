@@ -0,0 +1,130 @@
+package smf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/go-audio/midi"
+	"github.com/go-audio/music/theory"
+)
+
+// smfData builds a standard MIDI file from raw track data.
+func smfData(ppqn uint16, tracks ...[]byte) []byte {
+	buf := bytes.NewBuffer(nil)
+	buf.WriteString("MThd")
+	binary.Write(buf, binary.BigEndian, uint32(6))
+	binary.Write(buf, binary.BigEndian, uint16(1))
+	binary.Write(buf, binary.BigEndian, uint16(len(tracks)))
+	binary.Write(buf, binary.BigEndian, ppqn)
+	for _, tr := range tracks {
+		tr = append(tr, 0x00, 0xFF, 0x2F, 0x00)
+		buf.WriteString("MTrk")
+		binary.Write(buf, binary.BigEndian, uint32(len(tr)))
+		buf.Write(tr)
+	}
+	return buf.Bytes()
+}
+
+// ev encodes a delta time followed by the event bytes.
+func ev(delta uint32, data ...byte) []byte {
+	return append(midi.EncodeVarint(delta), data...)
+}
+
+func join(evs ...[]byte) []byte {
+	out := []byte{}
+	for _, e := range evs {
+		out = append(out, e...)
+	}
+	return out
+}
+
+func testFile() []byte {
+	c, e, g := byte(midi.KeyInt("C", 3)), byte(midi.KeyInt("E", 3)), byte(midi.KeyInt("G", 3))
+	f, a, c4 := byte(midi.KeyInt("F", 3)), byte(midi.KeyInt("A", 3)), byte(midi.KeyInt("C", 4))
+	tempoTrack := join(
+		ev(0, 0xFF, 0x51, 0x03, 0x09, 0x27, 0xC0), // 100 bpm
+	)
+	pianoTrack := join(
+		// C major for a bar
+		ev(0, 0x90, c, 100), ev(0, 0x90, e, 100), ev(0, 0x90, g, 100),
+		ev(384, 0x80, c, 0), ev(0, 0x80, e, 0), ev(0, 0x80, g, 0),
+		// pedal down, F major played short but sustained for a bar
+		ev(0, 0xB0, theory.SustainPedal, 127),
+		ev(0, 0x90, f, 90), ev(0, 0x90, a, 90), ev(0, 0x90, c4, 90),
+		ev(96, 0x90, f, 0), ev(0, 0x90, a, 0), ev(0, 0x90, c4, 0),
+		ev(288, 0xB0, theory.SustainPedal, 0),
+	)
+	drumTrack := join(
+		ev(0, 0x99, 36, 100), ev(0, 0x99, 38, 100), ev(0, 0x99, 42, 100),
+		ev(96, 0x89, 36, 0), ev(0, 0x89, 38, 0), ev(0, 0x89, 42, 0),
+	)
+	return smfData(96, tempoTrack, pianoTrack, drumTrack)
+}
+
+func TestReadChords(t *testing.T) {
+	spans, err := ReadChords(bytes.NewReader(testFile()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		name            string
+		start, duration int
+	}{
+		{"C Major", 0, 384},
+		{"F Major", 384, 384},
+	}
+	if len(spans) != len(want) {
+		t.Fatalf("expected %d chords, got %d (%s)", len(want), len(spans), spans.Chords())
+	}
+	for i, w := range want {
+		if got := spans[i].Chord.Def().String(); got != w.name {
+			t.Errorf("chord %d: expected %s, got %s", i, w.name, got)
+		}
+		if spans[i].Start != w.start || spans[i].Duration != w.duration {
+			t.Errorf("chord %d: expected %d+%d, got %d+%d", i, w.start, w.duration, spans[i].Start, spans[i].Duration)
+		}
+	}
+}
+
+func TestDecoder_Timeline(t *testing.T) {
+	tests := []struct {
+		name          string
+		dec           *Decoder
+		wantNotes     int
+		wantFDuration int
+	}{
+		{"default", &Decoder{}, 6, 384},
+		{"ignore sustain", &Decoder{IgnoreSustain: true}, 6, 96},
+		{"with drums", &Decoder{IncludeDrums: true}, 9, 384},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.dec.r = bytes.NewReader(testFile())
+			tl, err := tt.dec.Timeline()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tl.TicksPerQuarter != 96 {
+				t.Errorf("expected 96 ticks per quarter, got %d", tl.TicksPerQuarter)
+			}
+			if got := tl.Tempo.BPMAt(0); got != 100 {
+				t.Errorf("expected a 100 bpm tempo, got %f", got)
+			}
+			if len(tl.Notes) != tt.wantNotes {
+				t.Fatalf("expected %d notes, got %d", tt.wantNotes, len(tl.Notes))
+			}
+			for _, n := range tl.Notes {
+				if n.Key == midi.KeyInt("F", 3) && n.Duration != tt.wantFDuration {
+					t.Errorf("expected F3 to last %d ticks, got %d", tt.wantFDuration, n.Duration)
+				}
+			}
+		})
+	}
+}
+
+func TestReadChords_invalidFile(t *testing.T) {
+	if _, err := ReadChords(bytes.NewReader([]byte("RIFF0000WAVE"))); err == nil {
+		t.Errorf("expected an error when decoding a non MIDI file")
+	}
+}
@@ -0,0 +1,211 @@
+package smf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"sort"
+
+	"github.com/go-audio/midi"
+	"github.com/go-audio/music/theory"
+)
+
+// ErrFormatNotSupported is returned when trying to write a MIDI file format
+// other than 0 (single track) or 1 (synchronous tracks).
+var ErrFormatNotSupported = errors.New("only MIDI file formats 0 and 1 are supported")
+
+// Encoder writes timed notes as a Standard MIDI File. The file starts with the
+// tempo, time signature and key signature (when a scale is set). When using
+// format 0 all the tracks are merged in a single track.
+type Encoder struct {
+	w io.Writer
+	// Format is the MIDI file format, midi.SingleTrack (0) or
+	// midi.Syncronous (1).
+	Format uint16
+	// TicksPerQuarterNote is the resolution used to express the timing of the
+	// notes.
+	TicksPerQuarterNote uint16
+	// BPM is the tempo of the file (theory.DefaultBPM when not set).
+	BPM float64
+	// TimeSignature is the numerator and denominator of the time signature
+	// (4/4 when not set or when the denominator isn't a power of 2).
+	TimeSignature [2]int
+	// Scale, if set, is used to write the key signature.
+	Scale *theory.Scale
+
+	tracks []track
+}
+
+type track struct {
+	name  string
+	notes []theory.NoteEvent
+}
+
+// NewEncoder returns an encoder writing to w.
+func NewEncoder(w io.Writer, format uint16, ticksPerQuarterNote uint16) *Encoder {
+	return &Encoder{w: w, Format: format, TicksPerQuarterNote: ticksPerQuarterNote}
+}
+
+// AddTrack adds a named track containing the passed notes (timed using the
+// encoder resolution).
+func (e *Encoder) AddTrack(name string, notes []theory.NoteEvent) {
+	e.tracks = append(e.tracks, track{name: name, notes: notes})
+}
+
+// AddChords adds a track playing the chord spans voiced using the passed
+// strategy (theory.AsPlayedVoicing if nil).
+func (e *Encoder) AddChords(name string, spans theory.ChordSpans, voicing theory.Voicing) {
+	e.AddTrack(name, spans.Notes(voicing, 100))
+}
+
+// AddScaleRun adds a track playing a run of the scale from the start note,
+// moving the passed number of steps, each note lasting noteTicks.
+func (e *Encoder) AddScaleRun(name string, s *theory.Scale, start, steps, noteTicks int) {
+	notes := []theory.NoteEvent{}
+	for i, k := range s.Run(start, steps) {
+		notes = append(notes, theory.NoteEvent{Key: k, Start: i * noteTicks, Duration: noteTicks, Velocity: 100})
+	}
+	e.AddTrack(name, notes)
+}
+
+// Write writes the MIDI file.
+func (e *Encoder) Write() error {
+	if e.Format != midi.SingleTrack && e.Format != midi.Syncronous {
+		return ErrFormatNotSupported
+	}
+	tracks := [][]byte{}
+	switch e.Format {
+	case midi.SingleTrack:
+		merged := track{}
+		for _, t := range e.tracks {
+			if merged.name == "" {
+				merged.name = t.name
+			}
+			merged.notes = append(merged.notes, t.notes...)
+		}
+		tracks = append(tracks, e.trackData(merged, true))
+	default:
+		tracks = append(tracks, e.trackData(track{}, true))
+		for _, t := range e.tracks {
+			tracks = append(tracks, e.trackData(t, false))
+		}
+	}
+
+	buf := bytes.NewBuffer(nil)
+	buf.WriteString("MThd")
+	binary.Write(buf, binary.BigEndian, uint32(6))
+	binary.Write(buf, binary.BigEndian, e.Format)
+	binary.Write(buf, binary.BigEndian, uint16(len(tracks)))
+	binary.Write(buf, binary.BigEndian, e.ticksPerQuarterNote())
+	for _, data := range tracks {
+		buf.WriteString("MTrk")
+		binary.Write(buf, binary.BigEndian, uint32(len(data)))
+		buf.Write(data)
+	}
+	_, err := e.w.Write(buf.Bytes())
+	return err
+}
+
+// WriteChords writes a format 1 MIDI file with a single track playing the
+// chord spans.
+func WriteChords(w io.Writer, spans theory.ChordSpans, ticksPerQuarterNote uint16, bpm float64, voicing theory.Voicing, s *theory.Scale) error {
+	e := NewEncoder(w, midi.Syncronous, ticksPerQuarterNote)
+	e.BPM = bpm
+	e.Scale = s
+	e.AddChords("Chords", spans, voicing)
+	return e.Write()
+}
+
+func (e *Encoder) ticksPerQuarterNote() uint16 {
+	if e.TicksPerQuarterNote == 0 {
+		return 96
+	}
+	return e.TicksPerQuarterNote
+}
+
+// trackData returns the encoded events of a track chunk. The conductor track
+// (or the only track of a format 0 file) contains the tempo and signatures.
+func (e *Encoder) trackData(t track, conductor bool) []byte {
+	buf := bytes.NewBuffer(nil)
+	if t.name != "" {
+		buf.Write(midi.TrackName(t.name).Encode())
+	}
+	if conductor {
+		bpm := e.BPM
+		if bpm <= 0 {
+			bpm = theory.DefaultBPM
+		}
+		buf.Write(midi.TempoEvent(bpm).Encode())
+		buf.Write(timeSignatureData(e.TimeSignature))
+		if e.Scale != nil {
+			buf.Write(keySignatureData(e.Scale))
+		}
+	}
+
+	type timedEvent struct {
+		tick int
+		ev   *midi.Event
+	}
+	events := []timedEvent{}
+	for _, n := range t.notes {
+		// notes can't start before the beginning of the track, zero length
+		// notes would have their note off sorted before their note on
+		start := n.Start
+		if start < 0 {
+			start = 0
+		}
+		if n.End() <= start {
+			continue
+		}
+		vel := n.Velocity
+		if vel <= 0 || vel > 127 {
+			vel = 100
+		}
+		events = append(events,
+			timedEvent{tick: start, ev: midi.NoteOn(n.Channel, n.Key, vel)},
+			timedEvent{tick: n.End(), ev: midi.NoteOff(n.Channel, n.Key)},
+		)
+	}
+	// note offs first when events happen at the same time so repeated notes
+	// aren't cut.
+	sort.SliceStable(events, func(i, j int) bool {
+		if events[i].tick == events[j].tick {
+			return events[i].ev.MsgType < events[j].ev.MsgType
+		}
+		return events[i].tick < events[j].tick
+	})
+	var lastTick int
+	for _, te := range events {
+		te.ev.TimeDelta = uint32(te.tick - lastTick)
+		buf.Write(te.ev.Encode())
+		lastTick = te.tick
+	}
+	buf.Write(midi.EndOfTrack().Encode())
+	return buf.Bytes()
+}
+
+// timeSignatureData encodes a time signature meta event (with a delta time of
+// 0). The midi package can't encode this event. Time signatures that can't be
+// encoded (denominators which aren't a power of 2) are written as 4/4.
+func timeSignatureData(sig [2]int) []byte {
+	num, denom := sig[0], sig[1]
+	if num <= 0 || num > 255 || denom <= 0 || denom&(denom-1) != 0 {
+		num, denom = 4, 4
+	}
+	var denomPow byte
+	for d := denom; d > 1; d >>= 1 {
+		denomPow++
+	}
+	return []byte{0x00, 0xFF, midi.MetaByteMap["Time Signature"], 0x04, byte(num), denomPow, 0x18, 0x08}
+}
+
+// keySignatureData encodes a key signature meta event (with a delta time of
+// 0). The midi package can't encode this event.
+func keySignatureData(s *theory.Scale) []byte {
+	var mode byte
+	if s.IsMinor() {
+		mode = 1
+	}
+	return []byte{0x00, 0xFF, midi.MetaByteMap["Key Signature"], 0x02, byte(int8(s.KeySignature())), mode}
+}
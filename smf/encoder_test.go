@@ -0,0 +1,188 @@
+package smf
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/go-audio/midi"
+	"github.com/go-audio/music/theory"
+)
+
+// decodeFile decodes an encoded MIDI file using the midi package.
+func decodeFile(t *testing.T, data []byte) *midi.Decoder {
+	t.Helper()
+	dec := midi.NewDecoder(bytes.NewReader(data))
+	if err := dec.Decode(); err != nil {
+		t.Fatalf("failed to decode the encoded file - %v", err)
+	}
+	return dec
+}
+
+func TestEncoder_AddChords(t *testing.T) {
+	chords := theory.Chords{
+		{Keys: []int{midi.KeyInt("D", 3), midi.KeyInt("F", 3), midi.KeyInt("A", 3)}},
+		{Keys: []int{midi.KeyInt("G", 3), midi.KeyInt("A#", 3), midi.KeyInt("D", 4)}},
+		{Keys: []int{midi.KeyInt("A", 3), midi.KeyInt("C#", 4), midi.KeyInt("E", 4)}},
+	}
+	dMinor := &theory.Scale{Root: 2, Def: theory.ScaleDefMap[theory.NaturalMinorScale]}
+
+	tests := []struct {
+		name       string
+		format     uint16
+		voicing    theory.Voicing
+		wantTracks int
+		wantNames  []string
+	}{
+		{name: "format 1, as played", format: midi.Syncronous, wantTracks: 2,
+			wantNames: []string{"D Minor", "G Minor", "A Major"}},
+		{name: "format 0, close voicing", format: midi.SingleTrack, voicing: theory.CloseVoicing(3), wantTracks: 1,
+			wantNames: []string{"D Minor", "G Minor", "A Major"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := bytes.NewBuffer(nil)
+			e := NewEncoder(buf, tt.format, 96)
+			e.BPM = 100
+			e.TimeSignature = [2]int{3, 4}
+			e.Scale = dMinor
+			e.AddChords("Piano", theory.NewChordSpans(chords, 288), tt.voicing)
+			if err := e.Write(); err != nil {
+				t.Fatal(err)
+			}
+
+			dec := decodeFile(t, buf.Bytes())
+			if len(dec.Tracks) != tt.wantTracks {
+				t.Fatalf("expected %d tracks, got %d", tt.wantTracks, len(dec.Tracks))
+			}
+			var (
+				bpm     uint32
+				timeSig *midi.TimeSignature
+				key     int8
+				mode    uint32
+				names   []string
+			)
+			for _, ev := range dec.Tracks[0].Events {
+				switch {
+				case ev.Cmd == midi.MetaByteMap["Tempo"]:
+					bpm = ev.Bpm
+				case ev.Cmd == midi.MetaByteMap["Time Signature"]:
+					timeSig = ev.TimeSignature
+				case ev.Cmd == midi.MetaByteMap["Key Signature"]:
+					key, mode = int8(ev.Key), ev.Scale
+				}
+			}
+			for _, tr := range dec.Tracks {
+				for _, ev := range tr.Events {
+					if ev.Cmd == midi.MetaByteMap["Sequence/Track name"] {
+						names = append(names, ev.SeqTrackName)
+					}
+				}
+			}
+			if bpm != 100 {
+				t.Errorf("expected 100 bpm, got %d", bpm)
+			}
+			if timeSig == nil || timeSig.Numerator != 3 || timeSig.Denum() != 4 {
+				t.Errorf("expected a 3/4 time signature, got %v", timeSig)
+			}
+			if key != -1 || mode != 1 {
+				t.Errorf("expected the D minor key signature (-1, 1), got (%d, %d)", key, mode)
+			}
+			if !reflect.DeepEqual(names, []string{"Piano"}) {
+				t.Errorf("expected the Piano track name, got %v", names)
+			}
+
+			spans, err := ReadChords(bytes.NewReader(buf.Bytes()))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := []string{}
+			for _, span := range spans {
+				got = append(got, span.Chord.Def().String())
+			}
+			if !reflect.DeepEqual(got, tt.wantNames) {
+				t.Errorf("expected %v, got %v", tt.wantNames, got)
+			}
+			if len(spans) == 3 && spans[2].Start != 576 {
+				t.Errorf("expected the last chord to start at tick 576, got %d", spans[2].Start)
+			}
+		})
+	}
+}
+
+func TestEncoder_AddScaleRun(t *testing.T) {
+	gMajor := &theory.Scale{Root: 7, Def: theory.ScaleDefMap[theory.MajorScale]}
+	buf := bytes.NewBuffer(nil)
+	e := NewEncoder(buf, midi.Syncronous, 96)
+	e.Scale = gMajor
+	e.AddScaleRun("G Major", gMajor, midi.KeyInt("G", 3), 7, 48)
+	if err := e.Write(); err != nil {
+		t.Fatal(err)
+	}
+	tl, err := NewDecoder(bytes.NewReader(buf.Bytes())).Timeline()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := []int{}
+	for _, n := range tl.Notes {
+		got = append(got, n.Key)
+		if n.Duration != 48 {
+			t.Errorf("expected notes lasting 48 ticks, got %d", n.Duration)
+		}
+	}
+	if want := gMajor.Run(midi.KeyInt("G", 3), 7); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestEncoder_AddTrack_invalidTiming(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	e := NewEncoder(buf, midi.Syncronous, 96)
+	e.AddTrack("Notes", []theory.NoteEvent{
+		// starts before the track, clamped to 0
+		{Key: midi.KeyInt("C", 3), Start: -48, Duration: 96},
+		// zero length, skipped instead of hanging
+		{Key: midi.KeyInt("E", 3), Start: 96, Duration: 0},
+		{Key: midi.KeyInt("G", 3), Start: 96, Duration: 96},
+	})
+	if err := e.Write(); err != nil {
+		t.Fatal(err)
+	}
+	tl, err := NewDecoder(bytes.NewReader(buf.Bytes())).Timeline()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []theory.NoteEvent{
+		{Key: midi.KeyInt("C", 3), Start: 0, Duration: 48, Velocity: 100},
+		{Key: midi.KeyInt("G", 3), Start: 96, Duration: 96, Velocity: 100},
+	}
+	if !reflect.DeepEqual(tl.Notes, want) {
+		t.Errorf("expected %v, got %v", want, tl.Notes)
+	}
+}
+
+func TestTimeSignatureData(t *testing.T) {
+	tests := []struct {
+		sig       [2]int
+		num, pow2 byte
+	}{
+		{[2]int{3, 4}, 3, 2},
+		{[2]int{6, 8}, 6, 3},
+		{[2]int{}, 4, 2},
+		// can't be encoded
+		{[2]int{4, 3}, 4, 2},
+	}
+	for _, tt := range tests {
+		data := timeSignatureData(tt.sig)
+		if data[4] != tt.num || data[5] != tt.pow2 {
+			t.Errorf("%v: expected %d/2^%d, got %d/2^%d", tt.sig, tt.num, tt.pow2, data[4], data[5])
+		}
+	}
+}
+
+func TestEncoder_Write_unsupportedFormat(t *testing.T) {
+	e := NewEncoder(bytes.NewBuffer(nil), 2, 96)
+	if err := e.Write(); err != ErrFormatNotSupported {
+		t.Errorf("expected ErrFormatNotSupported, got %v", err)
+	}
+}
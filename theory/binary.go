@@ -0,0 +1,255 @@
+package theory
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ChordsFormatVersion is the version of the binary format written by
+// WriteChordSpans.
+const ChordsFormatVersion = 1
+
+// chordsMagic starts the binary encoded chord sequences.
+var chordsMagic = []byte("GACS")
+
+const (
+	// flagTimed indicates that the spans timing is encoded.
+	flagTimed = 1 << iota
+)
+
+var (
+	// ErrInvalidChordData is returned when decoding data that isn't a binary
+	// encoded chord sequence.
+	ErrInvalidChordData = errors.New("invalid chord sequence data")
+	// ErrUnsupportedVersion is returned when decoding a chord sequence encoded
+	// using a newer version of the format.
+	ErrUnsupportedVersion = errors.New("unsupported chord sequence format version")
+)
+
+// WriteChordSpans writes the chord spans using a compact binary format
+// preserving the exact voicings (octaves, order and repeated keys), the
+// spelling and the timing of the chords.
+//
+// The data starts with a magic number and the format version, followed by a
+// vocabulary of the distinct voicings and the sequence of spans, each
+// referencing a voicing by its index. All the numbers are varints so the
+// vocabulary size isn't limited.
+func WriteChordSpans(w io.Writer, spans ChordSpans) error {
+	return writeChordSpans(w, spans, true)
+}
+
+// ReadChordSpans reads chord spans written by WriteChordSpans (or by
+// Chords.MarshalBinary, in which case the chords have no timing).
+func ReadChordSpans(r io.Reader) (ChordSpans, error) {
+	br, ok := r.(io.ByteReader)
+	if !ok {
+		br = bufio.NewReader(r)
+	}
+	header := make([]byte, len(chordsMagic)+2)
+	for i := range header {
+		b, err := br.ReadByte()
+		if err != nil {
+			return nil, ErrInvalidChordData
+		}
+		header[i] = b
+	}
+	if !bytes.Equal(header[:len(chordsMagic)], chordsMagic) {
+		return nil, ErrInvalidChordData
+	}
+	if header[len(chordsMagic)] > ChordsFormatVersion {
+		return nil, ErrUnsupportedVersion
+	}
+	flags := header[len(chordsMagic)+1]
+	d := &chordsDecoder{r: br}
+
+	// the counts come from the data, the slices grow as the items are read so
+	// corrupted counts fail on the end of the data instead of allocating
+	vocabSize := d.uvarint()
+	vocab := []*Chord{}
+	for i := uint64(0); i < vocabSize && d.err == nil; i++ {
+		c := &Chord{Spelling: Spelling(d.uvarint()), Keys: []int{}}
+		keyCount := d.uvarint()
+		var prev int
+		for j := uint64(0); j < keyCount && d.err == nil; j++ {
+			prev += d.varint()
+			c.Keys = append(c.Keys, prev)
+		}
+		vocab = append(vocab, c)
+	}
+	if d.err != nil {
+		return nil, d.err
+	}
+
+	spanCount := d.uvarint()
+	spans := ChordSpans{}
+	var end int
+	for i := uint64(0); i < spanCount && d.err == nil; i++ {
+		var span ChordSpan
+		code := d.uvarint()
+		if code > uint64(len(vocab)) {
+			return nil, ErrInvalidChordData
+		}
+		if code > 0 {
+			span.Chord = vocab[code-1].Copy()
+		}
+		if flags&flagTimed != 0 {
+			span.Start = end + d.varint()
+			span.Duration = int(d.uvarint())
+			end = span.End()
+		}
+		spans = append(spans, span)
+	}
+	if d.err != nil {
+		return nil, d.err
+	}
+	return spans, nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler using the format of
+// WriteChordSpans.
+func (spans ChordSpans) MarshalBinary() ([]byte, error) {
+	buf := bytes.NewBuffer(nil)
+	err := WriteChordSpans(buf, spans)
+	return buf.Bytes(), err
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (spans *ChordSpans) UnmarshalBinary(data []byte) error {
+	s, err := ReadChordSpans(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	*spans = s
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler using the format of
+// WriteChordSpans, without timing. Unlike ToBytes, the encoding is lossless.
+func (chords Chords) MarshalBinary() ([]byte, error) {
+	spans := make(ChordSpans, len(chords))
+	for i, c := range chords {
+		spans[i].Chord = c
+	}
+	buf := bytes.NewBuffer(nil)
+	err := writeChordSpans(buf, spans, false)
+	return buf.Bytes(), err
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (chords *Chords) UnmarshalBinary(data []byte) error {
+	spans, err := ReadChordSpans(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	*chords = spans.Chords()
+	return nil
+}
+
+func writeChordSpans(w io.Writer, spans ChordSpans, timed bool) error {
+	e := &chordsEncoder{}
+	var flags byte
+	if timed {
+		flags |= flagTimed
+	}
+	e.buf = append(e.buf, chordsMagic...)
+	e.buf = append(e.buf, ChordsFormatVersion, flags)
+
+	// codes start at 1, 0 being used for nil chords
+	codes := map[string]uint64{}
+	vocab := []*Chord{}
+	for _, span := range spans {
+		if span.Chord == nil {
+			continue
+		}
+		id := voicingID(span.Chord)
+		if _, ok := codes[id]; !ok {
+			vocab = append(vocab, span.Chord)
+			codes[id] = uint64(len(vocab))
+		}
+	}
+	e.uvarint(uint64(len(vocab)))
+	for _, c := range vocab {
+		e.uvarint(uint64(c.Spelling))
+		e.uvarint(uint64(len(c.Keys)))
+		var prev int
+		for _, k := range c.Keys {
+			e.varint(k - prev)
+			prev = k
+		}
+	}
+
+	e.uvarint(uint64(len(spans)))
+	var end int
+	for _, span := range spans {
+		var code uint64
+		if span.Chord != nil {
+			code = codes[voicingID(span.Chord)]
+		}
+		e.uvarint(code)
+		if timed {
+			e.varint(span.Start - end)
+			e.uvarint(uint64(span.Duration))
+			end = span.End()
+		}
+	}
+	_, err := w.Write(e.buf)
+	return err
+}
+
+// voicingID identifies the voicing and spelling of a chord.
+func voicingID(c *Chord) string {
+	var b strings.Builder
+	b.WriteString(strconv.Itoa(int(c.Spelling)))
+	for _, k := range c.Keys {
+		b.WriteString("," + strconv.Itoa(k))
+	}
+	return b.String()
+}
+
+type chordsEncoder struct {
+	buf []byte
+	tmp [binary.MaxVarintLen64]byte
+}
+
+func (e *chordsEncoder) uvarint(v uint64) {
+	n := binary.PutUvarint(e.tmp[:], v)
+	e.buf = append(e.buf, e.tmp[:n]...)
+}
+
+func (e *chordsEncoder) varint(v int) {
+	n := binary.PutVarint(e.tmp[:], int64(v))
+	e.buf = append(e.buf, e.tmp[:n]...)
+}
+
+// chordsDecoder reads varints, keeping the first error.
+type chordsDecoder struct {
+	r   io.ByteReader
+	err error
+}
+
+func (d *chordsDecoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	v, err := binary.ReadUvarint(d.r)
+	if err != nil {
+		d.err = ErrInvalidChordData
+	}
+	return v
+}
+
+func (d *chordsDecoder) varint() int {
+	if d.err != nil {
+		return 0
+	}
+	v, err := binary.ReadVarint(d.r)
+	if err != nil {
+		d.err = ErrInvalidChordData
+	}
+	return int(v)
+}
@@ -0,0 +1,118 @@
+package theory
+
+import (
+	"bytes"
+	"encoding/binary"
+	"reflect"
+	"testing"
+
+	"github.com/go-audio/midi"
+)
+
+func TestChordSpans_MarshalBinary(t *testing.T) {
+	bbMaj7 := NewChordFromAbbrev("A#Maj7")
+	bbMaj7.Spelling = FlatSpelling
+	tests := []struct {
+		name  string
+		spans ChordSpans
+	}{
+		{"empty", ChordSpans{}},
+		{"voicings and octaves", ChordSpans{
+			{Chord: &Chord{Keys: []int{midi.KeyInt("A", 2), midi.KeyInt("F#", 3), midi.KeyInt("A", 3), midi.KeyInt("C#", 4)}}, Start: 0, Duration: 384},
+			{Chord: NewChordFromAbbrev("Dmaj").Transpose(36), Start: 384, Duration: 192},
+			// same chord an octave lower
+			{Chord: NewChordFromAbbrev("Dmaj").Transpose(24), Start: 576, Duration: 192},
+			{Chord: bbMaj7, Start: 768, Duration: 96},
+		}},
+		{"repeats, rests and overlaps", ChordSpans{
+			{Chord: NewChordFromAbbrev("Cmaj"), Start: 96, Duration: 96},
+			{Chord: nil, Start: 192, Duration: 96},
+			{Chord: NewChordFromAbbrev("Cmaj"), Start: 288, Duration: 192},
+			{Chord: &Chord{Keys: []int{60, 60, 64, 67}}, Start: 384, Duration: 96},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := tt.spans.MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got ChordSpans
+			if err := got.UnmarshalBinary(data); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.spans) {
+				t.Errorf("expected %+v, got %+v", tt.spans, got)
+			}
+		})
+	}
+}
+
+func TestChords_MarshalBinary(t *testing.T) {
+	chords := Chords{}
+	// more distinct voicings than a byte dictionary can hold
+	for i := 0; i < 300; i++ {
+		chords = append(chords, NewChordFromAbbrev("Cmin7").Transpose(i%100), NewChordFromAbbrev("Emaj").Transpose(i))
+	}
+	data, err := chords.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got Chords
+	if err := got.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, chords) {
+		t.Errorf("chords didn't round trip, got %s", got)
+	}
+
+	// writing through an io.Writer gives the same data with timing
+	buf := &bytes.Buffer{}
+	if err := WriteChordSpans(buf, ChordSpans{{Chord: chords[0]}}); err != nil {
+		t.Fatal(err)
+	}
+	spans, err := ReadChordSpans(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(spans) != 1 || !reflect.DeepEqual(spans[0].Chord, chords[0]) {
+		t.Errorf("unexpected spans %+v", spans)
+	}
+}
+
+func TestReadChordSpans_errors(t *testing.T) {
+	data, err := ChordSpans{{Chord: NewChordFromAbbrev("Amin"), Duration: 96}}.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	newer := append([]byte{}, data...)
+	newer[4] = ChordsFormatVersion + 1
+	// corrupted data with counts larger than the data
+	header := []byte{'G', 'A', 'C', 'S', ChordsFormatVersion, flagTimed}
+	huge := make([]byte, binary.MaxVarintLen64)
+	huge = huge[:binary.PutUvarint(huge, 1<<62)]
+	corrupted := func(parts ...[]byte) []byte {
+		return bytes.Join(append([][]byte{header}, parts...), nil)
+	}
+	tests := []struct {
+		name string
+		data []byte
+		err  error
+	}{
+		{"empty", nil, ErrInvalidChordData},
+		{"bad magic", append([]byte("MThd"), data[4:]...), ErrInvalidChordData},
+		{"newer version", newer, ErrUnsupportedVersion},
+		{"truncated", data[:len(data)-1], ErrInvalidChordData},
+		{"huge vocabulary", corrupted(huge), ErrInvalidChordData},
+		{"huge voicing", corrupted([]byte{1, 0}, huge), ErrInvalidChordData},
+		{"huge sequence", corrupted([]byte{0}, huge), ErrInvalidChordData},
+		{"unknown voicing", corrupted([]byte{0, 1, 2, 0, 0}), ErrInvalidChordData},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ReadChordSpans(bytes.NewReader(tt.data)); err != tt.err {
+				t.Errorf("expected %v, got %v", tt.err, err)
+			}
+		})
+	}
+}
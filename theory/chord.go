@@ -5,8 +5,6 @@ import (
 	"reflect"
 	"sort"
 	"strings"
-
-	"github.com/go-audio/midi"
 )
 
 // Chord represents multiple keys pressed at the same time
@@ -14,43 +12,31 @@ type Chord struct {
 	// Keys are the MIDI note values for the voicing used in the chord.
 	Keys []int
 	// KeyIntervals are the half steps between each key, in most cases, you want to use Intervals().
-	KeyIntervals     []uint
+	KeyIntervals []uint
+	// Spelling indicates if the chord notes should be named using sharps or
+	// flats.
+	Spelling         Spelling
 	intervalKeyCache []int
 	_isSorted        bool
 }
 
 // NewChordFromAbbrev takes a chord name such as Bmin and converts it to a *Chord
-// with the root key on the 0 octave.
+// with the root key on the 0 octave. The name is parsed as a lead sheet chord
+// symbol (see ParseChordSymbol) so the abbreviations of the ChordDefs as well
+// as the usual suffixes (Bbmaj7, F#m7b5, C-7) and slash chords (C/E, the bass
+// being below the root) are recognized. nil is returned if the name can't be
+// parsed.
 func NewChordFromAbbrev(name string) *Chord {
 	name = strings.TrimSpace(name)
-	// shortest name would be a c5 or something like that
-	if len(name) < 2 {
+	if name == "" {
 		return nil
 	}
-	root := []byte{name[0]}
-	name = name[1:]
-	if name[0] == '#' {
-		root = append(root, name[0])
-		name = name[1:]
-	}
-	var chordRef *ChordDefinition
-	for _, chordDef := range ChordDefs {
-		if name == chordDef.Abbrev {
-			chordRef = chordDef
-			break
-		}
-	}
-	if chordRef == nil {
+	// the root can be written in lower case (bmin)
+	cs, err := ParseChordSymbol(strings.ToUpper(name[:1]) + name[1:])
+	if err != nil {
 		return nil
 	}
-	chordRef.Root = string(root)
-	rootInt := midi.KeyInt(chordRef.Root, 0)
-	chord := &Chord{Keys: []int{rootInt}}
-	for i, interv := range chordRef.HalfSteps {
-		chord.Keys = append(chord.Keys, chord.Keys[i]+int(interv))
-	}
-
-	return chord
+	return cs.Chord()
 }
 
 // AbbrevName is the abbreviated name of the chord.
@@ -70,7 +56,7 @@ func (c *Chord) String() string {
 	def := c.Def()
 	strs := make([]string, len(c.Keys))
 	for i, k := range c.Keys {
-		strs[i] = midi.NoteToName(k)
+		strs[i] = NoteNameWithOctave(k, c.Spelling)
 	}
 	return fmt.Sprintf("%s - %q",
 		def,
@@ -98,7 +84,7 @@ func (c *Chord) Def() *ChordDefinition {
 				if analyzedChord != c {
 					copy(c.Keys, analyzedChord.Keys)
 				}
-				return chordDef.WithRoot(midi.Notes[analyzedChord.Keys[0]%12])
+				return chordDef.WithRoot(NoteName(analyzedChord.Keys[0], c.Spelling))
 			}
 		}
 		// we didn't find the chord, let's try to change the interval orders
@@ -122,11 +108,13 @@ func (c *Chord) Def() *ChordDefinition {
 
 // TODO: PossibleDefs
 
-// SortedByKeys returns a copy of the chord but with the chord keys by pitch (lowest first)
+// SortedByKeys returns a copy of the chord but with the chord keys sorted by
+// pitch class (C first). The keys of the chord itself aren't modified.
 func (c *Chord) SortedByKeys() *Chord {
 	newChord := &Chord{_isSorted: true}
 	// sorting the keys which might lead to issues with inversions
-	sortedKeys := c.Keys
+	sortedKeys := make([]int, len(c.Keys))
+	copy(sortedKeys, c.Keys)
 	sort.Slice(sortedKeys, func(i, j int) bool { return sortedKeys[i]%12 < sortedKeys[j]%12 })
 	newChord.Keys = sortedKeys
 	return newChord
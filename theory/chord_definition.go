@@ -41,7 +41,7 @@ func (cd *ChordDefinition) RootInt() int {
 
 func (cd *ChordDefinition) String() string {
 	if len(cd.Root) > 0 {
-		return fmt.Sprintf("%s %s", strings.ToUpper(cd.Root), cd.Name)
+		return fmt.Sprintf("%s%s %s", strings.ToUpper(cd.Root[:1]), cd.Root[1:], cd.Name)
 	}
 	return cd.Name
 }
@@ -0,0 +1,210 @@
+package theory
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/go-audio/midi"
+)
+
+// symbolSuffixes are the conventional lead sheet suffixes of the chord
+// definitions whose abbreviation isn't used as is.
+var symbolSuffixes = map[string]string{
+	"maj":         "",
+	"min":         "m",
+	"mb5":         "dim",
+	"min7":        "m7",
+	"tri":         "dim7",
+	"majb5":       "(b5)",
+	"min6":        "m6",
+	"min6add9":    "m6add9",
+	"min13":       "m13",
+	"m-Maj7":      "m(maj7)",
+	"m-Maj7add11": "m(maj7)add11",
+	"m-Maj7add13": "m(maj7)add13",
+	"m9-Maj7":     "m9(maj7)",
+	"m-Maj11":     "m(maj11)",
+	"m-Maj13":     "m(maj13)",
+}
+
+// Suffix returns the suffix used to write the chord on lead sheets, such as
+// "m7" or "maj7" ("" for major triads).
+func (cd *ChordDefinition) Suffix() string {
+	if suffix, ok := symbolSuffixes[cd.Abbrev]; ok {
+		return suffix
+	}
+	return strings.Replace(cd.Abbrev, "Maj", "maj", 1)
+}
+
+// Symbol returns the lead sheet symbol of the chord definition such as
+// "Bbmaj7", an empty string is returned if the root isn't set.
+func (cd *ChordDefinition) Symbol() string {
+	if cd == nil || cd.Root == "" {
+		return ""
+	}
+	return strings.ToUpper(cd.Root[:1]) + cd.Root[1:] + cd.Suffix()
+}
+
+// Symbol returns the lead sheet symbol of the chord. Inversions are written
+// as slash chords (C/E). An empty string is returned for unknown chords.
+func (c *Chord) Symbol() string {
+	def := c.Copy().Def()
+	symbol := def.Symbol()
+	if symbol == "" || len(c.Keys) == 0 {
+		return symbol
+	}
+	lowest := 0
+	for i, k := range c.Keys {
+		if k < c.Keys[lowest] {
+			lowest = i
+		}
+	}
+	if bass := c.NoteNames()[lowest]; bass != def.Root {
+		symbol += "/" + bass
+	}
+	return symbol
+}
+
+// ErrInvalidChordSymbol is returned when a chord symbol can't be parsed.
+var ErrInvalidChordSymbol = errors.New("invalid chord symbol")
+
+// chordSymbolAliases are the alternative ways to write chord suffixes, mapped
+// to the abbreviation of the matching chord definition.
+var chordSymbolAliases = map[string]string{
+	"M": "maj", "major": "maj", "Maj": "maj",
+	"-": "min", "mi": "min", "minor": "min",
+	"dim": "mb5", "°": "mb5", "o": "mb5", "m(b5)": "mb5",
+	"+": "aug", "#5": "aug", "(#5)": "aug",
+	"maj7": "Maj7", "M7": "Maj7", "ma7": "Maj7", "Δ": "Maj7", "Δ7": "Maj7", "j7": "Maj7",
+	"-7": "m7", "mi7": "m7",
+	"dim7": "tri", "°7": "tri", "o7": "tri",
+	"ø": "m7b5", "ø7": "m7b5", "-7b5": "m7b5", "m7(b5)": "m7b5",
+	"+7": "7#5", "7+": "7#5", "aug7": "7#5",
+	"mMaj7": "m-Maj7", "mM7": "m-Maj7", "m(Maj7)": "m-Maj7", "-Maj7": "m-Maj7", "-Δ7": "m-Maj7",
+	"sus": "sus4", "7sus": "7sus4", "9sus": "9sus4",
+	"2": "add9", "add2": "add9", "(add9)": "add9",
+	"m(add9)": "madd9", "-add9": "madd9",
+	"69": "6add9", "6/9": "6add9", "m69": "m6add9", "m6/9": "m6add9", "-6": "m6",
+	"maj9": "Maj9", "M9": "Maj9", "Δ9": "Maj9", "-9": "m9", "mi9": "m9",
+	"maj11": "Maj11", "M11": "Maj11", "-11": "m11",
+	"maj13": "Maj13", "M13": "Maj13", "m13": "min13", "-13": "min13",
+	"no3": "5", "(no3)": "5",
+}
+
+// chordDefBySuffix returns the chord definition written using the passed
+// suffix (its abbreviation, lead sheet suffix or a common alias).
+func chordDefBySuffix(suffix string) *ChordDefinition {
+	if abbrev, ok := chordSymbolAliases[suffix]; ok {
+		suffix = abbrev
+	}
+	for _, def := range ChordDefs {
+		if def.Abbrev == suffix {
+			return def
+		}
+	}
+	for _, def := range ChordDefs {
+		if def.Suffix() == suffix {
+			return def
+		}
+	}
+	// alterations are sometimes written between parenthesis: C7(b9)
+	if stripped := strings.NewReplacer("(", "", ")", "").Replace(suffix); stripped != suffix {
+		return chordDefBySuffix(stripped)
+	}
+	return nil
+}
+
+// ChordSymbol is a lead sheet chord symbol such as "F#m7/A".
+type ChordSymbol struct {
+	// Root is the spelled root of the chord.
+	Root string
+	// Suffix is the chord suffix as written.
+	Suffix string
+	// Bass is the spelled bass note of slash chords, empty otherwise.
+	Bass string
+	// Def is the definition of the chord, with its root set.
+	Def *ChordDefinition
+}
+
+// ParseChordSymbol parses a lead sheet chord symbol such as "Bb", "F#m7/A",
+// "Cmaj7", "C-7", "Bø" or "D/F#". Common aliases of the chord suffixes are
+// recognized in addition to the abbreviations of the ChordDefs.
+func ParseChordSymbol(symbol string) (*ChordSymbol, error) {
+	symbol = strings.TrimSpace(symbol)
+	root, rest := splitNoteName(symbol)
+	if root == "" {
+		return nil, ErrInvalidChordSymbol
+	}
+	cs := &ChordSymbol{Root: root, Suffix: rest}
+	// the slash of 6/9 chords isn't a bass note
+	if i := strings.LastIndex(rest, "/"); i >= 0 {
+		if bass, after := splitNoteName(rest[i+1:]); bass != "" && after == "" {
+			cs.Suffix, cs.Bass = rest[:i], bass
+		}
+	}
+	def := chordDefBySuffix(cs.Suffix)
+	if def == nil {
+		return nil, ErrInvalidChordSymbol
+	}
+	cs.Def = def.WithRoot(cs.Root)
+	return cs, nil
+}
+
+// splitNoteName splits a string starting with a note name (upper case letter
+// followed by accidentals) from the rest of the string. An empty note name is
+// returned if the string doesn't start with a note name.
+func splitNoteName(s string) (name, rest string) {
+	if len(s) == 0 || !strings.ContainsAny(s[:1], "ABCDEFG") {
+		return "", s
+	}
+	i := 1
+	for i < len(s) {
+		switch {
+		case s[i] == '#' || s[i] == 'b':
+			i++
+		case strings.HasPrefix(s[i:], "♯"):
+			s = s[:i] + "#" + s[i+len("♯"):]
+			i++
+		case strings.HasPrefix(s[i:], "♭"):
+			s = s[:i] + "b" + s[i+len("♭"):]
+			i++
+		default:
+			return s[:i], s[i:]
+		}
+	}
+	return s, ""
+}
+
+// Chord returns the chord of the symbol with its root on the 0 octave (like
+// NewChordFromAbbrev) and the bass note, if any, below it.
+func (cs *ChordSymbol) Chord() *Chord {
+	_, rootPC, _ := parseNoteName(cs.Root)
+	rootKey := midi.KeyInt("C", 0) + rootPC
+	chord := &Chord{Spelling: SpellingOf(cs.Root)}
+	if _, bassPC, ok := parseNoteName(cs.Bass); ok && bassPC != rootPC {
+		chord.Keys = append(chord.Keys, rootKey+mod12(bassPC-rootPC)-12)
+	}
+	chord.Keys = append(chord.Keys, rootKey)
+	for _, hs := range cs.Def.HalfSteps {
+		chord.Keys = append(chord.Keys, chord.Keys[len(chord.Keys)-1]+int(hs))
+	}
+	return chord
+}
+
+// TransposeInterval returns the chord symbol transposed by the interval,
+// keeping its suffix as written.
+func (cs *ChordSymbol) TransposeInterval(iv Interval) *ChordSymbol {
+	t := &ChordSymbol{Root: TransposeNoteName(cs.Root, iv), Suffix: cs.Suffix}
+	if cs.Bass != "" {
+		t.Bass = TransposeNoteName(cs.Bass, iv)
+	}
+	t.Def = cs.Def.WithRoot(t.Root)
+	return t
+}
+
+func (cs *ChordSymbol) String() string {
+	if cs.Bass != "" {
+		return cs.Root + cs.Suffix + "/" + cs.Bass
+	}
+	return cs.Root + cs.Suffix
+}
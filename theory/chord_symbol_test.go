@@ -0,0 +1,121 @@
+package theory
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-audio/midi"
+)
+
+func TestChord_Symbol(t *testing.T) {
+	tests := []struct {
+		name  string
+		chord *Chord
+		want  string
+	}{
+		{"major", NewChordFromAbbrev("Cmaj"), "C"},
+		{"minor with flats", NewChordFromAbbrev("Bbmin"), "Bbm"},
+		{"major seventh", NewChordFromAbbrev("EbMaj7"), "Ebmaj7"},
+		{"diminished", NewChordFromAbbrev("Bmb5"), "Bdim"},
+		{"minor major seventh", NewChordFromAbbrev("Am-Maj7"), "Am(maj7)"},
+		{"first inversion",
+			&Chord{Keys: []int{midi.KeyInt("E", 3), midi.KeyInt("G", 3), midi.KeyInt("C", 4)}}, "C/E"},
+		{"second inversion with flats",
+			&Chord{Keys: []int{midi.KeyInt("F", 3), midi.KeyInt("A#", 3), midi.KeyInt("D", 4)}, Spelling: FlatSpelling}, "Bb/F"},
+		{"unknown", &Chord{Keys: []int{0, 1}}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.chord.Symbol(); got != tt.want {
+				t.Errorf("Chord.Symbol() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseChordSymbol(t *testing.T) {
+	tests := []struct {
+		symbol string
+		want   string
+		keys   []int
+	}{
+		{"C", "C Major", []int{24, 28, 31}},
+		{"Bb", "Bb Major", []int{34, 38, 41}},
+		{"F#m7/A", "F# Minor Seventh", []int{21, 30, 33, 37, 40}},
+		{"Cmaj7", "C Major Seventh", []int{24, 28, 31, 35}},
+		{"CM7", "C Major Seventh", []int{24, 28, 31, 35}},
+		{"C-7", "C Minor Seventh", []int{24, 27, 31, 34}},
+		{"Bø", "B Minor Seventh Flat 5th", []int{35, 38, 41, 45}},
+		{"Ebdim", "Eb Diminished", []int{27, 30, 33}},
+		{"G+", "G Augmented", []int{31, 35, 39}},
+		{"D/F#", "D Major", []int{18, 26, 30, 33}},
+		{"C6/9", "C Sixth add 9th", []int{24, 28, 31, 33, 38}},
+		{"A7(b9)", "A Seventh Flat 9th", []int{33, 37, 40, 43, 46}},
+		{"E♭m", "Eb Minor", []int{27, 30, 34}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.symbol, func(t *testing.T) {
+			cs, err := ParseChordSymbol(tt.symbol)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := cs.Def.String(); got != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+			if got := cs.Chord().Keys; !reflect.DeepEqual(got, tt.keys) {
+				t.Errorf("expected keys %v, got %v", tt.keys, got)
+			}
+		})
+	}
+
+	for _, symbol := range []string{"", "H7", "Cxyz", "chorus", "N.C."} {
+		if _, err := ParseChordSymbol(symbol); err != ErrInvalidChordSymbol {
+			t.Errorf("expected %q to be invalid, got %v", symbol, err)
+		}
+	}
+}
+
+func TestChordSymbol_TransposeInterval(t *testing.T) {
+	tests := []struct {
+		symbol string
+		iv     Interval
+		want   string
+	}{
+		{"G", MajorSecond.Down(), "F"},
+		{"F#m7/A", MinorThird, "Am7/C"},
+		{"Bbmaj7", MajorSecond, "Cmaj7"},
+		{"D/F#", PerfectFourth, "G/B"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.symbol, func(t *testing.T) {
+			cs, err := ParseChordSymbol(tt.symbol)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := cs.TransposeInterval(tt.iv).String(); got != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestNoteNameInterval(t *testing.T) {
+	tests := []struct {
+		from, to string
+		want     Interval
+	}{
+		{"Bb", "D", MajorThird},
+		{"A#", "D", Interval{Steps: 3, HalfSteps: 4}},
+		{"G", "F", MinorSeventh},
+		{"C", "B#", Interval{Steps: 6, HalfSteps: 12}},
+		{"E", "E", PerfectUnison},
+	}
+	for _, tt := range tests {
+		t.Run(tt.from+" "+tt.to, func(t *testing.T) {
+			got, ok := NoteNameInterval(tt.from, tt.to)
+			if !ok || got != tt.want {
+				t.Errorf("NoteNameInterval(%s, %s) = %v, want %v", tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+}
@@ -371,6 +371,46 @@ func TestNewChordFromAbbrev(t *testing.T) {
 				},
 			},
 		},
+		{name: "Bbmaj7",
+			want: &Chord{
+				Keys: []int{
+					midi.KeyInt("A#", 0),
+					midi.KeyInt("D", 1),
+					midi.KeyInt("F", 1),
+					midi.KeyInt("A", 1),
+				},
+				Spelling: FlatSpelling,
+			},
+		},
+		{name: "C-7",
+			want: &Chord{
+				Keys: []int{
+					midi.KeyInt("C", 0),
+					midi.KeyInt("D#", 0),
+					midi.KeyInt("G", 0),
+					midi.KeyInt("A#", 0),
+				},
+			},
+		},
+		{name: "C/E",
+			want: &Chord{
+				Keys: []int{
+					midi.KeyInt("E", -1),
+					midi.KeyInt("C", 0),
+					midi.KeyInt("E", 0),
+					midi.KeyInt("G", 0),
+				},
+			},
+		},
+		{name: "bmin",
+			want: &Chord{
+				Keys: []int{
+					midi.KeyInt("B", 0),
+					midi.KeyInt("D", 1),
+					midi.KeyInt("F#", 1),
+				},
+			},
+		},
 		{name: "Matt", want: nil},
 		{name: "CMajor", want: nil},
 	}
@@ -382,3 +422,48 @@ func TestNewChordFromAbbrev(t *testing.T) {
 		})
 	}
 }
+
+func TestChord_Def_spareCapacity(t *testing.T) {
+	// keys built with append have a larger capacity than their length, the
+	// analysis must not write past the chord keys.
+	keys := make([]int, 0, 4)
+	keys = append(keys, midi.KeyInt("C", 3), midi.KeyInt("F", 3), midi.KeyInt("A", 3))
+	c := &Chord{Keys: keys}
+	if got := c.Def().String(); got != "F Major" {
+		t.Errorf("Expected F Major, got %s", got)
+	}
+}
+
+func TestChord_SortedByKeys(t *testing.T) {
+	// SortedByKeys used to sort the keys of the receiver in place (the
+	// returned chord aliased them), Def then left unknown chords with sorted
+	// and rotated keys.
+	keys := []int{midi.KeyInt("G", 3), midi.KeyInt("C", 3), midi.KeyInt("E", 3)}
+	c := &Chord{Keys: append([]int(nil), keys...)}
+	sorted := c.SortedByKeys()
+	if want := []int{midi.KeyInt("C", 3), midi.KeyInt("E", 3), midi.KeyInt("G", 3)}; !reflect.DeepEqual(sorted.Keys, want) {
+		t.Errorf("expected %v, got %v", want, sorted.Keys)
+	}
+	if !reflect.DeepEqual(c.Keys, keys) {
+		t.Errorf("expected the chord keys to be kept as %v, got %v", keys, c.Keys)
+	}
+
+	unknown := []int{midi.KeyInt("F#", 3), midi.KeyInt("C", 3), midi.KeyInt("D", 3)}
+	c = &Chord{Keys: append([]int(nil), unknown...)}
+	if got := c.Def().Name; got != "Unknown" {
+		t.Fatalf("expected an unknown chord, got %s", got)
+	}
+	if !reflect.DeepEqual(c.Keys, unknown) {
+		t.Errorf("expected the keys of an unknown chord to be kept as %v, got %v", unknown, c.Keys)
+	}
+}
+
+func TestNewChordFromAbbrev_keepsDefinitions(t *testing.T) {
+	NewChordFromAbbrev("Gmaj")
+	NewChordFromAbbrev("Ebm7")
+	for _, def := range ChordDefs {
+		if def.Root != "" {
+			t.Errorf("expected the %s definition not to have a root, got %s", def.Name, def.Root)
+		}
+	}
+}
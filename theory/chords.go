@@ -13,7 +13,8 @@ type Chords []*Chord
 
 // ToBytes compresses a slice of chords where each chord is represented by a
 // byte. A dictionary is also returned so a byte can be converted back to a
-// chord (but the octave and repeated notes within the chord will be lost)
+// chord (but the octave and repeated notes within the chord will be lost).
+// See MarshalBinary for a lossless encoding.
 func (chords Chords) ToBytes() (data []byte, dict map[byte]string) {
 	// build the dictionaries
 	uNames := strings.Split(chords.Uniques().String(), ",")
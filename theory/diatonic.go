@@ -0,0 +1,106 @@
+package theory
+
+// StepIndex returns the absolute position of the note on the scale, counting
+// scale steps from the tonic found in MIDI octave 0 (so C3 on a C Major scale
+// is at position 35). Notes outside of the scale are given the position of the
+// closest scale note below them and inScale is set to false.
+func (s *Scale) StepIndex(note int) (pos int, inScale bool) {
+	pos, chromatic := s.stepIndex(note)
+	return pos, chromatic == 0
+}
+
+// StepNote returns the note found the passed number of scale steps away from
+// the passed note (negative steps go down), moving across octaves as needed.
+// For instance, on a C Major scale, moving E3 two steps up gives G3 and moving
+// C3 one step down gives B2. Notes outside of the scale keep their chromatic
+// offset from the scale note below them (C#3 one step up gives D#3).
+func (s *Scale) StepNote(note, steps int) int {
+	if s == nil || len(s.Def.HalfSteps) == 0 {
+		return note
+	}
+	pos, chromatic := s.stepIndex(note)
+	return s.noteAtStep(pos+steps) + chromatic
+}
+
+// DiatonicInterval returns the interval between 2 notes counted using the
+// scale steps (the half steps are the actual distance between the notes). On
+// a heptatonic scale, the result matches the traditional interval naming, for
+// instance E to G on a C Major scale is a minor third.
+func (s *Scale) DiatonicInterval(from, to int) Interval {
+	fromPos, _ := s.stepIndex(from)
+	toPos, _ := s.stepIndex(to)
+	return Interval{Steps: toPos - fromPos, HalfSteps: to - from}
+}
+
+// TransposeDiatonic transposes the passed notes by a number of scale steps,
+// keeping the notes in the scale. For instance, transposing a melody 2 steps
+// up creates a harmony a third above in key.
+func (s *Scale) TransposeDiatonic(notes []int, steps int) []int {
+	out := make([]int, len(notes))
+	for i, n := range notes {
+		out[i] = s.StepNote(n, steps)
+	}
+	return out
+}
+
+// TransposeDiatonic returns a copy of the chord with each key moved by the
+// passed number of scale steps (a C Major triad moved one step up in C Major
+// becomes D minor).
+func (c *Chord) TransposeDiatonic(s *Scale, steps int) *Chord {
+	if c == nil {
+		return nil
+	}
+	return &Chord{Keys: s.TransposeDiatonic(c.Keys, steps), Spelling: c.Spelling}
+}
+
+// stepIndex returns the absolute step position of the note and its chromatic
+// offset from the scale note at that position.
+func (s *Scale) stepIndex(note int) (pos, chromatic int) {
+	if s == nil || len(s.Def.HalfSteps) == 0 {
+		return note, 0
+	}
+	offsets := s.Def.pitchClasses()
+	rel := note - mod12(s.Root)
+	octave := floorDiv(rel, 12)
+	within := rel - octave*12
+	degree := 0
+	for i, o := range offsets {
+		if o <= within {
+			degree = i
+		}
+	}
+	return octave*len(offsets) + degree, within - offsets[degree]
+}
+
+// noteAtStep is the reverse of stepIndex.
+func (s *Scale) noteAtStep(pos int) int {
+	offsets := s.Def.pitchClasses()
+	octave := floorDiv(pos, len(offsets))
+	degree := pos - octave*len(offsets)
+	return mod12(s.Root) + octave*12 + offsets[degree]
+}
+
+// floorDiv divides rounding towards negative infinity.
+func floorDiv(a, b int) int {
+	q := a / b
+	if (a%b != 0) && ((a < 0) != (b < 0)) {
+		q--
+	}
+	return q
+}
+
+// Run returns the notes of the scale starting from the passed note and moving
+// the passed number of steps (a negative value creates a descending run). The
+// start note is included, so a one octave run on a heptatonic scale is
+// s.Run(root, 7).
+func (s *Scale) Run(start, steps int) []int {
+	dir := 1
+	if steps < 0 {
+		dir, steps = -1, -steps
+	}
+	notes := make([]int, 0, steps+1)
+	for i := 0; i <= steps; i++ {
+		notes = append(notes, s.StepNote(start, i*dir))
+	}
+	return notes
+}
@@ -0,0 +1,90 @@
+package theory
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-audio/midi"
+)
+
+func TestScale_StepNote(t *testing.T) {
+	cMajor := &Scale{Root: 0, Def: ScaleDefMap[MajorScale]}
+	aMinorPenta := &Scale{Root: 9, Def: ScaleDefMap[MinorPentatonicScale]}
+	tests := []struct {
+		name  string
+		scale *Scale
+		note  int
+		steps int
+		want  int
+	}{
+		{"third above", cMajor, midi.KeyInt("E", 3), 2, midi.KeyInt("G", 3)},
+		{"one step down across the octave", cMajor, midi.KeyInt("C", 3), -1, midi.KeyInt("B", 2)},
+		{"octave", cMajor, midi.KeyInt("D", 3), 7, midi.KeyInt("D", 4)},
+		{"two octaves down", cMajor, midi.KeyInt("F", 3), -14, midi.KeyInt("F", 1)},
+		{"chromatic note keeps its offset", cMajor, midi.KeyInt("C#", 3), 1, midi.KeyInt("D#", 3)},
+		{"pentatonic", aMinorPenta, midi.KeyInt("A", 2), 2, midi.KeyInt("D", 3)},
+		{"pentatonic wrap", aMinorPenta, midi.KeyInt("G", 2), 1, midi.KeyInt("A", 2)},
+		{"negative notes", cMajor, -1, 1, 0},
+		{"no steps", cMajor, 64, 0, 64},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.scale.StepNote(tt.note, tt.steps); got != tt.want {
+				t.Errorf("Scale.StepNote(%s, %d) = %s, want %s", midi.NoteToName(tt.note), tt.steps,
+					midi.NoteToName(got), midi.NoteToName(tt.want))
+			}
+		})
+	}
+}
+
+func TestScale_StepIndex(t *testing.T) {
+	cMajor := &Scale{Root: 60, Def: ScaleDefMap[MajorScale]}
+	if pos, ok := cMajor.StepIndex(midi.KeyInt("C", 3)); pos != 35 || !ok {
+		t.Errorf("Scale.StepIndex(C3) = %d, %t", pos, ok)
+	}
+	if pos, ok := cMajor.StepIndex(midi.KeyInt("F#", 3)); pos != 38 || ok {
+		t.Errorf("Scale.StepIndex(F#3) = %d, %t", pos, ok)
+	}
+}
+
+func TestScale_DiatonicInterval(t *testing.T) {
+	cMajor := &Scale{Root: 0, Def: ScaleDefMap[MajorScale]}
+	tests := []struct {
+		from, to int
+		want     string
+	}{
+		{midi.KeyInt("E", 3), midi.KeyInt("G", 3), "Minor Third"},
+		{midi.KeyInt("C", 3), midi.KeyInt("E", 3), "Major Third"},
+		{midi.KeyInt("F", 3), midi.KeyInt("B", 3), "Augmented Fourth"},
+		{midi.KeyInt("B", 2), midi.KeyInt("F", 3), "Diminished Fifth"},
+		{midi.KeyInt("G", 3), midi.KeyInt("D", 3), "Perfect Fourth"},
+		{midi.KeyInt("D", 3), midi.KeyInt("C", 4), "Minor Seventh"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := cMajor.DiatonicInterval(tt.from, tt.to).Name(); got != tt.want {
+				t.Errorf("Scale.DiatonicInterval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScale_TransposeDiatonic(t *testing.T) {
+	gMajor := &Scale{Root: 7, Def: ScaleDefMap[MajorScale]}
+	melody := []int{
+		midi.KeyInt("G", 3), midi.KeyInt("A", 3), midi.KeyInt("B", 3),
+		midi.KeyInt("C", 4), midi.KeyInt("D", 4),
+	}
+	want := []int{
+		midi.KeyInt("B", 3), midi.KeyInt("C", 4), midi.KeyInt("D", 4),
+		midi.KeyInt("E", 4), midi.KeyInt("F#", 4),
+	}
+	if got := gMajor.TransposeDiatonic(melody, 2); !reflect.DeepEqual(got, want) {
+		t.Errorf("Scale.TransposeDiatonic() = %v, want %v", keyNames(got), keyNames(want))
+	}
+
+	chord := NewChordFromAbbrev("Gmaj").TransposeDiatonic(gMajor, 1)
+	if got := chord.Def().String(); got != "A Minor" {
+		t.Errorf("Chord.TransposeDiatonic() = %s, want A Minor", got)
+	}
+}
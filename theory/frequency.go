@@ -0,0 +1,112 @@
+package theory
+
+import (
+	"math"
+
+	"github.com/go-audio/midi"
+)
+
+// ConcertPitch is the frequency, in Hz, of the A above middle C (MIDI note 69,
+// named A3 by the midi package and A4 in scientific pitch notation) used as
+// the reference to tune all the other notes in equal temperament.
+type ConcertPitch float64
+
+// Common reference pitches
+const (
+	// A440 is the standard concert pitch (ISO 16).
+	A440 ConcertPitch = 440
+	// A432 is the alternative tuning sometimes called Verdi tuning.
+	A432 ConcertPitch = 432
+	// A415 is the pitch commonly used for baroque music, about a half step
+	// below A440.
+	A415 ConcertPitch = 415
+)
+
+// referenceKey is the MIDI note tuned to the concert pitch.
+const referenceKey = 69
+
+// CentsPerOctave is the number of cents in an octave, there are 100 cents in
+// an equal tempered half step.
+const CentsPerOctave = 1200
+
+// Freq returns the frequency in Hz of the passed MIDI note.
+func (p ConcertPitch) Freq(note int) float64 {
+	return p.PitchFreq(float64(note))
+}
+
+// FreqCents returns the frequency in Hz of the passed MIDI note shifted by
+// the passed number of cents (for instance -13.7 for a just major third).
+func (p ConcertPitch) FreqCents(note int, cents float64) float64 {
+	return p.PitchFreq(float64(note) + cents/100)
+}
+
+// PitchFreq returns the frequency in Hz of a fractional MIDI note number (60.5
+// is a quarter tone above middle C).
+func (p ConcertPitch) PitchFreq(pitch float64) float64 {
+	return float64(p) * math.Pow(2, (pitch-referenceKey)/12)
+}
+
+// Pitch returns the fractional MIDI note number of the passed frequency, see
+// Note to get the closest note and its deviation.
+func (p ConcertPitch) Pitch(freq float64) float64 {
+	return referenceKey + 12*math.Log2(freq/float64(p))
+}
+
+// Note returns the MIDI note closest to the passed frequency and the
+// deviation of the frequency from that note in cents (-50 to 50).
+func (p ConcertPitch) Note(freq float64) (note int, cents float64) {
+	pitch := p.Pitch(freq)
+	note = int(math.Floor(pitch + 0.5))
+	return note, (pitch - float64(note)) * 100
+}
+
+// NoteFreq returns the frequency in Hz of a note name and octave, using the
+// octave numbering of midi.KeyInt.
+func (p ConcertPitch) NoteFreq(name string, octave int) float64 {
+	return p.Freq(midi.KeyInt(name, octave))
+}
+
+// Cents returns the distance in cents between two frequencies, positive when
+// to is higher than from.
+func Cents(from, to float64) float64 {
+	return CentsPerOctave * math.Log2(to/from)
+}
+
+// ShiftCents returns the frequency shifted by the passed number of cents.
+func ShiftCents(freq, cents float64) float64 {
+	return freq * math.Pow(2, cents/CentsPerOctave)
+}
+
+// RatioCents converts a frequency ratio (3/2 for a just fifth) to cents.
+func RatioCents(ratio float64) float64 {
+	return CentsPerOctave * math.Log2(ratio)
+}
+
+// Frequencies returns the frequencies in Hz of the chord keys, in the same
+// order as the keys.
+func (c *Chord) Frequencies(p ConcertPitch) []float64 {
+	if c == nil {
+		return nil
+	}
+	freqs := make([]float64, len(c.Keys))
+	for i, k := range c.Keys {
+		freqs[i] = p.Freq(k)
+	}
+	return freqs
+}
+
+// Frequencies returns the frequencies in Hz of the scale notes played upwards
+// from the tonic in the passed octave (using the octave numbering of
+// midi.KeyInt), the octave of the tonic isn't repeated.
+func (s *Scale) Frequencies(p ConcertPitch, octave int) []float64 {
+	if s == nil {
+		return nil
+	}
+	key := mod12(s.Root) + (octave+2)*12
+	freqs := []float64{p.Freq(key)}
+	for _, hs := range s.Def.HalfSteps {
+		key += hs
+		freqs = append(freqs, p.Freq(key))
+	}
+	return freqs
+}
@@ -0,0 +1,93 @@
+package theory
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-audio/midi"
+)
+
+func floatsClose(a, b []float64, tolerance float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if math.Abs(a[i]-b[i]) > tolerance {
+			return false
+		}
+	}
+	return true
+}
+
+func TestConcertPitch_Freq(t *testing.T) {
+	tests := []struct {
+		name  string
+		pitch ConcertPitch
+		note  int
+		want  float64
+	}{
+		{"A440 A", A440, 69, 440},
+		{"A440 middle C", A440, midi.KeyInt("C", 3), 261.6256},
+		{"A440 low E", A440, midi.KeyInt("E", 0), 41.20344},
+		{"A432 A", A432, 69, 432},
+		{"A432 middle C", A432, midi.KeyInt("C", 3), 256.8687},
+		{"A415 A", A415, 69, 415},
+		{"A415 upper A", A415, 81, 830},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.pitch.Freq(tt.note); math.Abs(got-tt.want) > 1e-4 {
+				t.Errorf("expected %f, got %f", tt.want, got)
+			}
+			note, cents := tt.pitch.Note(tt.want)
+			if note != tt.note || math.Abs(cents) > 1e-3 {
+				t.Errorf("Note(%f) = %d %+f cents, want %d", tt.want, note, cents, tt.note)
+			}
+		})
+	}
+}
+
+func TestConcertPitch_Note(t *testing.T) {
+	tests := []struct {
+		name  string
+		freq  float64
+		note  int
+		cents float64
+	}{
+		{"A432 on an A440 instrument", 432, 69, -31.7667},
+		{"baroque A", 415, 68, -1.2706},
+		{"quarter tone", A440.FreqCents(60, 49), 60, 49},
+		{"just major third above A", 440 * 5 / 4, 73, -13.6863},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			note, cents := A440.Note(tt.freq)
+			if note != tt.note || math.Abs(cents-tt.cents) > 1e-3 {
+				t.Errorf("expected %d %+f cents, got %d %+f", tt.note, tt.cents, note, cents)
+			}
+		})
+	}
+	if got := A440.NoteFreq("A", 3); got != 440 {
+		t.Errorf("NoteFreq(A, 3) = %f, want 440", got)
+	}
+	if got := Cents(440, 880); math.Abs(got-1200) > 1e-9 {
+		t.Errorf("Cents(440, 880) = %f, want 1200", got)
+	}
+	if got := ShiftCents(440, -1200); math.Abs(got-220) > 1e-9 {
+		t.Errorf("ShiftCents(440, -1200) = %f, want 220", got)
+	}
+	if got := RatioCents(3.0 / 2); math.Abs(got-701.955) > 1e-3 {
+		t.Errorf("RatioCents(3/2) = %f, want 701.955", got)
+	}
+}
+
+func TestFrequencies(t *testing.T) {
+	c := &Chord{Keys: []int{midi.KeyInt("A", 2), midi.KeyInt("C#", 3), midi.KeyInt("E", 3)}}
+	if got, want := c.Frequencies(A440), []float64{220, 277.1826, 329.6276}; !floatsClose(got, want, 1e-4) {
+		t.Errorf("Chord.Frequencies() = %v, want %v", got, want)
+	}
+	s := &Scale{Root: midi.KeyInt("A", 0), Def: ScaleDefMap[MinorPentatonicScale]}
+	if got, want := s.Frequencies(A432, 3), []float64{432, 513.7375, 576.6509, 647.2687, 769.7365}; !floatsClose(got, want, 1e-4) {
+		t.Errorf("Scale.Frequencies() = %v, want %v", got, want)
+	}
+}
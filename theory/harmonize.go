@@ -0,0 +1,236 @@
+package theory
+
+import (
+	"sort"
+)
+
+// HarmonizeOptions configure the harmonization of a melody. Zero values use
+// the defaults.
+type HarmonizeOptions struct {
+	// TicksPerQuarter is the resolution of the melody, 96 by default.
+	TicksPerQuarter int
+	// BeatsPerBar is the number of quarter notes per bar, 4 by default.
+	BeatsPerBar int
+	// ChordTicks is the duration of each chord (the harmonic rhythm), one bar
+	// by default.
+	ChordTicks int
+	// Sevenths harmonizes the melody using seventh chords instead of triads.
+	Sevenths bool
+	// Count is the number of harmonizations returned, 3 by default.
+	Count int
+}
+
+// Harmonization is a chord progression harmonizing a melody.
+type Harmonization struct {
+	Spans ChordSpans
+	// Score rates how well the chords fit the melody, higher is better.
+	Score float64
+}
+
+// harmonization scoring
+const (
+	// nonChordTonePenalty is the ratio of the note weight removed for melody
+	// notes that aren't chord tones.
+	nonChordTonePenalty = 0.5
+	fifthMotionBonus    = 0.5
+	repeatPenalty       = 0.25
+	tonicStartBonus     = 0.5
+	tonicEndBonus       = 1
+)
+
+// Harmonize proposes chord progressions harmonizing the melody using the
+// diatonic chords of the scale, best first. The melody is split in segments
+// of opts.ChordTicks, each getting a chord. Chords are rated by how many
+// melody notes are chord tones, notes on strong beats weighting more, and
+// progressions get a bonus for root motions by fifth and for starting and
+// ending on the tonic. The whole melody is searched so the best progressions
+// are returned, not only the best chord for each segment.
+func Harmonize(melody []NoteEvent, s *Scale, opts HarmonizeOptions) []Harmonization {
+	if s == nil || len(melody) == 0 {
+		return nil
+	}
+	if opts.TicksPerQuarter <= 0 {
+		opts.TicksPerQuarter = 96
+	}
+	if opts.BeatsPerBar <= 0 {
+		opts.BeatsPerBar = 4
+	}
+	if opts.ChordTicks <= 0 {
+		opts.ChordTicks = opts.BeatsPerBar * opts.TicksPerQuarter
+	}
+	if opts.Count <= 0 {
+		opts.Count = 3
+	}
+
+	candidates := s.diatonicChords(opts.Sevenths)
+	var end int
+	for _, n := range melody {
+		if n.End() > end {
+			end = n.End()
+		}
+	}
+	segments := (end + opts.ChordTicks - 1) / opts.ChordTicks
+	// nothing sounds after the first tick
+	if segments <= 0 {
+		return nil
+	}
+
+	// fit[i][c] is the score of the candidate c over the segment i
+	fit := make([][]float64, segments)
+	for i := range fit {
+		fit[i] = make([]float64, len(candidates))
+		start := i * opts.ChordTicks
+		for c, chord := range candidates {
+			fit[i][c] = melodyFit(melody, chord, start, start+opts.ChordTicks, opts)
+		}
+	}
+
+	// keep the best partial progressions ending on each candidate
+	type path struct {
+		score  float64
+		chords []int
+	}
+	best := make([][]path, len(candidates))
+	for c := range candidates {
+		score := fit[0][c]
+		if s.IndexOfNote(candidates[c].Keys[0]) == 0 {
+			score += tonicStartBonus
+		}
+		best[c] = []path{{score: score, chords: []int{c}}}
+	}
+	for i := 1; i < segments; i++ {
+		next := make([][]path, len(candidates))
+		for c := range candidates {
+			var paths []path
+			for prev := range candidates {
+				bonus := progressionBonus(candidates[prev], candidates[c])
+				for _, p := range best[prev] {
+					chords := append(append([]int(nil), p.chords...), c)
+					paths = append(paths, path{score: p.score + bonus + fit[i][c], chords: chords})
+				}
+			}
+			sort.SliceStable(paths, func(a, b int) bool { return paths[a].score > paths[b].score })
+			if len(paths) > opts.Count {
+				paths = paths[:opts.Count]
+			}
+			next[c] = paths
+		}
+		best = next
+	}
+
+	var all []path
+	for c, paths := range best {
+		for _, p := range paths {
+			if s.IndexOfNote(candidates[c].Keys[0]) == 0 {
+				p.score += tonicEndBonus
+			}
+			all = append(all, p)
+		}
+	}
+	sort.SliceStable(all, func(a, b int) bool { return all[a].score > all[b].score })
+	if len(all) > opts.Count {
+		all = all[:opts.Count]
+	}
+
+	out := make([]Harmonization, len(all))
+	for i, p := range all {
+		chords := make(Chords, len(p.chords))
+		for j, c := range p.chords {
+			chords[j] = candidates[c].Copy()
+		}
+		out[i] = Harmonization{Spans: NewChordSpans(chords, opts.ChordTicks), Score: p.score}
+	}
+	return out
+}
+
+// diatonicChords returns the chords built on each degree of the scale, with
+// their root between C2 and B2 (MIDI 48 to 59).
+func (s *Scale) diatonicChords(sevenths bool) []*Chord {
+	size := 3
+	if sevenths {
+		size = 4
+	}
+	var chords []*Chord
+	for _, pc := range s.Notes() {
+		root := 48 + mod12(pc)
+		var c *Chord
+		if ScaleChords[s.Def.Name] != nil {
+			if sevenths {
+				c = s.SeventhChordForRoot(root)
+			} else {
+				c = s.TriadChordForRoot(root)
+			}
+		}
+		// stack thirds for the scales without chord definitions
+		if c == nil || len(c.Keys) < size {
+			c = &Chord{Keys: []int{root}}
+			for i := 1; i < size; i++ {
+				c.Keys = append(c.Keys, s.StepNote(root, 2*i))
+			}
+		}
+		c.Spelling = s.Spelling()
+		chords = append(chords, c)
+	}
+	return chords
+}
+
+// melodyFit rates how well the chord fits the melody notes sounding between
+// two ticks.
+func melodyFit(melody []NoteEvent, c *Chord, start, end int, opts HarmonizeOptions) float64 {
+	var score float64
+	for _, n := range melody {
+		if n.End() <= start || n.Start >= end {
+			continue
+		}
+		// notes held over the chord change are heard on its downbeat
+		at := n.Start
+		if at < start {
+			at = start
+		}
+		w := beatWeight(at, opts)
+		if isChordTone(c, n.Key) {
+			score += w
+		} else {
+			score -= w * nonChordTonePenalty
+		}
+	}
+	return score
+}
+
+// beatWeight returns the metric weight of a tick: 2 for the downbeat, 1.5 for
+// the middle of bars with an even number of beats, 1 for the other beats and
+// 0.5 between beats.
+func beatWeight(tick int, opts HarmonizeOptions) float64 {
+	if tick%opts.TicksPerQuarter != 0 {
+		return 0.5
+	}
+	beat := (tick / opts.TicksPerQuarter) % opts.BeatsPerBar
+	switch {
+	case beat == 0:
+		return 2
+	case opts.BeatsPerBar%2 == 0 && beat == opts.BeatsPerBar/2:
+		return 1.5
+	}
+	return 1
+}
+
+// progressionBonus rates moving from a chord to another.
+func progressionBonus(from, to *Chord) float64 {
+	motion := mod12(to.Keys[0] - from.Keys[0])
+	switch motion {
+	case 0:
+		return -repeatPenalty
+	case 5:
+		return fifthMotionBonus
+	}
+	return 0
+}
+
+func isChordTone(c *Chord, key int) bool {
+	for _, k := range c.Keys {
+		if mod12(k) == mod12(key) {
+			return true
+		}
+	}
+	return false
+}
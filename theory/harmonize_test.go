@@ -0,0 +1,114 @@
+package theory
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-audio/midi"
+)
+
+// melody builds a melody of quarter notes (at 96 ticks per quarter) from note
+// names in the octave 3.
+func melody(notes ...string) []NoteEvent {
+	events := make([]NoteEvent, len(notes))
+	for i, n := range notes {
+		events[i] = NoteEvent{Key: midi.KeyInt(n, 3), Start: i * 96, Duration: 96, Velocity: 100}
+	}
+	return events
+}
+
+func harmonizationSymbols(h Harmonization) []string {
+	var symbols []string
+	for _, span := range h.Spans {
+		symbols = append(symbols, span.Chord.Symbol())
+	}
+	return symbols
+}
+
+func TestHarmonize(t *testing.T) {
+	tests := []struct {
+		name   string
+		melody []NoteEvent
+		scale  *Scale
+		opts   HarmonizeOptions
+		want   []string
+	}{
+		{name: "C major cadence",
+			melody: melody("C", "E", "G", "E", "F", "A", "C", "A", "G", "B", "D", "B", "C", "G", "E", "C"),
+			scale:  &Scale{Root: midi.KeyInt("C", 3) % 12, Def: ScaleDefMap[MajorScale]},
+			want:   []string{"C", "F", "G", "C"},
+		},
+		{name: "D major",
+			melody: melody("D", "F#", "A", "F#", "E", "G", "B", "G", "A", "C#", "E", "C#", "D", "A", "F#", "D"),
+			scale:  &Scale{Root: midi.KeyInt("D", 3) % 12, Def: ScaleDefMap[MajorScale]},
+			want:   []string{"D", "Em", "A", "D"},
+		},
+		{name: "A minor with two chords per bar",
+			melody: melody("A", "C", "D", "A", "E", "B", "A", "E"),
+			scale:  &Scale{Root: midi.KeyInt("A", 3) % 12, Def: ScaleDefMap[NaturalMinorScale]},
+			opts:   HarmonizeOptions{ChordTicks: 192},
+			want:   []string{"Am", "Dm", "Em", "Am"},
+		},
+		{name: "sevenths",
+			melody: melody("C", "E", "G", "B", "D", "F", "A", "C", "G", "B", "D", "F", "C", "E", "G", "B"),
+			scale:  &Scale{Root: midi.KeyInt("C", 3) % 12, Def: ScaleDefMap[MajorScale]},
+			opts:   HarmonizeOptions{Sevenths: true},
+			want:   []string{"Cmaj7", "Dm7", "G7", "Cmaj7"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Harmonize(tt.melody, tt.scale, tt.opts)
+			if len(got) == 0 {
+				t.Fatal("expected harmonizations")
+			}
+			if symbols := harmonizationSymbols(got[0]); !reflect.DeepEqual(symbols, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, symbols)
+			}
+			for i := 1; i < len(got); i++ {
+				if got[i].Score > got[i-1].Score {
+					t.Errorf("harmonization %d scored higher than %d", i, i-1)
+				}
+			}
+		})
+	}
+}
+
+func TestHarmonize_spans(t *testing.T) {
+	m := melody("C", "E", "G", "E", "F", "A", "C", "A", "G", "B", "D", "B", "C", "G", "E", "C")
+	s := &Scale{Root: midi.KeyInt("C", 3) % 12, Def: ScaleDefMap[MajorScale]}
+	got := Harmonize(m, s, HarmonizeOptions{Count: 5})
+	if len(got) != 5 {
+		t.Fatalf("expected 5 harmonizations, got %d", len(got))
+	}
+	for _, h := range got {
+		if len(h.Spans) != 4 {
+			t.Fatalf("expected 4 chords, got %d", len(h.Spans))
+		}
+		for i, span := range h.Spans {
+			if span.Start != i*384 || span.Duration != 384 {
+				t.Errorf("span %d: expected %d+384, got %d+%d", i, i*384, span.Start, span.Duration)
+			}
+		}
+	}
+}
+
+func TestHarmonize_empty(t *testing.T) {
+	s := &Scale{Root: midi.KeyInt("C", 3) % 12, Def: ScaleDefMap[MajorScale]}
+	for _, m := range [][]NoteEvent{
+		nil,
+		// zero duration note
+		{{Key: 60}},
+		// pickup note ending on the first beat
+		{{Key: 67, Start: -96, Duration: 96}},
+	} {
+		if got := Harmonize(m, s, HarmonizeOptions{}); got != nil {
+			t.Errorf("expected no harmonization for %v, got %v", m, got)
+		}
+	}
+	// pickup notes are heard on the first chord
+	m := []NoteEvent{{Key: 67, Start: -96, Duration: 192}, {Key: 64, Start: 96, Duration: 288}}
+	if got := Harmonize(m, s, HarmonizeOptions{}); len(got) == 0 || len(got[0].Spans) != 1 {
+		t.Errorf("expected a single chord, got %v", got)
+	}
+}
@@ -0,0 +1,120 @@
+package theory
+
+import "strings"
+
+// Interval is the distance between two notes. It is expressed both in
+// diatonic steps (the distance between the letter names of the notes) and in
+// half steps so that intervals such as an augmented fourth and a diminished
+// fifth can be told apart and spelling can be preserved.
+type Interval struct {
+	// Steps is the number of letter names between the notes (0 for a unison,
+	// 2 for a third, 7 for an octave).
+	Steps int
+	// HalfSteps is the number of semitones between the notes.
+	HalfSteps int
+}
+
+// Common intervals
+var (
+	PerfectUnison     = Interval{Steps: 0, HalfSteps: 0}
+	MinorSecond       = Interval{Steps: 1, HalfSteps: 1}
+	MajorSecond       = Interval{Steps: 1, HalfSteps: 2}
+	AugmentedSecond   = Interval{Steps: 1, HalfSteps: 3}
+	MinorThird        = Interval{Steps: 2, HalfSteps: 3}
+	MajorThird        = Interval{Steps: 2, HalfSteps: 4}
+	PerfectFourth     = Interval{Steps: 3, HalfSteps: 5}
+	AugmentedFourth   = Interval{Steps: 3, HalfSteps: 6}
+	DiminishedFifth   = Interval{Steps: 4, HalfSteps: 6}
+	PerfectFifth      = Interval{Steps: 4, HalfSteps: 7}
+	AugmentedFifth    = Interval{Steps: 4, HalfSteps: 8}
+	MinorSixth        = Interval{Steps: 5, HalfSteps: 8}
+	MajorSixth        = Interval{Steps: 5, HalfSteps: 9}
+	DiminishedSeventh = Interval{Steps: 6, HalfSteps: 9}
+	MinorSeventh      = Interval{Steps: 6, HalfSteps: 10}
+	MajorSeventh      = Interval{Steps: 6, HalfSteps: 11}
+	PerfectOctave     = Interval{Steps: 7, HalfSteps: 12}
+)
+
+var intervalNumberNames = []string{
+	"Unison", "Second", "Third", "Fourth", "Fifth", "Sixth", "Seventh", "Octave",
+	"Ninth", "Tenth", "Eleventh", "Twelfth", "Thirteenth",
+}
+
+// naturalHalfSteps are the half steps of the major/perfect intervals of a
+// major scale, indexed by simple interval steps.
+var naturalHalfSteps = []int{0, 2, 4, 5, 7, 9, 11}
+
+// Down returns the same interval but descending.
+func (iv Interval) Down() Interval {
+	return Interval{Steps: -iv.Steps, HalfSteps: -iv.HalfSteps}
+}
+
+// Add returns the sum of the two intervals (a major third plus a minor third
+// is a perfect fifth).
+func (iv Interval) Add(o Interval) Interval {
+	return Interval{Steps: iv.Steps + o.Steps, HalfSteps: iv.HalfSteps + o.HalfSteps}
+}
+
+// Quality returns the quality of the interval: Perfect, Major, Minor,
+// Augmented or Diminished (possibly doubled).
+func (iv Interval) Quality() string {
+	if iv.Steps < 0 {
+		return iv.Down().Quality()
+	}
+	simple := iv.Steps % 7
+	octaves := iv.Steps / 7
+	diff := iv.HalfSteps - octaves*12 - naturalHalfSteps[simple]
+	perfect := simple == 0 || simple == 3 || simple == 4
+	switch {
+	case perfect && diff == 0:
+		return "Perfect"
+	case !perfect && diff == 0:
+		return "Major"
+	case !perfect && diff == -1:
+		return "Minor"
+	case diff > 0:
+		return strings.TrimSpace(strings.Repeat("Doubly ", diff-1) + "Augmented")
+	case perfect:
+		return strings.TrimSpace(strings.Repeat("Doubly ", -diff-1) + "Diminished")
+	default:
+		return strings.TrimSpace(strings.Repeat("Doubly ", -diff-2) + "Diminished")
+	}
+}
+
+// Name returns the English name of the interval such as "Minor Third" or
+// "Augmented Fourth".
+func (iv Interval) Name() string {
+	steps := iv.Steps
+	if steps < 0 {
+		steps = -steps
+	}
+	number := "Compound"
+	if steps < len(intervalNumberNames) {
+		number = intervalNumberNames[steps]
+	}
+	return iv.Quality() + " " + number
+}
+
+func (iv Interval) String() string {
+	return iv.Name()
+}
+
+// chordToneSteps are the steps of the chord tones found the indexed number of
+// half steps above the root of a chord (a flat fifth rather than a sharp
+// fourth, a sharp ninth rather than a minor tenth...).
+var chordToneSteps = []int{0, 1, 1, 2, 2, 3, 4, 4, 4, 5, 6, 6, 7, 8, 8, 8, 9, 10, 10, 11, 12, 12, 13, 13}
+
+// ChordToneInterval returns the interval usually used to name a chord tone
+// the passed number of half steps above the root. For instance 6 half steps
+// give a diminished fifth (C7b5), 8 an augmented fifth and 15 an augmented
+// ninth (C7#9).
+func ChordToneInterval(halfSteps int) Interval {
+	if halfSteps < 0 {
+		return ChordToneInterval(-halfSteps).Down()
+	}
+	octaves := halfSteps / 24
+	return Interval{
+		Steps:     chordToneSteps[halfSteps%24] + octaves*14,
+		HalfSteps: halfSteps,
+	}
+}
@@ -0,0 +1,53 @@
+package theory
+
+import "testing"
+
+func TestInterval_Name(t *testing.T) {
+	tests := []struct {
+		iv   Interval
+		want string
+	}{
+		{PerfectUnison, "Perfect Unison"},
+		{MinorSecond, "Minor Second"},
+		{MajorThird, "Major Third"},
+		{AugmentedFourth, "Augmented Fourth"},
+		{DiminishedFifth, "Diminished Fifth"},
+		{DiminishedSeventh, "Diminished Seventh"},
+		{PerfectOctave, "Perfect Octave"},
+		{MajorThird.Add(MinorThird), "Perfect Fifth"},
+		{PerfectOctave.Add(MajorSecond), "Major Ninth"},
+		{MinorThird.Down(), "Minor Third"},
+		{Interval{Steps: 2, HalfSteps: 2}, "Diminished Third"},
+		{Interval{Steps: 3, HalfSteps: 7}, "Doubly Augmented Fourth"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := tt.iv.Name(); got != tt.want {
+				t.Errorf("Interval.Name() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChordToneInterval(t *testing.T) {
+	tests := []struct {
+		halfSteps int
+		want      string
+	}{
+		{4, "Major Third"},
+		{6, "Diminished Fifth"},
+		{8, "Augmented Fifth"},
+		{10, "Minor Seventh"},
+		{13, "Minor Ninth"},
+		{15, "Augmented Ninth"},
+		{18, "Augmented Eleventh"},
+		{21, "Major Thirteenth"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := ChordToneInterval(tt.halfSteps).Name(); got != tt.want {
+				t.Errorf("ChordToneInterval(%d) = %s, want %s", tt.halfSteps, got, tt.want)
+			}
+		})
+	}
+}
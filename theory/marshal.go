@@ -0,0 +1,272 @@
+package theory
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/go-audio/midi"
+)
+
+var (
+	// ErrUnknownScale is returned when unmarshaling an unknown scale or scale
+	// definition.
+	ErrUnknownScale = errors.New("unknown scale")
+	// ErrInvalidVoicing is returned when unmarshaling a chord with a voicing
+	// that can't be parsed.
+	ErrInvalidVoicing = errors.New("invalid chord voicing")
+	// ErrVoicingMismatch is returned when unmarshaling a chord whose voicing
+	// doesn't play the chord symbol.
+	ErrVoicingMismatch = errors.New("the voicing doesn't match the chord symbol")
+)
+
+// scaleNameAliases are alternative names of the scales.
+var scaleNameAliases = map[string]ScaleName{
+	"minor":   NaturalMinorScale,
+	"ionian":  MajorScale,
+	"aeolian": NaturalMinorScale,
+}
+
+// scaleDefByName returns the definition of the named scale, the name being
+// case insensitive.
+func scaleDefByName(name string) (ScaleDefinition, bool) {
+	name = strings.TrimSpace(name)
+	if alias, ok := scaleNameAliases[strings.ToLower(name)]; ok {
+		return ScaleDefMap[alias], true
+	}
+	for _, def := range ScaleDefs {
+		if strings.EqualFold(string(def.Name), name) {
+			return def, true
+		}
+	}
+	return ScaleDefinition{}, false
+}
+
+// MarshalText implements encoding.TextMarshaler, the definition is encoded as
+// its name (Dorian).
+func (def ScaleDefinition) MarshalText() ([]byte, error) {
+	return []byte(def.Name), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (def *ScaleDefinition) UnmarshalText(text []byte) error {
+	d, ok := scaleDefByName(string(text))
+	if !ok {
+		return ErrUnknownScale
+	}
+	*def = d
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (def ScaleDefinition) MarshalJSON() ([]byte, error) {
+	return marshalTextJSON(def)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (def *ScaleDefinition) UnmarshalJSON(data []byte) error {
+	return unmarshalTextJSON(data, def)
+}
+
+// MarshalText implements encoding.TextMarshaler, the scale is encoded as its
+// spelled tonic followed by its name (Bb Major, D Dorian).
+func (s Scale) MarshalText() ([]byte, error) {
+	return []byte(s.NoteNames()[0] + " " + string(s.Def.Name)), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *Scale) UnmarshalText(text []byte) error {
+	str := strings.TrimSpace(string(text))
+	i := strings.IndexByte(str, ' ')
+	if i < 0 {
+		return ErrUnknownScale
+	}
+	_, pc, ok := parseNoteName(str[:i])
+	if !ok {
+		return ErrUnknownScale
+	}
+	def, ok := scaleDefByName(str[i+1:])
+	if !ok {
+		return ErrUnknownScale
+	}
+	s.Root, s.Def = pc, def
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s Scale) MarshalJSON() ([]byte, error) {
+	return marshalTextJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *Scale) UnmarshalJSON(data []byte) error {
+	return unmarshalTextJSON(data, s)
+}
+
+// MarshalText implements encoding.TextMarshaler, the definition is encoded as
+// its lead sheet symbol (Bbmaj7) or as its abbreviation if the root isn't set.
+func (cd *ChordDefinition) MarshalText() ([]byte, error) {
+	if cd.Root == "" {
+		return []byte(cd.Abbrev), nil
+	}
+	return []byte(cd.Symbol()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, chord symbols and
+// abbreviations are accepted.
+func (cd *ChordDefinition) UnmarshalText(text []byte) error {
+	if cs, err := ParseChordSymbol(string(text)); err == nil {
+		*cd = *cs.Def
+		return nil
+	}
+	def := chordDefBySuffix(strings.TrimSpace(string(text)))
+	if def == nil {
+		return ErrInvalidChordSymbol
+	}
+	*cd = *def
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (cd *ChordDefinition) MarshalJSON() ([]byte, error) {
+	return marshalTextJSON(cd)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (cd *ChordDefinition) UnmarshalJSON(data []byte) error {
+	return unmarshalTextJSON(data, cd)
+}
+
+// MarshalText implements encoding.TextMarshaler, the chord is encoded as its
+// symbol followed by its voicing between parenthesis: "F#m7/A (A2 F#3 A3 C#4
+// E4)". Unknown chords only have their voicing.
+func (c *Chord) MarshalText() ([]byte, error) {
+	names := c.NoteNames()
+	voicing := make([]string, len(c.Keys))
+	for i, k := range c.Keys {
+		voicing[i] = spelledNoteWithOctave(k, names[i])
+	}
+	text := "(" + strings.Join(voicing, " ") + ")"
+	if symbol := c.Symbol(); symbol != "" {
+		text = symbol + " " + text
+	}
+	return []byte(text), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. The voicing is optional,
+// chords without voicing are built like ChordSymbol.Chord does: "F#m7/A" and
+// "F#m7/A (A2 F#3 A3 C#4 E4)" are both valid. When both are set, the voicing
+// has to be identified as the chord symbol (same root, kind and bass).
+func (c *Chord) UnmarshalText(text []byte) error {
+	str := strings.TrimSpace(string(text))
+	symbol, voicing := str, ""
+	if i := strings.IndexByte(str, '('); i >= 0 {
+		if !strings.HasSuffix(str, ")") {
+			return ErrInvalidVoicing
+		}
+		symbol, voicing = strings.TrimSpace(str[:i]), str[i+1:len(str)-1]
+	}
+	if voicing == "" {
+		cs, err := ParseChordSymbol(symbol)
+		if err != nil {
+			return err
+		}
+		*c = *cs.Chord()
+		return nil
+	}
+	var cs *ChordSymbol
+	if symbol != "" {
+		var err error
+		if cs, err = ParseChordSymbol(symbol); err != nil {
+			return err
+		}
+	}
+	chord := Chord{Keys: []int{}}
+	for _, name := range strings.Fields(voicing) {
+		key, ok := parseNoteNameWithOctave(name)
+		if !ok {
+			return ErrInvalidVoicing
+		}
+		chord.Keys = append(chord.Keys, key)
+		if SpellingOf(name) == FlatSpelling {
+			chord.Spelling = FlatSpelling
+		}
+	}
+	if cs != nil && !cs.matches(&chord) {
+		return ErrVoicingMismatch
+	}
+	*c = chord
+	return nil
+}
+
+// matches reports if the chord is identified as the chord symbol.
+func (cs *ChordSymbol) matches(c *Chord) bool {
+	played, err := ParseChordSymbol(c.Symbol())
+	if err != nil || played.Def.Abbrev != cs.Def.Abbrev {
+		return false
+	}
+	pitchClass := func(s *ChordSymbol) (root, bass int) {
+		_, root, _ = parseNoteName(s.Root)
+		bass = root
+		if s.Bass != "" {
+			_, bass, _ = parseNoteName(s.Bass)
+		}
+		return root, bass
+	}
+	root, bass := pitchClass(cs)
+	playedRoot, playedBass := pitchClass(played)
+	return root == playedRoot && bass == playedBass
+}
+
+// MarshalJSON implements json.Marshaler.
+func (c *Chord) MarshalJSON() ([]byte, error) {
+	return marshalTextJSON(c)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (c *Chord) UnmarshalJSON(data []byte) error {
+	return unmarshalTextJSON(data, c)
+}
+
+// spelledNoteWithOctave returns the name of the key using the passed spelled
+// note name and the octave numbering of midi.NoteToName (B#2 sounds like C3).
+func spelledNoteWithOctave(key int, name string) string {
+	return name + strconv.Itoa(SpelledOctave(key, name))
+}
+
+// parseNoteNameWithOctave parses a note name followed by its octave (Bb2, C-1)
+// into a MIDI key.
+func parseNoteNameWithOctave(name string) (int, bool) {
+	i := strings.IndexAny(name, "-0123456789")
+	if i < 1 {
+		return 0, false
+	}
+	letter, alter, ok := noteAlteration(name[:i])
+	if !ok {
+		return 0, false
+	}
+	octave, err := strconv.Atoi(name[i:])
+	if err != nil {
+		return 0, false
+	}
+	return midi.KeyInt("C", octave) + naturalHalfSteps[letter] + alter, true
+}
+
+// marshalTextJSON encodes a text marshaler as a JSON string.
+func marshalTextJSON(m interface{ MarshalText() ([]byte, error) }) ([]byte, error) {
+	text, err := m.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(text))
+}
+
+// unmarshalTextJSON decodes a JSON string using a text unmarshaler.
+func unmarshalTextJSON(data []byte, u interface{ UnmarshalText([]byte) error }) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+	return u.UnmarshalText([]byte(text))
+}
@@ -0,0 +1,167 @@
+package theory
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/go-audio/midi"
+)
+
+func TestScale_MarshalText(t *testing.T) {
+	tests := []struct {
+		scale Scale
+		want  string
+	}{
+		{Scale{Root: 2, Def: ScaleDefMap[DorianScale]}, "D Dorian"},
+		{Scale{Root: 10, Def: ScaleDefMap[MajorScale]}, "Bb Major"},
+		{Scale{Root: 6, Def: ScaleDefMap[NaturalMinorScale]}, "F# Natural Minor"},
+		{Scale{Root: 9, Def: ScaleDefMap[MinorPentatonicScale]}, "A Minor Pentatonic"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			text, err := tt.scale.MarshalText()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(text) != tt.want {
+				t.Errorf("MarshalText() = %s, want %s", text, tt.want)
+			}
+			var s Scale
+			if err := s.UnmarshalText(text); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(s, tt.scale) {
+				t.Errorf("expected %v, got %v", tt.scale, s)
+			}
+		})
+	}
+
+	var s Scale
+	if err := s.UnmarshalText([]byte("eb minor")); err != nil || s.Root != 3 || s.Def.Name != NaturalMinorScale {
+		t.Errorf("expected Eb Natural Minor, got %v (%v)", s, err)
+	}
+	for _, text := range []string{"", "Dorian", "H Major", "C Unknown"} {
+		if err := s.UnmarshalText([]byte(text)); err != ErrUnknownScale {
+			t.Errorf("expected %q to be unknown, got %v", text, err)
+		}
+	}
+}
+
+func TestChord_MarshalText(t *testing.T) {
+	fSharpMinor := &Chord{Keys: []int{
+		midi.KeyInt("A", 2), midi.KeyInt("F#", 3), midi.KeyInt("A", 3), midi.KeyInt("C#", 4), midi.KeyInt("E", 4)}}
+	tests := []struct {
+		name  string
+		chord *Chord
+		want  string
+	}{
+		{"slash chord", fSharpMinor, "F#m7/A (A2 F#3 A3 C#4 E4)"},
+		{"flats", &Chord{Keys: []int{midi.KeyInt("A#", 2), midi.KeyInt("D", 3), midi.KeyInt("F", 3)}, Spelling: FlatSpelling}, "Bb (Bb2 D3 F3)"},
+		{"spelled from the root", &Chord{Keys: []int{midi.KeyInt("C", 3), midi.KeyInt("E", 3), midi.KeyInt("G", 3), midi.KeyInt("A#", 3), midi.KeyInt("D#", 4)}}, "C7#9 (C3 E3 G3 Bb3 D#4)"},
+		{"unknown", &Chord{Keys: []int{midi.KeyInt("C", 3), midi.KeyInt("C#", 3)}}, "(C3 C#3)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, err := tt.chord.MarshalText()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(text) != tt.want {
+				t.Errorf("MarshalText() = %s, want %s", text, tt.want)
+			}
+			c := &Chord{}
+			if err := c.UnmarshalText(text); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(c.Keys, tt.chord.Keys) {
+				t.Errorf("expected keys %v, got %v", tt.chord.Keys, c.Keys)
+			}
+		})
+	}
+
+	c := &Chord{}
+	if err := c.UnmarshalText([]byte("F#m7/A")); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.Symbol(); got != "F#m7/A" {
+		t.Errorf("expected F#m7/A without voicing, got %s", got)
+	}
+	for _, text := range []string{"X7", "C (C3 E3", "C (C3 H3)", "C (C E G)"} {
+		if err := c.UnmarshalText([]byte(text)); err == nil {
+			t.Errorf("expected %q to fail", text)
+		}
+	}
+	for _, text := range []string{"C (D3 F3 A3)", "Cm (C3 E3 G3)", "C/E (C3 E3 G3)", "C (C3 C#3)"} {
+		if err := c.UnmarshalText([]byte(text)); err != ErrVoicingMismatch {
+			t.Errorf("expected %q to fail with %v, got %v", text, ErrVoicingMismatch, err)
+		}
+	}
+	for _, text := range []string{"Gb (F#3 A#3 C#4)", "Cmaj7 (C3 E3 G3 B3)", "C/E (E2 C3 G3)"} {
+		if err := c.UnmarshalText([]byte(text)); err != nil {
+			t.Errorf("expected %q to be valid, got %v", text, err)
+		}
+	}
+}
+
+func TestChordDefinition_MarshalText(t *testing.T) {
+	tests := []struct {
+		def  *ChordDefinition
+		want string
+	}{
+		{ChordDefs[0], "maj"},
+		{ChordDefs[5].WithRoot("Bb"), "Bbm7"},
+		{ChordDefs[7].WithRoot("C"), "Cmaj7"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			text, err := tt.def.MarshalText()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(text) != tt.want {
+				t.Errorf("MarshalText() = %s, want %s", text, tt.want)
+			}
+			def := &ChordDefinition{}
+			if err := def.UnmarshalText(text); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(def, tt.def) {
+				t.Errorf("expected %#v, got %#v", tt.def, def)
+			}
+		})
+	}
+}
+
+func TestMarshalJSON(t *testing.T) {
+	type analysis struct {
+		Scale  Scale
+		Mode   ScaleDefinition
+		Chords Chords
+		Def    *ChordDefinition
+	}
+	a := analysis{
+		Scale: Scale{Root: 2, Def: ScaleDefMap[DorianScale]},
+		Mode:  ScaleDefMap[LydianScale],
+		Chords: Chords{
+			{Keys: []int{midi.KeyInt("D", 3), midi.KeyInt("F", 3), midi.KeyInt("A", 3), midi.KeyInt("C", 4)}},
+			{Keys: []int{midi.KeyInt("G", 2), midi.KeyInt("B", 2), midi.KeyInt("D", 3), midi.KeyInt("F", 3)}},
+		},
+		Def: ChordDefs[1].WithRoot("E"),
+	}
+	data, err := json.Marshal(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"Scale":"D Dorian","Mode":"Lydian","Chords":["Dm7 (D3 F3 A3 C4)","G7 (G2 B2 D3 F3)"],"Def":"Em"}`
+	if string(data) != want {
+		t.Errorf("json.Marshal() = %s, want %s", data, want)
+	}
+	var got analysis
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, a) {
+		t.Errorf("expected %+v, got %+v", a, got)
+	}
+}
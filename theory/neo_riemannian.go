@@ -0,0 +1,272 @@
+package theory
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+var (
+	// ErrNotMajorMinorTriad is returned when a neo-Riemannian operation is
+	// applied to a chord that isn't a major or minor triad.
+	ErrNotMajorMinorTriad = errors.New("not a major or minor triad")
+	// ErrUnknownTransformation is returned when an unknown neo-Riemannian
+	// operation is requested.
+	ErrUnknownTransformation = errors.New("unknown neo-Riemannian transformation")
+)
+
+// NeoRiemannianOps maps the supported neo-Riemannian operations to their
+// sequence of elementary P, L, R transformations (applied left to right).
+//
+//	P (Parallel): C major <-> C minor
+//	L (Leading-tone exchange): C major <-> E minor
+//	R (Relative): C major <-> A minor
+//	N (Nebenverwandt, RLP): C major <-> F minor
+//	S (Slide, LPR): C major <-> C# minor
+//	H (Hexatonic pole, LPL): C major <-> G# minor
+var NeoRiemannianOps = map[rune]string{
+	'P': "P",
+	'L': "L",
+	'R': "R",
+	'N': "RLP",
+	'S': "LPR",
+	'H': "LPL",
+}
+
+// NeoRiemannian applies the passed sequence of neo-Riemannian operations
+// (for instance "PL" or "N") to a major or minor triad and returns the
+// resulting chord. The voicing of the chord is preserved, only the keys
+// required by each transformation are moved (parsimonious voice leading) and
+// the chord is spelled using the key it implies, like Transpose.
+func (c *Chord) NeoRiemannian(ops string) (*Chord, error) {
+	if c == nil {
+		return nil, ErrNotMajorMinorTriad
+	}
+	keys := make([]int, len(c.Keys))
+	copy(keys, c.Keys)
+	for _, op := range ops {
+		steps, ok := NeoRiemannianOps[op]
+		if !ok {
+			return nil, fmt.Errorf("%s - %q", ErrUnknownTransformation, op)
+		}
+		for _, step := range steps {
+			root, major, ok := triadRootAndQuality(keys)
+			if !ok {
+				return nil, ErrNotMajorMinorTriad
+			}
+			third, fifth := root+3, root+7
+			if major {
+				third = root + 4
+			}
+			var from, delta int
+			switch step {
+			case 'P':
+				from, delta = third, 1
+				if major {
+					delta = -1
+				}
+			case 'L':
+				from, delta = fifth, 1
+				if major {
+					from, delta = root, -1
+				}
+			case 'R':
+				from, delta = root, -2
+				if major {
+					from, delta = fifth, 2
+				}
+			}
+			for i, k := range keys {
+				if mod12(k) == mod12(from) {
+					keys[i] = k + delta
+				}
+			}
+		}
+	}
+	out := &Chord{Keys: keys}
+	out.Spelling = chordSpelling(out, c.Spelling)
+	return out, nil
+}
+
+// NeoRiemannianPath returns the shortest sequence of P, L and R operations
+// transforming the first triad into the second one (for instance "LP" to go
+// from C major to E major). An empty string is returned if both chords are
+// the same triad.
+func NeoRiemannianPath(from, to *Chord) (string, error) {
+	if from == nil || to == nil {
+		return "", ErrNotMajorMinorTriad
+	}
+	type triad struct {
+		root  int
+		major bool
+	}
+	var start, target triad
+	var ok bool
+	if start.root, start.major, ok = triadRootAndQuality(from.Keys); !ok {
+		return "", ErrNotMajorMinorTriad
+	}
+	if target.root, target.major, ok = triadRootAndQuality(to.Keys); !ok {
+		return "", ErrNotMajorMinorTriad
+	}
+
+	// breadth first search over the 24 major and minor triads
+	paths := map[triad]string{start: ""}
+	queue := []triad{start}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if current == target {
+			return paths[current], nil
+		}
+		chord := &Chord{Keys: triadKeys(current.root, current.major)}
+		for _, op := range "PLR" {
+			next, _ := chord.NeoRiemannian(string(op))
+			var t triad
+			t.root, t.major, _ = triadRootAndQuality(next.Keys)
+			if _, seen := paths[t]; !seen {
+				paths[t] = paths[current] + string(op)
+				queue = append(queue, t)
+			}
+		}
+	}
+	return "", ErrNotMajorMinorTriad
+}
+
+// TonnetzCoord is a position on the Tonnetz (the neo-Riemannian tone network).
+// X counts perfect fifths and Y major thirds so that the pitch class at a given
+// coordinate is (7X + 4Y) mod 12.
+type TonnetzCoord struct {
+	X float64
+	Y float64
+}
+
+// PitchClass returns the pitch class found at the coordinate (rounded to the
+// closest note).
+func (tc TonnetzCoord) PitchClass() int {
+	return mod12(7*int(math.Round(tc.X)) + 4*int(math.Round(tc.Y)))
+}
+
+func (tc TonnetzCoord) distance(o TonnetzCoord) float64 {
+	return math.Hypot(tc.X-o.X, tc.Y-o.Y)
+}
+
+// tonnetzPeriods are the translations leaving the pitch classes unchanged.
+var tonnetzPeriods = []TonnetzCoord{{X: 0, Y: 3}, {X: 4, Y: -1}}
+
+// NoteTonnetzCoord returns the canonical position of a note on the Tonnetz,
+// with 0 <= X < 4 and 0 <= Y < 3.
+func NoteTonnetzCoord(note int) TonnetzCoord {
+	pc := mod12(note)
+	for x := 0; x < 4; x++ {
+		for y := 0; y < 3; y++ {
+			if mod12(7*x+4*y) == pc {
+				return TonnetzCoord{X: float64(x), Y: float64(y)}
+			}
+		}
+	}
+	return TonnetzCoord{}
+}
+
+// TonnetzTriangle returns the coordinates of the root, third and fifth of a
+// major or minor triad on the Tonnetz. The triangle is placed using the
+// canonical position of the root.
+func (c *Chord) TonnetzTriangle() ([3]TonnetzCoord, error) {
+	var tri [3]TonnetzCoord
+	if c == nil {
+		return tri, ErrNotMajorMinorTriad
+	}
+	root, major, ok := triadRootAndQuality(c.Keys)
+	if !ok {
+		return tri, ErrNotMajorMinorTriad
+	}
+	r := NoteTonnetzCoord(root)
+	tri[0] = r
+	tri[2] = TonnetzCoord{X: r.X + 1, Y: r.Y}
+	if major {
+		tri[1] = TonnetzCoord{X: r.X, Y: r.Y + 1}
+	} else {
+		tri[1] = TonnetzCoord{X: r.X + 1, Y: r.Y - 1}
+	}
+	return tri, nil
+}
+
+// TonnetzCenter returns the center of the triangle formed by the triad on the
+// Tonnetz.
+func (c *Chord) TonnetzCenter() (TonnetzCoord, error) {
+	tri, err := c.TonnetzTriangle()
+	if err != nil {
+		return TonnetzCoord{}, err
+	}
+	return TonnetzCoord{
+		X: (tri[0].X + tri[1].X + tri[2].X) / 3,
+		Y: (tri[0].Y + tri[1].Y + tri[2].Y) / 3,
+	}, nil
+}
+
+// TonnetzPath returns the center of each triad of the progression on the
+// Tonnetz. Because the Tonnetz wraps around, each position is picked to be the
+// closest to the previous one so the path can be drawn as a continuous
+// movement.
+func (chords Chords) TonnetzPath() ([]TonnetzCoord, error) {
+	path := make([]TonnetzCoord, 0, len(chords))
+	for i, c := range chords {
+		center, err := c.TonnetzCenter()
+		if err != nil {
+			return nil, fmt.Errorf("chord %d - %s", i, err)
+		}
+		if i > 0 {
+			prev := path[i-1]
+			best := center
+			for a := -3; a <= 3; a++ {
+				for b := -3; b <= 3; b++ {
+					candidate := TonnetzCoord{
+						X: center.X + float64(a)*tonnetzPeriods[0].X + float64(b)*tonnetzPeriods[1].X,
+						Y: center.Y + float64(a)*tonnetzPeriods[0].Y + float64(b)*tonnetzPeriods[1].Y,
+					}
+					if candidate.distance(prev) < best.distance(prev) {
+						best = candidate
+					}
+				}
+			}
+			center = best
+		}
+		path = append(path, center)
+	}
+	return path, nil
+}
+
+// triadRootAndQuality returns the root pitch class of the keys if they form a
+// major or minor triad (duplicated notes are allowed).
+func triadRootAndQuality(keys []int) (root int, major bool, ok bool) {
+	var pcs [12]bool
+	var count int
+	for _, k := range keys {
+		if !pcs[mod12(k)] {
+			pcs[mod12(k)] = true
+			count++
+		}
+	}
+	if count != 3 {
+		return 0, false, false
+	}
+	for r := 0; r < 12; r++ {
+		if !pcs[r] || !pcs[mod12(r+7)] {
+			continue
+		}
+		if pcs[mod12(r+4)] {
+			return r, true, true
+		}
+		if pcs[mod12(r+3)] {
+			return r, false, true
+		}
+	}
+	return 0, false, false
+}
+
+// triadKeys returns the keys of the major or minor triad in root position.
+func triadKeys(root int, major bool) []int {
+	if major {
+		return []int{root, root + 4, root + 7}
+	}
+	return []int{root, root + 3, root + 7}
+}
@@ -0,0 +1,156 @@
+package theory
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-audio/midi"
+)
+
+func TestChord_NeoRiemannian(t *testing.T) {
+	cMajor := []int{
+		midi.KeyInt("C", 3),
+		midi.KeyInt("E", 3),
+		midi.KeyInt("G", 3),
+	}
+	tests := []struct {
+		name     string
+		keys     []int
+		ops      string
+		wantKeys []int
+		wantDef  string
+		wantErr  bool
+	}{
+		{name: "P", keys: cMajor, ops: "P",
+			wantKeys: []int{midi.KeyInt("C", 3), midi.KeyInt("D#", 3), midi.KeyInt("G", 3)},
+			wantDef:  "C Minor"},
+		{name: "L", keys: cMajor, ops: "L",
+			wantKeys: []int{midi.KeyInt("B", 2), midi.KeyInt("E", 3), midi.KeyInt("G", 3)},
+			wantDef:  "E Minor"},
+		{name: "R", keys: cMajor, ops: "R",
+			wantKeys: []int{midi.KeyInt("C", 3), midi.KeyInt("E", 3), midi.KeyInt("A", 3)},
+			wantDef:  "A Minor"},
+		{name: "PP is the identity", keys: cMajor, ops: "PP", wantKeys: cMajor, wantDef: "C Major"},
+		{name: "N", keys: cMajor, ops: "N", wantDef: "F Minor",
+			wantKeys: []int{midi.KeyInt("C", 3), midi.KeyInt("F", 3), midi.KeyInt("G#", 3)}},
+		{name: "S", keys: cMajor, ops: "S", wantDef: "C# Minor",
+			wantKeys: []int{midi.KeyInt("C#", 3), midi.KeyInt("E", 3), midi.KeyInt("G#", 3)}},
+		{name: "H", keys: cMajor, ops: "H", wantDef: "G# Minor",
+			wantKeys: []int{midi.KeyInt("B", 2), midi.KeyInt("D#", 3), midi.KeyInt("G#", 3)}},
+		{name: "minor R with doubled root",
+			keys:     []int{midi.KeyInt("A", 2), midi.KeyInt("C", 3), midi.KeyInt("E", 3), midi.KeyInt("A", 3)},
+			ops:      "R",
+			wantKeys: []int{midi.KeyInt("G", 2), midi.KeyInt("C", 3), midi.KeyInt("E", 3), midi.KeyInt("G", 3)},
+			wantDef:  "C Major"},
+		{name: "not a triad", keys: []int{60, 64, 67, 70}, ops: "P", wantErr: true},
+		{name: "unknown op", keys: cMajor, ops: "X", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Chord{Keys: tt.keys}
+			got, err := c.NeoRiemannian(tt.ops)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Chord.NeoRiemannian() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(got.Keys, tt.wantKeys) {
+				t.Errorf("Chord.NeoRiemannian() keys = %v, want %v", keyNames(got.Keys), keyNames(tt.wantKeys))
+			}
+			if def := got.Def().String(); def != tt.wantDef {
+				t.Errorf("Chord.NeoRiemannian() = %s, want %s", def, tt.wantDef)
+			}
+		})
+	}
+}
+
+func TestChord_NeoRiemannian_spelling(t *testing.T) {
+	tests := []struct {
+		name  string
+		chord *Chord
+		ops   string
+		want  []string
+	}{
+		{"parallel minor uses flats", &Chord{Keys: []int{60, 64, 67}}, "P", []string{"C", "Eb", "G"}},
+		{"relative minor of Bb", &Chord{Keys: []int{58, 62, 65}, Spelling: FlatSpelling}, "R", []string{"Bb", "D", "G"}},
+		{"Gb is kept", &Chord{Keys: []int{54, 58, 61}, Spelling: FlatSpelling}, "PP", []string{"Gb", "Bb", "Db"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.chord.NeoRiemannian(tt.ops)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if names := got.NoteNames(); !reflect.DeepEqual(names, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, names)
+			}
+		})
+	}
+}
+
+func TestNeoRiemannianPath(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to string
+		want     string
+	}{
+		{"same chord", "Cmaj", "Cmaj", ""},
+		{"parallel", "Cmaj", "Cmin", "P"},
+		{"chromatic mediant", "Cmaj", "Emaj", "LP"},
+		{"tritone away", "Cmaj", "F#maj", "PRPR"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to := NewChordFromAbbrev(tt.from), NewChordFromAbbrev(tt.to)
+			got, err := NeoRiemannianPath(from, to)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(tt.want) {
+				t.Errorf("NeoRiemannianPath() = %v, want %v", got, tt.want)
+			}
+			moved, _ := from.NeoRiemannian(got)
+			if moved.Def().String() != to.Def().String() {
+				t.Errorf("applying %s to %s gives %s, expected %s", got, tt.from, moved.Def(), to.Def())
+			}
+		})
+	}
+	if _, err := NeoRiemannianPath(NewChordFromAbbrev("C7"), NewChordFromAbbrev("Cmaj")); err != ErrNotMajorMinorTriad {
+		t.Errorf("expected ErrNotMajorMinorTriad, got %v", err)
+	}
+}
+
+func TestTonnetz(t *testing.T) {
+	for note := -12; note < 24; note++ {
+		if got := NoteTonnetzCoord(note).PitchClass(); got != mod12(note) {
+			t.Errorf("NoteTonnetzCoord(%d) points to %d", note, got)
+		}
+	}
+
+	tri, err := NewChordFromAbbrev("Amin").TonnetzTriangle()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []int{9, 0, 4} {
+		if got := tri[i].PitchClass(); got != want {
+			t.Errorf("TonnetzTriangle()[%d] = %d, want %d", i, got, want)
+		}
+	}
+
+	// each PLR step moves to an adjacent triangle sharing an edge
+	chords := Chords{NewChordFromAbbrev("Cmaj")}
+	for _, op := range "RLPRLP" {
+		next, _ := chords[len(chords)-1].NeoRiemannian(string(op))
+		chords = append(chords, next)
+	}
+	path, err := chords.TonnetzPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i < len(path); i++ {
+		if d := path[i].distance(path[i-1]); d > 1 {
+			t.Errorf("step %d moved too far on the Tonnetz: %v -> %v", i, path[i-1], path[i])
+		}
+	}
+}
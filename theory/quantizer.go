@@ -0,0 +1,148 @@
+package theory
+
+// QuantizeMode defines how the Quantizer moves out of scale notes.
+type QuantizeMode int
+
+const (
+	// QuantizeNearest moves the note to the closest note in scale, ties are
+	// resolved using the quantizer TieRule.
+	QuantizeNearest QuantizeMode = iota
+	// QuantizeUp moves the note up to the next note in scale.
+	QuantizeUp
+	// QuantizeDown moves the note down to the previous note in scale.
+	QuantizeDown
+	// QuantizeNearestChordTone moves the note to the closest tone of the
+	// quantizer Chord (or of the tonic triad of the scale if no chord is set).
+	// Ties are resolved towards the root of the chord.
+	QuantizeNearestChordTone
+)
+
+// TieRule defines which note to pick when a note is as far from the scale note
+// below as from the scale note above.
+type TieRule int
+
+const (
+	// TieDown picks the lower note.
+	TieDown TieRule = iota
+	// TieUp picks the upper note.
+	TieUp
+	// TieFollowDirection picks the note in the direction of the melody (see
+	// Quantizer.Next), ascending lines go up and descending lines go down.
+	TieFollowDirection
+)
+
+// Quantizer moves notes so they fit in a scale. A quantizer is stateless when
+// using Quantize but remembers the direction of the melody when notes are
+// streamed via Next so repeated ties don't create jagged lines.
+type Quantizer struct {
+	Scale *Scale
+	Mode  QuantizeMode
+	Tie   TieRule
+	// Chord is the chord used by the QuantizeNearestChordTone mode.
+	Chord *Chord
+
+	lastNote  int
+	hasLast   bool
+	direction int
+}
+
+// NewQuantizer returns a quantizer for the passed scale and mode.
+func NewQuantizer(s *Scale, mode QuantizeMode) *Quantizer {
+	return &Quantizer{Scale: s, Mode: mode}
+}
+
+// Offset returns the number of half steps to add to the note to quantize it.
+func (q *Quantizer) Offset(note int) int {
+	return q.offset(note, 0)
+}
+
+// Quantize returns the quantized note.
+func (q *Quantizer) Quantize(note int) int {
+	return note + q.Offset(note)
+}
+
+// Next quantizes the next note of a stream of notes. The direction of the
+// incoming melody is remembered (repeated notes keep the previous direction)
+// and used to resolve ties when the tie rule is TieFollowDirection.
+func (q *Quantizer) Next(note int) int {
+	if q.hasLast && note != q.lastNote {
+		q.direction = 1
+		if note < q.lastNote {
+			q.direction = -1
+		}
+	}
+	q.lastNote = note
+	q.hasLast = true
+	return note + q.offset(note, q.direction)
+}
+
+// QuantizeAll quantizes a stream of notes (see Next).
+func (q *Quantizer) QuantizeAll(notes []int) []int {
+	out := make([]int, len(notes))
+	for i, n := range notes {
+		out[i] = q.Next(n)
+	}
+	return out
+}
+
+// Reset forgets the direction of the melody.
+func (q *Quantizer) Reset() {
+	q.lastNote, q.hasLast, q.direction = 0, false, 0
+}
+
+func (q *Quantizer) offset(note, direction int) int {
+	if q == nil || q.Scale == nil {
+		return 0
+	}
+	var allowed [12]bool
+	rootPC := -1
+	if q.Mode == QuantizeNearestChordTone {
+		chord := q.Chord
+		if chord == nil {
+			root := mod12(q.Scale.Root)
+			chord = &Chord{Keys: []int{root, q.Scale.StepNote(root, 2), q.Scale.StepNote(root, 4)}}
+		}
+		for _, k := range chord.Keys {
+			allowed[mod12(k)] = true
+		}
+		rootPC = chord.Copy().Def().RootInt()
+	} else {
+		for _, pc := range q.Scale.Def.pitchClasses() {
+			allowed[mod12(pc+q.Scale.Root)] = true
+		}
+	}
+
+	for d := 0; d < 12; d++ {
+		below, above := allowed[mod12(note-d)], allowed[mod12(note+d)]
+		switch q.Mode {
+		case QuantizeUp:
+			if above {
+				return d
+			}
+			continue
+		case QuantizeDown:
+			if below {
+				return -d
+			}
+			continue
+		}
+		switch {
+		case below && above:
+			if q.Mode == QuantizeNearestChordTone {
+				if mod12(note+d) == rootPC {
+					return d
+				}
+				return -d
+			}
+			if q.Tie == TieUp || (q.Tie == TieFollowDirection && direction > 0) {
+				return d
+			}
+			return -d
+		case below:
+			return -d
+		case above:
+			return d
+		}
+	}
+	return 0
+}
@@ -0,0 +1,117 @@
+package theory
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-audio/midi"
+)
+
+func TestQuantizer_Quantize(t *testing.T) {
+	cMajor := &Scale{Root: 60, Def: ScaleDefMap[MajorScale]}
+	aMinorPenta := &Scale{Root: 9, Def: ScaleDefMap[MinorPentatonicScale]}
+	chromatic := []int{60, 61, 62, 63, 64, 65, 66, 67, 68, 69, 70, 71}
+	tests := []struct {
+		name  string
+		q     *Quantizer
+		notes []int
+		want  []int
+	}{
+		{
+			name:  "down",
+			q:     NewQuantizer(cMajor, QuantizeDown),
+			notes: chromatic,
+			want:  []int{60, 60, 62, 62, 64, 65, 65, 67, 67, 69, 69, 71},
+		},
+		{
+			name:  "up",
+			q:     NewQuantizer(cMajor, QuantizeUp),
+			notes: chromatic,
+			want:  []int{60, 62, 62, 64, 64, 65, 67, 67, 69, 69, 71, 71},
+		},
+		{
+			name:  "nearest with ties going up",
+			q:     &Quantizer{Scale: cMajor, Mode: QuantizeNearest, Tie: TieUp},
+			notes: chromatic,
+			want:  []int{60, 62, 62, 64, 64, 65, 67, 67, 69, 69, 71, 71},
+		},
+		{
+			name:  "nearest on a scale with whole step gaps",
+			q:     NewQuantizer(aMinorPenta, QuantizeNearest),
+			notes: []int{midi.KeyInt("A#", 2), midi.KeyInt("B", 2), midi.KeyInt("C#", 3), midi.KeyInt("F#", 3)},
+			want:  []int{midi.KeyInt("A", 2), midi.KeyInt("C", 3), midi.KeyInt("C", 3), midi.KeyInt("G", 3)},
+		},
+		{
+			name:  "down on a scale with whole step gaps",
+			q:     NewQuantizer(aMinorPenta, QuantizeDown),
+			notes: []int{midi.KeyInt("B", 2), midi.KeyInt("F#", 3)},
+			want:  []int{midi.KeyInt("A", 2), midi.KeyInt("E", 3)},
+		},
+		{
+			name:  "negative notes",
+			q:     NewQuantizer(cMajor, QuantizeUp),
+			notes: []int{-1, -2, -11},
+			want:  []int{-1, -1, -10},
+		},
+		{
+			name:  "chord tones",
+			q:     &Quantizer{Scale: cMajor, Mode: QuantizeNearestChordTone, Chord: NewChordFromAbbrev("Cmaj")},
+			notes: []int{midi.KeyInt("B", 3), midi.KeyInt("D", 3), midi.KeyInt("A", 3)},
+			want:  []int{midi.KeyInt("C", 4), midi.KeyInt("C", 3), midi.KeyInt("G", 3)},
+		},
+		{
+			name:  "chord tones ties go to the root",
+			q:     &Quantizer{Scale: cMajor, Mode: QuantizeNearestChordTone, Chord: NewChordFromAbbrev("Caug")},
+			notes: []int{midi.KeyInt("A#", 3), midi.KeyInt("D", 3)},
+			want:  []int{midi.KeyInt("C", 4), midi.KeyInt("C", 3)},
+		},
+		{
+			name:  "tonic triad when no chord is set",
+			q:     NewQuantizer(cMajor, QuantizeNearestChordTone),
+			notes: []int{midi.KeyInt("D", 3), midi.KeyInt("A", 3), midi.KeyInt("F", 3)},
+			want:  []int{midi.KeyInt("C", 3), midi.KeyInt("G", 3), midi.KeyInt("E", 3)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := make([]int, len(tt.notes))
+			for i, n := range tt.notes {
+				got[i] = tt.q.Quantize(n)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Quantizer.Quantize() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuantizer_QuantizeAll(t *testing.T) {
+	cMajor := &Scale{Root: 0, Def: ScaleDefMap[MajorScale]}
+	q := &Quantizer{Scale: cMajor, Mode: QuantizeNearest, Tie: TieFollowDirection}
+	// ascending then descending chromatic line
+	line := []int{60, 61, 62, 63, 64, 63, 62, 61, 60}
+	want := []int{60, 62, 62, 64, 64, 62, 62, 60, 60}
+	if got := q.QuantizeAll(line); !reflect.DeepEqual(got, want) {
+		t.Errorf("Quantizer.QuantizeAll() = %v, want %v", got, want)
+	}
+	q.Reset()
+	if got := q.Next(61); got != 60 {
+		t.Errorf("expected a reset quantizer without direction to resolve ties down, got %d", got)
+	}
+}
+
+func TestScale_OffsetForNote_allScales(t *testing.T) {
+	for _, def := range ScaleDefs {
+		s := &Scale{Root: 2, Def: def}
+		inScale := map[int]bool{}
+		for _, n := range s.Notes() {
+			inScale[n] = true
+		}
+		for note := -24; note < 24; note++ {
+			adjusted := s.AdjustedNote(note)
+			if !inScale[mod12(adjusted)] || adjusted > note {
+				t.Errorf("%s: AdjustedNote(%d) = %d isn't the closest lower note in scale", s, note, adjusted)
+			}
+		}
+	}
+}
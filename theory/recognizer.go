@@ -0,0 +1,386 @@
+package theory
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-audio/midi"
+)
+
+// ChordChange is emitted by a Recognizer when the sounding notes change.
+type ChordChange struct {
+	// Chord is the sounding chord, nil when less than 2 pitch classes are
+	// sounding.
+	Chord *Chord
+	// Def is the definition of the chord as found by Chord.Def, nil when no
+	// chord is sounding.
+	Def *ChordDefinition
+	// Keys are the sounding keys (held or sustained), lowest first.
+	Keys []int
+	// Time is when the change happened (before debouncing).
+	Time time.Time
+}
+
+// Recognizer incrementally tracks note on/off and sustain pedal events coming
+// from a live MIDI input and reports chord changes. Changes are debounced so
+// notes of a chord that aren't pressed exactly at the same time only trigger
+// one change. A Recognizer is safe for concurrent use.
+type Recognizer struct {
+	// Debounce is how long the sounding notes need to stay the same before a
+	// change is reported. 0 reports every change right away.
+	Debounce time.Duration
+	// IgnoreSustain ignores the sustain pedal, only held keys are considered.
+	IgnoreSustain bool
+	// IncludeDrums includes the notes played on the drum channel.
+	IncludeDrums bool
+	// OnChange is called with every chord change.
+	OnChange func(ChordChange)
+
+	mu        sync.Mutex
+	held      [16][128]bool
+	sustained [16][128]bool
+	pedal     [16]bool
+	current   []int
+	pending   *ChordChange
+	last      *ChordChange
+	// changes are reported once the lock is released so OnChange can call
+	// the recognizer.
+	changes []ChordChange
+	// listeners queue the changes for the Listen channels.
+	listeners []*listener
+}
+
+// NewRecognizer returns a recognizer debouncing changes by the passed duration
+// and calling onChange (if not nil) with each chord change.
+func NewRecognizer(debounce time.Duration, onChange func(ChordChange)) *Recognizer {
+	return &Recognizer{Debounce: debounce, OnChange: onChange, current: []int{}}
+}
+
+// HandleEvent processes a MIDI event received at the passed time. Note on,
+// note off and sustain pedal events are used, other events are ignored.
+func (r *Recognizer) HandleEvent(ev *midi.Event, at time.Time) {
+	if ev == nil {
+		return
+	}
+	switch ev.MsgType {
+	case midi.EventByteMap["NoteOn"]:
+		r.NoteOn(int(ev.MsgChan), int(ev.Note), int(ev.Velocity), at)
+	case midi.EventByteMap["NoteOff"]:
+		r.NoteOff(int(ev.MsgChan), int(ev.Note), at)
+	case midi.EventByteMap["ControlChange"]:
+		if ev.Controller == SustainPedal {
+			r.Sustain(int(ev.MsgChan), ev.NewValue >= 64, at)
+		}
+	}
+}
+
+// NoteOn registers a key pressed on the channel. A velocity of 0 is treated as
+// a note off.
+func (r *Recognizer) NoteOn(channel, key, velocity int, at time.Time) {
+	if velocity == 0 {
+		r.NoteOff(channel, key, at)
+		return
+	}
+	if !r.validNote(channel, key) {
+		return
+	}
+	r.mu.Lock()
+	r.poll(at)
+	r.held[channel][key] = true
+	r.sustained[channel][key] = false
+	r.update(at)
+	r.unlock()
+}
+
+// NoteOff registers a key released on the channel. The key keeps sounding if
+// the sustain pedal of the channel is down.
+func (r *Recognizer) NoteOff(channel, key int, at time.Time) {
+	if !r.validNote(channel, key) {
+		return
+	}
+	r.mu.Lock()
+	r.poll(at)
+	if r.held[channel][key] {
+		r.held[channel][key] = false
+		r.sustained[channel][key] = r.pedal[channel] && !r.IgnoreSustain
+	}
+	r.update(at)
+	r.unlock()
+}
+
+// Sustain registers the sustain pedal of the channel being pressed or
+// released.
+func (r *Recognizer) Sustain(channel int, down bool, at time.Time) {
+	if channel < 0 || channel > 15 {
+		return
+	}
+	r.mu.Lock()
+	r.poll(at)
+	r.pedal[channel] = down
+	if !down {
+		r.sustained[channel] = [128]bool{}
+	}
+	r.update(at)
+	r.unlock()
+}
+
+// Reset releases all the keys and pedals, reporting the change if notes were
+// sounding.
+func (r *Recognizer) Reset(at time.Time) {
+	r.mu.Lock()
+	r.held = [16][128]bool{}
+	r.sustained = [16][128]bool{}
+	r.pedal = [16]bool{}
+	r.update(at)
+	r.unlock()
+}
+
+// Poll reports the pending change if it has been stable for the debounce
+// duration at the passed time. Events already poll, call Poll regularly (or
+// use Listen) to get changes reported when no new events come in.
+func (r *Recognizer) Poll(now time.Time) {
+	r.mu.Lock()
+	r.poll(now)
+	r.unlock()
+}
+
+// Flush reports the pending change right away.
+func (r *Recognizer) Flush() {
+	r.mu.Lock()
+	r.emit()
+	r.unlock()
+}
+
+// Keys returns the sounding keys (held or sustained), lowest first.
+func (r *Recognizer) Keys() []int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	keys := make([]int, len(r.current))
+	copy(keys, r.current)
+	return keys
+}
+
+// HeldKeys returns the keys currently held down, lowest first.
+func (r *Recognizer) HeldKeys() []int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.keys(false)
+}
+
+// Chord returns the last reported chord, nil if no chord is sounding.
+func (r *Recognizer) Chord() *Chord {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.last == nil {
+		return nil
+	}
+	return r.last.Chord
+}
+
+// Listen consumes the events of the passed channel as they arrive and returns
+// a channel receiving the chord changes. The changes are still passed to
+// OnChange if set. Changes are queued so the recognizer never waits for the
+// consumer. Once the events channel is closed, the pending change is reported
+// and the returned channel is closed after delivering the queued changes.
+func (r *Recognizer) Listen(events <-chan *midi.Event) <-chan ChordChange {
+	out := make(chan ChordChange)
+	l := &listener{notify: make(chan struct{}, 1)}
+	r.mu.Lock()
+	r.listeners = append(r.listeners, l)
+	r.mu.Unlock()
+
+	go func() {
+		defer close(out)
+		timer := time.NewTimer(time.Hour)
+		timer.Stop()
+		for {
+			// only send when a change is queued
+			var send chan<- ChordChange
+			next, queued := l.peek()
+			if queued {
+				send = out
+			}
+			select {
+			case ev, ok := <-events:
+				if !ok {
+					r.Flush()
+					r.removeListener(l)
+					for _, change := range l.close() {
+						out <- change
+					}
+					return
+				}
+				r.HandleEvent(ev, time.Now())
+			case now := <-timer.C:
+				r.Poll(now)
+			case send <- next:
+				l.pop()
+			case <-l.notify:
+			}
+			r.mu.Lock()
+			if r.pending != nil {
+				// drain the timer if it fired while handling an event so
+				// Reset doesn't leave a stale tick in the channel
+				if !timer.Stop() {
+					select {
+					case <-timer.C:
+					default:
+					}
+				}
+				timer.Reset(r.Debounce - time.Since(r.pending.Time))
+			}
+			r.mu.Unlock()
+		}
+	}()
+	return out
+}
+
+// removeListener stops delivering changes to the listener.
+func (r *Recognizer) removeListener(l *listener) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, other := range r.listeners {
+		if other == l {
+			r.listeners = append(r.listeners[:i], r.listeners[i+1:]...)
+			return
+		}
+	}
+}
+
+// listener queues the changes reported to a Listen channel. Changes pushed
+// after the listener is closed are dropped.
+type listener struct {
+	mu     sync.Mutex
+	queue  []ChordChange
+	closed bool
+	// notify wakes up the Listen goroutine when a change is queued.
+	notify chan struct{}
+}
+
+func (l *listener) push(change ChordChange) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return
+	}
+	l.queue = append(l.queue, change)
+	select {
+	case l.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (l *listener) peek() (ChordChange, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.queue) == 0 {
+		return ChordChange{}, false
+	}
+	return l.queue[0], true
+}
+
+func (l *listener) pop() {
+	l.mu.Lock()
+	l.queue = l.queue[1:]
+	l.mu.Unlock()
+}
+
+// close stops queuing changes and returns the ones not delivered yet.
+func (l *listener) close() []ChordChange {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.closed = true
+	queue := l.queue
+	l.queue = nil
+	return queue
+}
+
+func (r *Recognizer) validNote(channel, key int) bool {
+	if channel < 0 || channel > 15 || key < 0 || key > 127 {
+		return false
+	}
+	return r.IncludeDrums || channel != DrumChannel
+}
+
+// keys returns the held keys and, if requested, the sustained ones.
+func (r *Recognizer) keys(withSustained bool) []int {
+	seen := [128]bool{}
+	for ch := range r.held {
+		for k := range r.held[ch] {
+			if r.held[ch][k] || (withSustained && r.sustained[ch][k]) {
+				seen[k] = true
+			}
+		}
+	}
+	keys := []int{}
+	for k, ok := range seen {
+		if ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Ints(keys)
+	return keys
+}
+
+// update refreshes the sounding keys and schedules a change when they differ
+// from the last reported ones.
+func (r *Recognizer) update(at time.Time) {
+	keys := r.keys(!r.IgnoreSustain)
+	if intsEqual(keys, r.current) {
+		return
+	}
+	r.current = keys
+	var lastKeys []int
+	if r.last != nil {
+		lastKeys = r.last.Keys
+	}
+	if intsEqual(keys, lastKeys) || (r.last == nil && len(keys) == 0) {
+		// back to what was reported, nothing to report
+		r.pending = nil
+		return
+	}
+	r.pending = &ChordChange{Keys: keys, Time: at}
+	if r.Debounce <= 0 {
+		r.emit()
+	}
+}
+
+// poll reports the pending change if it's been stable long enough.
+func (r *Recognizer) poll(now time.Time) {
+	if r.pending != nil && now.Sub(r.pending.Time) >= r.Debounce {
+		r.emit()
+	}
+}
+
+func (r *Recognizer) emit() {
+	if r.pending == nil {
+		return
+	}
+	change := *r.pending
+	r.pending = nil
+	if countPitchClasses(change.Keys) >= 2 {
+		keys := make([]int, len(change.Keys))
+		copy(keys, change.Keys)
+		change.Chord = &Chord{Keys: keys}
+		change.Def = change.Chord.Copy().Def()
+	}
+	r.last = &change
+	r.changes = append(r.changes, change)
+	for _, l := range r.listeners {
+		l.push(change)
+	}
+}
+
+// unlock releases the lock and reports the changes found while holding it.
+func (r *Recognizer) unlock() {
+	changes, onChange := r.changes, r.OnChange
+	r.changes = nil
+	r.mu.Unlock()
+	if onChange == nil {
+		return
+	}
+	for _, change := range changes {
+		onChange(change)
+	}
+}
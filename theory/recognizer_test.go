@@ -0,0 +1,263 @@
+package theory
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-audio/midi"
+)
+
+// changeName returns the name of the chord of a change, "" when silent.
+func changeName(change ChordChange) string {
+	if change.Def == nil {
+		return ""
+	}
+	return change.Def.String()
+}
+
+func TestRecognizer(t *testing.T) {
+	c, e, g := midi.KeyInt("C", 3), midi.KeyInt("E", 3), midi.KeyInt("G", 3)
+	f, a, c4 := midi.KeyInt("F", 3), midi.KeyInt("A", 3), midi.KeyInt("C", 4)
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	ms := func(n int) time.Time { return start.Add(time.Duration(n) * time.Millisecond) }
+
+	tests := []struct {
+		name     string
+		debounce time.Duration
+		play     func(r *Recognizer)
+		want     []string
+	}{
+		{name: "no debounce",
+			play: func(r *Recognizer) {
+				r.NoteOn(0, c, 100, ms(0))
+				r.NoteOn(0, e, 100, ms(5))
+				r.NoteOn(0, g, 100, ms(10))
+			},
+			// a single note isn't a chord
+			want: []string{"", "Unknown", "C Major"},
+		},
+		{name: "debounced strum",
+			debounce: 30 * time.Millisecond,
+			play: func(r *Recognizer) {
+				r.NoteOn(0, c, 100, ms(0))
+				r.NoteOn(0, e, 100, ms(5))
+				r.NoteOn(0, g, 100, ms(10))
+				r.Poll(ms(20))
+				r.Poll(ms(40))
+				// quick change of chord, the release isn't reported
+				r.NoteOff(0, c, ms(500))
+				r.NoteOff(0, e, ms(500))
+				r.NoteOff(0, g, ms(500))
+				r.NoteOn(0, f, 100, ms(510))
+				r.NoteOn(0, a, 100, ms(512))
+				r.NoteOn(0, c4, 100, ms(515))
+				r.Flush()
+			},
+			want: []string{"C Major", "F Major"},
+		},
+		{name: "sustain pedal",
+			play: func(r *Recognizer) {
+				r.Sustain(0, true, ms(0))
+				r.NoteOn(0, c, 100, ms(0))
+				r.NoteOff(0, c, ms(10))
+				r.NoteOn(0, e, 100, ms(20))
+				r.NoteOff(0, e, ms(30))
+				r.NoteOn(0, g, 100, ms(40))
+				r.NoteOff(0, g, ms(50))
+				r.Sustain(0, false, ms(100))
+			},
+			want: []string{"", "Unknown", "C Major", ""},
+		},
+		{name: "note on with velocity 0 and drums ignored",
+			play: func(r *Recognizer) {
+				r.NoteOn(DrumChannel, 36, 100, ms(0))
+				r.NoteOn(0, c, 100, ms(0))
+				r.NoteOn(1, e, 100, ms(0))
+				r.NoteOn(2, g, 100, ms(0))
+				r.NoteOn(1, e, 0, ms(10))
+			},
+			want: []string{"", "Unknown", "C Major", "C Fifth"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := []string{}
+			r := NewRecognizer(tt.debounce, func(change ChordChange) {
+				got = append(got, changeName(change))
+			})
+			tt.play(r)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRecognizer_HandleEvent(t *testing.T) {
+	c, e, g := midi.KeyInt("C", 3), midi.KeyInt("E", 3), midi.KeyInt("G", 3)
+	now := time.Now()
+	var changes []ChordChange
+	r := NewRecognizer(0, func(change ChordChange) { changes = append(changes, change) })
+	r.HandleEvent(midi.ControlChange(0, SustainPedal, 127), now)
+	for _, k := range []int{g, e, c} {
+		r.HandleEvent(midi.NoteOn(0, k, 100), now)
+	}
+	r.HandleEvent(midi.NoteOff(0, g), now)
+
+	if got := r.HeldKeys(); !reflect.DeepEqual(got, []int{c, e}) {
+		t.Errorf("expected held keys %v, got %v", []int{c, e}, got)
+	}
+	if got := r.Keys(); !reflect.DeepEqual(got, []int{c, e, g}) {
+		t.Errorf("expected sounding keys %v, got %v", []int{c, e, g}, got)
+	}
+	if got := r.Chord(); got == nil || got.Def().String() != "C Major" {
+		t.Errorf("expected C Major, got %v", got)
+	}
+	// the voicing of the reported chord is kept
+	last := changes[len(changes)-1]
+	if !reflect.DeepEqual(last.Chord.Keys, []int{c, e, g}) {
+		t.Errorf("expected the chord keys to be %v, got %v", []int{c, e, g}, last.Chord.Keys)
+	}
+
+	r.HandleEvent(midi.ControlChange(0, SustainPedal, 0), now)
+	if got := r.Keys(); !reflect.DeepEqual(got, []int{c, e}) {
+		t.Errorf("expected sounding keys %v once the pedal released, got %v", []int{c, e}, got)
+	}
+	r.Reset(now)
+	if got := r.Keys(); len(got) != 0 {
+		t.Errorf("expected no sounding keys after a reset, got %v", got)
+	}
+}
+
+func TestRecognizer_Listen(t *testing.T) {
+	events := make(chan *midi.Event)
+	r := NewRecognizer(10*time.Millisecond, nil)
+	changes := r.Listen(events)
+	go func() {
+		for _, n := range []string{"D", "F#", "A"} {
+			events <- midi.NoteOn(0, midi.KeyInt(n, 3), 100)
+		}
+		time.Sleep(50 * time.Millisecond)
+		events <- midi.NoteOff(0, midi.KeyInt("F#", 3))
+		events <- midi.NoteOn(0, midi.KeyInt("F", 3), 100)
+		close(events)
+	}()
+	got := []string{}
+	for change := range changes {
+		got = append(got, changeName(change))
+	}
+	if want := []string{"D Major", "D Minor"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestRecognizer_Listen_afterClose(t *testing.T) {
+	var reported []string
+	r := NewRecognizer(0, func(change ChordChange) {
+		reported = append(reported, changeName(change))
+	})
+	events := make(chan *midi.Event)
+	changes := r.Listen(events)
+	close(events)
+	for range changes {
+	}
+	// used to panic sending on the closed channel
+	now := time.Now()
+	for _, n := range []string{"C", "E", "G"} {
+		r.NoteOn(0, midi.KeyInt(n, 3), 100, now)
+	}
+	r.Reset(now)
+	r.Flush()
+	if want := []string{"", "Unknown", "C Major", ""}; !reflect.DeepEqual(reported, want) {
+		t.Errorf("expected OnChange to still be called, got %q", reported)
+	}
+}
+
+func TestRecognizer_Listen_onChangeSetWhileListening(t *testing.T) {
+	r := NewRecognizer(0, nil)
+	events := make(chan *midi.Event)
+	changes := r.Listen(events)
+	var reported []string
+	r.OnChange = func(change ChordChange) {
+		reported = append(reported, changeName(change))
+	}
+	go func() {
+		for _, n := range []string{"C", "E", "G"} {
+			events <- midi.NoteOn(0, midi.KeyInt(n, 3), 100)
+		}
+		close(events)
+	}()
+	got := []string{}
+	for change := range changes {
+		got = append(got, changeName(change))
+	}
+	want := []string{"", "Unknown", "C Major"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %q on the channel, got %q", want, got)
+	}
+	r.Reset(time.Now())
+	if want = append(want, ""); !reflect.DeepEqual(reported, want) {
+		t.Errorf("expected OnChange to be kept, got %q", reported)
+	}
+}
+
+func TestRecognizer_Listen_concurrent(t *testing.T) {
+	r := NewRecognizer(0, nil)
+	first, second := make(chan *midi.Event), make(chan *midi.Event)
+	firstChanges, secondChanges := r.Listen(first), r.Listen(second)
+	now := time.Now()
+	for _, n := range []string{"C", "E", "G"} {
+		r.NoteOn(0, midi.KeyInt(n, 3), 100, now)
+	}
+	close(first)
+	want := []string{"", "Unknown", "C Major"}
+	got := []string{}
+	for change := range firstChanges {
+		got = append(got, changeName(change))
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %q on the first channel, got %q", want, got)
+	}
+	// the second listener keeps receiving changes once the first is closed
+	r.NoteOff(0, midi.KeyInt("G", 3), now)
+	close(second)
+	got = []string{}
+	for change := range secondChanges {
+		got = append(got, changeName(change))
+	}
+	if want = append(want, "Unknown"); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %q on the second channel, got %q", want, got)
+	}
+}
+
+func TestRecognizer_Listen_slowConsumer(t *testing.T) {
+	r := NewRecognizer(0, nil)
+	events := make(chan *midi.Event)
+	changes := r.Listen(events)
+	// report more changes than a buffered channel would hold without reading
+	done := make(chan struct{})
+	go func() {
+		now := time.Now()
+		for i := 0; i < 50; i++ {
+			r.NoteOn(0, midi.KeyInt("C", 3), 100, now)
+			r.NoteOn(0, midi.KeyInt("E", 3), 100, now)
+			r.NoteOff(0, midi.KeyInt("E", 3), now)
+			r.NoteOff(0, midi.KeyInt("C", 3), now)
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("the recognizer blocked on the consumer")
+	}
+	close(events)
+	var count int
+	for range changes {
+		count++
+	}
+	if count != 200 {
+		t.Errorf("expected 200 changes, got %d", count)
+	}
+}
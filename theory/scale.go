@@ -15,16 +15,12 @@ type Scale struct {
 
 // OffsetForNote returns the offset to apply to an incoming note so it stays in
 // scale. This is used to keep input notes within the scale (by moving the note
-// to the closed lower note in scale).
+// to the closest lower note in scale). See Quantizer for other strategies.
 func (s *Scale) OffsetForNote(note int) int {
 	if s == nil {
 		return 0
 	}
-	index := (((note % 12) + 12) - (s.Root % 12)) % 12
-	if s.Def.InScale[index] {
-		return 0
-	}
-	return -1
+	return NewQuantizer(s, QuantizeDown).Offset(note)
 }
 
 // AdjustedNote "corrects" the input note to be in scale.
@@ -81,8 +77,9 @@ func (s *Scale) chordForRoot(note int, nbrNotesInChord uint) *Chord {
 		// unsupported scale
 		return chord
 	}
-	// find the position of the root in the scale
-	modKey := note % 12
+	// find the position of the root in the scale, InScale is relative to the
+	// tonic
+	modKey := mod12(note - s.Root)
 	inScaleNotes := s.Def.NotesInScale()
 	var idx int
 	for i := 0; i < len(inScaleNotes); i++ {
@@ -117,7 +114,7 @@ func (s *Scale) chordForRoot(note int, nbrNotesInChord uint) *Chord {
 }
 
 func (s *Scale) String() string {
-	return fmt.Sprintf("%s %s", midi.Notes[s.Root%12], s.Def.Name)
+	return fmt.Sprintf("%s %s", midi.Notes[mod12(s.Root)], s.Def.Name)
 }
 
 // Scales is a slice of scales
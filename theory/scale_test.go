@@ -169,6 +169,20 @@ func TestScale_TriadChordForRoot(t *testing.T) {
 			want:          &Chord{Keys: []int{60, 63, 67}},
 			wantChordName: "C Minor",
 		},
+		{
+			name:          "E3 in D Major",
+			scale:         &Scale{Root: midi.KeyInt("D", 3) % 12, Def: ScaleDefMap[MajorScale]},
+			inputNote:     midi.KeyInt("E", 3),
+			want:          &Chord{Keys: []int{64, 67, 71}},
+			wantChordName: "E Minor",
+		},
+		{
+			name:          "D3 in B Minor",
+			scale:         &Scale{Root: midi.KeyInt("B", 3) % 12, Def: ScaleDefMap[NaturalMinorScale]},
+			inputNote:     midi.KeyInt("D", 3),
+			want:          &Chord{Keys: []int{62, 66, 69}},
+			wantChordName: "D Major",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
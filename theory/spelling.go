@@ -0,0 +1,326 @@
+package theory
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/go-audio/midi"
+)
+
+// Spelling indicates how altered notes should be named.
+type Spelling int
+
+const (
+	// SharpSpelling names altered notes using sharps (C#, D#...), this is the
+	// convention used by the midi package.
+	SharpSpelling Spelling = iota
+	// FlatSpelling names altered notes using flats (Db, Eb...).
+	FlatSpelling
+)
+
+// FlatNotes are the names of the notes using flats, see midi.Notes for the
+// sharp equivalent.
+var FlatNotes = []string{"C", "Db", "D", "Eb", "E", "F", "Gb", "G", "Ab", "A", "Bb", "B"}
+
+var letterNames = "CDEFGAB"
+
+// NoteName returns the name of the pitch class of the passed note using the
+// requested spelling.
+func NoteName(note int, spelling Spelling) string {
+	if spelling == FlatSpelling {
+		return FlatNotes[mod12(note)]
+	}
+	return midi.Notes[mod12(note)]
+}
+
+// NoteNameWithOctave returns the name and octave of the passed MIDI note using
+// the requested spelling (for instance Bb2). The octave numbering matches
+// midi.NoteToName.
+func NoteNameWithOctave(note int, spelling Spelling) string {
+	return NoteName(note, spelling) + strconv.Itoa(midi.NoteOctave(note))
+}
+
+// SpellingOf returns the spelling used by a note name such as Bb or C#.
+func SpellingOf(name string) Spelling {
+	if len(name) > 1 && strings.ContainsAny(name[1:], "b♭") {
+		return FlatSpelling
+	}
+	return SharpSpelling
+}
+
+// TransposeNoteName transposes a note name by the passed interval, keeping the
+// letter names consistent with the interval. For instance, transposing Bb by a
+// major second gives C and transposing E by an augmented fourth gives A#.
+// An empty string is returned if the passed name isn't a valid note name.
+func TransposeNoteName(name string, iv Interval) string {
+	letter, pc, ok := parseNoteName(name)
+	if !ok {
+		return ""
+	}
+	return spellNote(mod7(letter+iv.Steps), pc+iv.HalfSteps)
+}
+
+// Alteration returns the number of half steps a note name is altered by (-1
+// for Bb, 2 for Fx). 0 is returned for invalid names.
+func Alteration(name string) int {
+	_, alter, _ := noteAlteration(name)
+	return alter
+}
+
+// SpelledOctave returns the octave of a MIDI key spelled using the passed note
+// name, following the octave numbering of midi.NoteToName. The octave is the
+// one of the letter name: B#2 and C3 sound the same but are in different
+// octaves.
+func SpelledOctave(key int, name string) int {
+	return floorDiv(key-Alteration(name), 12) - 2
+}
+
+// noteAlteration returns the letter index and the number of half steps a note
+// name is altered by (-1 for Bb).
+func noteAlteration(name string) (letter, alter int, ok bool) {
+	letter, pc, ok := parseNoteName(name)
+	if !ok {
+		return 0, 0, false
+	}
+	return letter, mod12(pc-naturalHalfSteps[letter]+6) - 6, true
+}
+
+// parseNoteName returns the letter index (C = 0 to B = 6) and the pitch class
+// of a note name.
+func parseNoteName(name string) (letter, pc int, ok bool) {
+	if len(name) < 1 {
+		return 0, 0, false
+	}
+	letter = strings.IndexByte(letterNames, strings.ToUpper(name[:1])[0])
+	if letter < 0 {
+		return 0, 0, false
+	}
+	pc = naturalHalfSteps[letter]
+	for _, r := range name[1:] {
+		switch r {
+		case '#', '♯':
+			pc++
+		case 'b', '♭':
+			pc--
+		case 'x':
+			pc += 2
+		default:
+			return 0, 0, false
+		}
+	}
+	return letter, mod12(pc), true
+}
+
+// spellNote names the pitch class using the passed letter index and as many
+// accidentals as needed.
+func spellNote(letter, pc int) string {
+	diff := mod12(pc - naturalHalfSteps[letter])
+	if diff > 6 {
+		diff -= 12
+	}
+	name := letterNames[letter : letter+1]
+	if diff > 0 {
+		return name + strings.Repeat("#", diff)
+	}
+	return name + strings.Repeat("b", -diff)
+}
+
+// keyFifths returns the number of sharps (positive) or flats (negative) of the
+// major key built on the passed tonic. F#/Gb major is ambiguous and resolved
+// using the spelling hint.
+func keyFifths(tonic int, hint Spelling) int {
+	fifths := mod12(7 * tonic)
+	if fifths > 6 || (fifths == 6 && hint == FlatSpelling) {
+		fifths -= 12
+	}
+	return fifths
+}
+
+// spellingForFifths returns the spelling matching a key signature.
+func spellingForFifths(fifths int, hint Spelling) Spelling {
+	switch {
+	case fifths < 0:
+		return FlatSpelling
+	case fifths > 0:
+		return SharpSpelling
+	}
+	return hint
+}
+
+// scaleParentOffsets are the half steps between the tonic of a mode and the
+// tonic of the major scale sharing its key signature.
+var scaleParentOffsets = map[ScaleName]int{
+	MajorScale:           0,
+	MajorPentatonicScale: 0,
+	MajorBebopScale:      0,
+	LydianScale:          -5,
+	MixolydianScale:      -7,
+	DominantBebopScale:   -7,
+	DorianScale:          -2,
+	PhrygianScale:        -4,
+	LocrianScale:         -11,
+	NaturalMinorScale:    3,
+	HarmonicMinorScale:   3,
+	MelodicMinorScale:    3,
+	MinorPentatonicScale: 3,
+	BluesScale:           3,
+	HungarianMinorScale:  3,
+	NeapolitanMinorScale: 3,
+}
+
+// KeySignature returns the key signature of the scale expressed as a number of
+// sharps (positive) or flats (negative), -7 to 7. Modes use the key signature
+// of their parent major scale, scales without a traditional key signature use
+// the major or minor key signature of their tonic.
+func (s *Scale) KeySignature() int {
+	if s == nil {
+		return 0
+	}
+	return keyFifths(s.Root+s.parentOffset(), SharpSpelling)
+}
+
+// IsMinor reports whether the key signature of the scale is expressed as a
+// minor key (natural, harmonic or melodic minor, minor pentatonic...).
+func (s *Scale) IsMinor() bool {
+	return s != nil && s.parentOffset() == 3
+}
+
+// parentOffset returns the half steps between the tonic of the scale and the
+// tonic of the major scale sharing its key signature.
+func (s *Scale) parentOffset() int {
+	if offset, ok := scaleParentOffsets[s.Def.Name]; ok {
+		return offset
+	}
+	notes := s.Def.pitchClasses()
+	if intSliceIncludesOther(notes, []int{3}) && !intSliceIncludesOther(notes, []int{4}) {
+		return 3
+	}
+	return 0
+}
+
+// Spelling returns the spelling matching the key signature of the scale.
+func (s *Scale) Spelling() Spelling {
+	return spellingForFifths(s.KeySignature(), SharpSpelling)
+}
+
+// SpellKey returns the name of the key using the note names of the scale
+// (F# in D Major, Gb in Eb Minor). Keys out of the scale use the spelling of
+// its key signature, sharps are used if the scale is nil.
+func (s *Scale) SpellKey(key int) string {
+	if s == nil {
+		return NoteName(key, SharpSpelling)
+	}
+	names := s.NoteNames()
+	for i, n := range s.Notes() {
+		if mod12(n-key) == 0 {
+			return names[i]
+		}
+	}
+	return NoteName(key, s.Spelling())
+}
+
+// NoteNames returns the properly spelled names of the notes in the scale.
+// Heptatonic scales use each letter name once (F Major gives F G A Bb C D E),
+// other scales use the spelling of their key signature.
+func (s *Scale) NoteNames() []string {
+	notes := s.Notes()
+	names := make([]string, len(notes))
+	spelling := s.Spelling()
+	if len(notes) != 7 {
+		for i, n := range notes {
+			names[i] = NoteName(n, spelling)
+		}
+		return names
+	}
+	rootLetter, _, _ := parseNoteName(NoteName(s.Root, spelling))
+	for i, n := range notes {
+		names[i] = spellNote(mod7(rootLetter+i), n)
+	}
+	return names
+}
+
+// pitchClasses returns the pitch classes of the scale built on C, computed
+// from the half steps (InScale isn't set for all definitions).
+func (def ScaleDefinition) pitchClasses() []int {
+	pcs := []int{0}
+	var k int
+	for _, hs := range def.HalfSteps {
+		k += hs
+		pcs = append(pcs, mod12(k))
+	}
+	return pcs
+}
+
+func mod7(n int) int {
+	return ((n % 7) + 7) % 7
+}
+
+// NoteNames returns the properly spelled names of the chord keys, relative to
+// the root of the chord (Eb Major gives Eb G Bb, C7#9 gives C E G Bb D#).
+// Keys of unknown chords are named using the chord spelling.
+func (c *Chord) NoteNames() []string {
+	names := make([]string, len(c.Keys))
+	def := c.Copy().Def()
+	_, rootPC, ok := parseNoteName(def.Root)
+	if !ok {
+		for i, k := range c.Keys {
+			names[i] = NoteName(k, c.Spelling)
+		}
+		return names
+	}
+	offsets := []int{0}
+	for _, hs := range def.HalfSteps {
+		offsets = append(offsets, offsets[len(offsets)-1]+int(hs))
+	}
+	for i, k := range c.Keys {
+		names[i] = NoteName(k, c.Spelling)
+		for _, offset := range offsets {
+			if mod12(offset) != mod12(k-rootPC) {
+				continue
+			}
+			iv := ChordToneInterval(offset)
+			if def.Abbrev == "tri" && offset == 9 {
+				iv = DiminishedSeventh
+			}
+			names[i] = TransposeNoteName(def.Root, iv)
+			break
+		}
+	}
+	return names
+}
+
+// NewScaleFromKeySignature returns the scale of the passed mode using the key
+// signature expressed as a number of sharps (positive) or flats (negative).
+// For instance, -1 and NaturalMinorScale give D Natural Minor.
+func NewScaleFromKeySignature(fifths int, mode ScaleName) *Scale {
+	def, ok := ScaleDefMap[mode]
+	if !ok {
+		def = ScaleDefMap[MajorScale]
+	}
+	s := &Scale{Def: def}
+	s.Root = mod12(7*fifths - s.parentOffset())
+	return s
+}
+
+// NoteNameInterval returns the ascending interval, smaller than an octave,
+// between two note names (Bb to D is a major third, A# to D a diminished
+// fourth). False is returned if a note name isn't valid.
+func NoteNameInterval(from, to string) (Interval, bool) {
+	fromLetter, fromPC, ok := parseNoteName(from)
+	if !ok {
+		return Interval{}, false
+	}
+	toLetter, toPC, ok := parseNoteName(to)
+	if !ok {
+		return Interval{}, false
+	}
+	iv := Interval{Steps: mod7(toLetter - fromLetter), HalfSteps: mod12(toPC - fromPC)}
+	// C to B# is an augmented seventh and C to Cb a diminished unison
+	switch {
+	case iv.Steps == 6 && iv.HalfSteps < 6:
+		iv.HalfSteps += 12
+	case iv.Steps == 0 && iv.HalfSteps > 6:
+		iv.HalfSteps -= 12
+	}
+	return iv, true
+}
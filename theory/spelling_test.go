@@ -0,0 +1,170 @@
+package theory
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-audio/midi"
+)
+
+func TestTransposeNoteName(t *testing.T) {
+	tests := []struct {
+		name string
+		iv   Interval
+		want string
+	}{
+		{"C", MajorThird, "E"},
+		{"Bb", MajorSecond, "C"},
+		{"Bb", MinorThird, "Db"},
+		{"Bb", Interval{Steps: 2, HalfSteps: 1}, "Dbbb"},
+		{"E", AugmentedFourth, "A#"},
+		{"E", DiminishedFifth, "Bb"},
+		{"F#", MajorThird, "A#"},
+		{"C", MinorThird.Down(), "A"},
+		{"B", MajorThird, "D#"},
+		{"Eb", MajorSixth, "C"},
+		{"G#", MajorThird, "B#"},
+		{"H", MajorThird, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name+" "+tt.iv.Name(), func(t *testing.T) {
+			if got := TransposeNoteName(tt.name, tt.iv); got != tt.want {
+				t.Errorf("TransposeNoteName(%s, %s) = %v, want %v", tt.name, tt.iv, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScale_KeySignature(t *testing.T) {
+	tests := []struct {
+		name  string
+		scale *Scale
+		want  int
+		names []string
+	}{
+		{"C Major", &Scale{Root: 0, Def: ScaleDefMap[MajorScale]}, 0,
+			[]string{"C", "D", "E", "F", "G", "A", "B"}},
+		{"F Major", &Scale{Root: 5, Def: ScaleDefMap[MajorScale]}, -1,
+			[]string{"F", "G", "A", "Bb", "C", "D", "E"}},
+		{"B Major", &Scale{Root: 11, Def: ScaleDefMap[MajorScale]}, 5,
+			[]string{"B", "C#", "D#", "E", "F#", "G#", "A#"}},
+		{"C Minor", &Scale{Root: 60, Def: ScaleDefMap[NaturalMinorScale]}, -3,
+			[]string{"C", "D", "Eb", "F", "G", "Ab", "Bb"}},
+		{"D Dorian", &Scale{Root: 2, Def: ScaleDefMap[DorianScale]}, 0,
+			[]string{"D", "E", "F", "G", "A", "B", "C"}},
+		{"D Harmonic Minor", &Scale{Root: 2, Def: ScaleDefMap[HarmonicMinorScale]}, -1,
+			[]string{"D", "E", "F", "G", "A", "Bb", "C#"}},
+		{"F Minor Pentatonic", &Scale{Root: 5, Def: ScaleDefMap[MinorPentatonicScale]}, -4,
+			[]string{"F", "Ab", "Bb", "C", "Eb"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.scale.KeySignature(); got != tt.want {
+				t.Errorf("Scale.KeySignature() = %v, want %v", got, tt.want)
+			}
+			if got := tt.scale.NoteNames(); !reflect.DeepEqual(got, tt.names) {
+				t.Errorf("Scale.NoteNames() = %v, want %v", got, tt.names)
+			}
+		})
+	}
+}
+
+func TestNoteNameWithOctave(t *testing.T) {
+	if got := NoteNameWithOctave(58, FlatSpelling); got != "Bb2" {
+		t.Errorf("NoteNameWithOctave(58, FlatSpelling) = %s, want Bb2", got)
+	}
+	if got := NoteNameWithOctave(58, SharpSpelling); got != "A#2" {
+		t.Errorf("NoteNameWithOctave(58, SharpSpelling) = %s, want A#2", got)
+	}
+}
+
+func TestScale_SpellKey(t *testing.T) {
+	tests := []struct {
+		name  string
+		scale *Scale
+		key   int
+		want  string
+	}{
+		{"no scale", nil, midi.KeyInt("A#", 3), "A#"},
+		{"in D Major", &Scale{Root: 2, Def: ScaleDefMap[MajorScale]}, midi.KeyInt("F#", 3), "F#"},
+		{"in Bb Minor", &Scale{Root: 10, Def: ScaleDefMap[NaturalMinorScale]}, midi.KeyInt("C#", 3), "Db"},
+		{"in F# Major", &Scale{Root: 6, Def: ScaleDefMap[MajorScale]}, midi.KeyInt("F", 3), "E#"},
+		{"out of F Major", &Scale{Root: 5, Def: ScaleDefMap[MajorScale]}, midi.KeyInt("C#", 3), "Db"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.scale.SpellKey(tt.key); got != tt.want {
+				t.Errorf("Scale.SpellKey(%d) = %s, want %s", tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSpelledOctave(t *testing.T) {
+	tests := []struct {
+		key  int
+		name string
+		want int
+	}{
+		{midi.KeyInt("C", 3), "C", 3},
+		{midi.KeyInt("C", 3), "B#", 2},
+		{midi.KeyInt("B", 2), "Cb", 3},
+		{midi.KeyInt("A#", 2), "Bb", 2},
+	}
+	for _, tt := range tests {
+		if got := SpelledOctave(tt.key, tt.name); got != tt.want {
+			t.Errorf("SpelledOctave(%d, %s) = %d, want %d", tt.key, tt.name, got, tt.want)
+		}
+	}
+	if got := Alteration("Bbb"); got != -2 {
+		t.Errorf("Alteration(Bbb) = %d, want -2", got)
+	}
+}
+
+func TestChord_NoteNames(t *testing.T) {
+	tests := []struct {
+		name  string
+		chord *Chord
+		want  []string
+	}{
+		{"Eb Major", NewChordFromAbbrev("Ebmaj"), []string{"Eb", "G", "Bb"}},
+		{"F# Minor", NewChordFromAbbrev("F#min"), []string{"F#", "A", "C#"}},
+		{"C7#9", &Chord{Keys: []int{0, 4, 7, 10, 15}}, []string{"C", "E", "G", "Bb", "D#"}},
+		{"B7b5", &Chord{Keys: []int{11, 15, 17, 21}}, []string{"B", "D#", "F", "A"}},
+		{"C Diminished 7th", &Chord{Keys: []int{0, 3, 6, 9}}, []string{"C", "Eb", "Gb", "Bbb"}},
+		{"first inversion", &Chord{Keys: []int{4, 7, 12}}, []string{"E", "G", "C"}},
+		{"unknown", &Chord{Keys: []int{0, 1}, Spelling: FlatSpelling}, []string{"C", "Db"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.chord.NoteNames(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Chord.NoteNames() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewScaleFromKeySignature(t *testing.T) {
+	tests := []struct {
+		fifths int
+		mode   ScaleName
+		want   string
+	}{
+		{0, MajorScale, "C Major"},
+		{-1, NaturalMinorScale, "D Natural Minor"},
+		{3, MajorScale, "A Major"},
+		{0, DorianScale, "D Dorian"},
+		{-2, MixolydianScale, "F Mixolydian"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			s := NewScaleFromKeySignature(tt.fifths, tt.mode)
+			if got := s.String(); got != tt.want {
+				t.Errorf("NewScaleFromKeySignature(%d, %s) = %s, want %s", tt.fifths, tt.mode, got, tt.want)
+			}
+			if got := s.KeySignature(); got != tt.fifths {
+				t.Errorf("expected the key signature to be %d, got %d", tt.fifths, got)
+			}
+		})
+	}
+}
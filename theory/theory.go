@@ -80,3 +80,8 @@ func intSliceIncludesOther(a, b []int) bool {
 	}
 	return true
 }
+
+// mod12 returns the pitch class of a note, including for negative values.
+func mod12(n int) int {
+	return ((n % 12) + 12) % 12
+}
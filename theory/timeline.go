@@ -0,0 +1,280 @@
+package theory
+
+import (
+	"sort"
+)
+
+// DefaultBPM is the tempo used when a timeline doesn't define one (the MIDI
+// default).
+const DefaultBPM = 120.0
+
+const (
+	// SustainPedal is the MIDI controller number of the sustain (damper) pedal.
+	SustainPedal = 64
+	// DrumChannel is the (0 indexed) General MIDI percussion channel.
+	DrumChannel = 9
+)
+
+// NoteEvent is a note played at a given time. Times are expressed in ticks
+// (see Timeline.TicksPerQuarter).
+type NoteEvent struct {
+	// Key is the MIDI note value.
+	Key      int
+	Start    int
+	Duration int
+	// Velocity is the MIDI velocity (0-127).
+	Velocity int
+	// Channel is the MIDI channel (0-15).
+	Channel int
+}
+
+// End returns the tick at which the note stops.
+func (n NoteEvent) End() int {
+	return n.Start + n.Duration
+}
+
+// ChordSpan is a chord sounding for a period of time (in ticks).
+type ChordSpan struct {
+	Chord    *Chord
+	Start    int
+	Duration int
+}
+
+// End returns the tick at which the chord stops.
+func (cs ChordSpan) End() int {
+	return cs.Start + cs.Duration
+}
+
+// ChordSpans is a sequence of timed chords.
+type ChordSpans []ChordSpan
+
+// Chords returns the chords of the spans so they can be analyzed like any
+// other chord progression.
+func (spans ChordSpans) Chords() Chords {
+	chords := make(Chords, len(spans))
+	for i, span := range spans {
+		chords[i] = span.Chord
+	}
+	return chords
+}
+
+// TempoChange sets the tempo from a given tick.
+type TempoChange struct {
+	Tick int
+	BPM  float64
+}
+
+// TempoMap is a list of tempo changes sorted by tick.
+type TempoMap []TempoChange
+
+// BPMAt returns the tempo at the passed tick.
+func (tm TempoMap) BPMAt(tick int) float64 {
+	bpm := DefaultBPM
+	for _, tc := range tm {
+		if tc.Tick > tick {
+			break
+		}
+		bpm = tc.BPM
+	}
+	return bpm
+}
+
+// Timeline is a performance: notes with their timing, the resolution used to
+// express time and the tempo changes.
+type Timeline struct {
+	// TicksPerQuarter is the number of ticks in a quarter note (PPQN).
+	TicksPerQuarter int
+	Tempo           TempoMap
+	Notes           []NoteEvent
+}
+
+// NewTimeline returns an empty timeline using the passed resolution, 96 ticks
+// per quarter note are used if the resolution isn't valid.
+func NewTimeline(ticksPerQuarter int) *Timeline {
+	if ticksPerQuarter <= 0 {
+		ticksPerQuarter = 96
+	}
+	return &Timeline{TicksPerQuarter: ticksPerQuarter}
+}
+
+// Add adds notes to the timeline.
+func (tl *Timeline) Add(notes ...NoteEvent) {
+	tl.Notes = append(tl.Notes, notes...)
+}
+
+// AddChord adds all the keys of the chord starting at the same time.
+func (tl *Timeline) AddChord(c *Chord, start, duration, velocity int) {
+	if c == nil {
+		return
+	}
+	for _, k := range c.Keys {
+		tl.Add(NoteEvent{Key: k, Start: start, Duration: duration, Velocity: velocity})
+	}
+}
+
+// SetTempo sets the tempo from the passed tick, replacing any tempo change
+// already set at the same tick.
+func (tl *Timeline) SetTempo(tick int, bpm float64) {
+	for i, tc := range tl.Tempo {
+		if tc.Tick == tick {
+			tl.Tempo[i].BPM = bpm
+			return
+		}
+	}
+	tl.Tempo = append(tl.Tempo, TempoChange{Tick: tick, BPM: bpm})
+	sort.Slice(tl.Tempo, func(i, j int) bool { return tl.Tempo[i].Tick < tl.Tempo[j].Tick })
+}
+
+// Sort sorts the notes by start time, then by key.
+func (tl *Timeline) Sort() {
+	sort.SliceStable(tl.Notes, func(i, j int) bool {
+		if tl.Notes[i].Start != tl.Notes[j].Start {
+			return tl.Notes[i].Start < tl.Notes[j].Start
+		}
+		return tl.Notes[i].Key < tl.Notes[j].Key
+	})
+}
+
+// End returns the tick at which the last note stops.
+func (tl *Timeline) End() int {
+	var end int
+	for _, n := range tl.Notes {
+		if n.End() > end {
+			end = n.End()
+		}
+	}
+	return end
+}
+
+// Beats converts ticks into quarter notes.
+func (tl *Timeline) Beats(tick int) float64 {
+	return float64(tick) / float64(tl.ticksPerQuarter())
+}
+
+// Seconds converts a tick position into seconds using the tempo map.
+func (tl *Timeline) Seconds(tick int) float64 {
+	var seconds float64
+	lastTick, bpm := 0, DefaultBPM
+	for _, tc := range tl.Tempo {
+		if tc.Tick >= tick {
+			break
+		}
+		seconds += tl.Beats(tc.Tick-lastTick) * 60 / bpm
+		lastTick, bpm = tc.Tick, tc.BPM
+	}
+	return seconds + tl.Beats(tick-lastTick)*60/bpm
+}
+
+// Keys returns the keys of all the notes in the timeline.
+func (tl *Timeline) Keys() []int {
+	keys := make([]int, len(tl.Notes))
+	for i, n := range tl.Notes {
+		keys[i] = n.Key
+	}
+	return keys
+}
+
+// NotesAt returns the notes sounding at the passed tick.
+func (tl *Timeline) NotesAt(tick int) []NoteEvent {
+	notes := []NoteEvent{}
+	for _, n := range tl.Notes {
+		if n.Start <= tick && tick < n.End() {
+			notes = append(notes, n)
+		}
+	}
+	return notes
+}
+
+// ChordSpans segments the timeline in chords. A new span starts every time the
+// set of sounding keys changes and at least 2 different pitch classes are
+// sounding. The keys of each chord are sorted from the lowest to the highest.
+func (tl *Timeline) ChordSpans() ChordSpans {
+	boundaries := []int{}
+	seen := map[int]bool{}
+	for _, n := range tl.Notes {
+		if n.Duration <= 0 {
+			continue
+		}
+		for _, b := range []int{n.Start, n.End()} {
+			if !seen[b] {
+				seen[b] = true
+				boundaries = append(boundaries, b)
+			}
+		}
+	}
+	sort.Ints(boundaries)
+
+	spans := ChordSpans{}
+	var lastKeys []int
+	for i := 0; i+1 < len(boundaries); i++ {
+		start, end := boundaries[i], boundaries[i+1]
+		keys := soundingKeys(tl.Notes, start)
+		if countPitchClasses(keys) < 2 {
+			lastKeys = nil
+			continue
+		}
+		if lastKeys != nil && intsEqual(keys, lastKeys) && spans[len(spans)-1].End() == start {
+			spans[len(spans)-1].Duration = end - spans[len(spans)-1].Start
+			continue
+		}
+		spans = append(spans, ChordSpan{Chord: &Chord{Keys: keys}, Start: start, Duration: end - start})
+		lastKeys = keys
+	}
+	return spans
+}
+
+// Chords returns the chord progression played in the timeline (see
+// ChordSpans).
+func (tl *Timeline) Chords() Chords {
+	return tl.ChordSpans().Chords()
+}
+
+// EligibleScales returns the scales matching the notes played in the timeline.
+func (tl *Timeline) EligibleScales() Scales {
+	return EligibleScalesForNotes(tl.Keys())
+}
+
+func (tl *Timeline) ticksPerQuarter() int {
+	if tl.TicksPerQuarter <= 0 {
+		return 96
+	}
+	return tl.TicksPerQuarter
+}
+
+// soundingKeys returns the sorted unique keys sounding at the passed tick.
+func soundingKeys(notes []NoteEvent, tick int) []int {
+	uKeys := map[int]bool{}
+	keys := []int{}
+	for _, n := range notes {
+		if n.Start <= tick && tick < n.End() && !uKeys[n.Key] {
+			uKeys[n.Key] = true
+			keys = append(keys, n.Key)
+		}
+	}
+	sort.Ints(keys)
+	return keys
+}
+
+func countPitchClasses(keys []int) int {
+	var pcs [12]bool
+	var count int
+	for _, k := range keys {
+		if !pcs[mod12(k)] {
+			pcs[mod12(k)] = true
+			count++
+		}
+	}
+	return count
+}
+
+func intsEqual(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
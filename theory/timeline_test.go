@@ -0,0 +1,76 @@
+package theory
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-audio/midi"
+)
+
+func TestTimeline_ChordSpans(t *testing.T) {
+	tl := NewTimeline(96)
+	// Bmin for a bar, Dmaj for a bar with a melody note on top, F#min
+	tl.AddChord(NewChordFromAbbrev("Bmin").Transpose(36), 0, 384, 100)
+	tl.AddChord(NewChordFromAbbrev("Dmaj").Transpose(36), 384, 384, 100)
+	tl.Add(NoteEvent{Key: midi.KeyInt("A", 4), Start: 576, Duration: 192, Velocity: 90})
+	// single note, not a chord
+	tl.Add(NoteEvent{Key: midi.KeyInt("C", 3), Start: 768, Duration: 96})
+	tl.AddChord(NewChordFromAbbrev("F#min").Transpose(36), 864, 384, 100)
+
+	spans := tl.ChordSpans()
+	want := []struct {
+		name            string
+		start, duration int
+	}{
+		{"B Minor", 0, 384},
+		{"D Major", 384, 192},
+		{"D Major", 576, 192},
+		{"F# Minor", 864, 384},
+	}
+	if len(spans) != len(want) {
+		t.Fatalf("expected %d spans, got %d: %v", len(want), len(spans), spans.Chords())
+	}
+	for i, w := range want {
+		if got := spans[i].Chord.Def().String(); got != w.name {
+			t.Errorf("span %d: expected %s, got %s", i, w.name, got)
+		}
+		if spans[i].Start != w.start || spans[i].Duration != w.duration {
+			t.Errorf("span %d: expected %d+%d, got %d+%d", i, w.start, w.duration, spans[i].Start, spans[i].Duration)
+		}
+	}
+	if got := tl.Chords().String(); got != "Bmin,Dmaj,Dmaj,F#min" {
+		t.Errorf("Timeline.Chords() = %s", got)
+	}
+	if got := tl.End(); got != 1248 {
+		t.Errorf("Timeline.End() = %d, want 1248", got)
+	}
+	if got := len(tl.NotesAt(600)); got != 4 {
+		t.Errorf("expected 4 notes sounding at tick 600, got %d", got)
+	}
+}
+
+func TestTimeline_Seconds(t *testing.T) {
+	tl := NewTimeline(480)
+	if got := tl.Seconds(960); got != 1 {
+		t.Errorf("expected 2 beats at the default tempo to last 1s, got %f", got)
+	}
+	tl.SetTempo(0, 60)
+	tl.SetTempo(960, 120)
+	tests := []struct {
+		tick int
+		want float64
+	}{
+		{0, 0},
+		{480, 1},
+		{960, 2},
+		{1440, 2.5},
+	}
+	for _, tt := range tests {
+		if got := tl.Seconds(tt.tick); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("Timeline.Seconds(%d) = %f, want %f", tt.tick, got, tt.want)
+		}
+	}
+	if got := tl.Tempo.BPMAt(1000); got != 120 {
+		t.Errorf("TempoMap.BPMAt(1000) = %f, want 120", got)
+	}
+}
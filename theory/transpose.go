@@ -0,0 +1,143 @@
+package theory
+
+import "strings"
+
+// Transpose returns a copy of the chord transposed by the passed number of
+// half steps (negative values transpose down). The spelling of the new chord
+// is picked based on the key signature of its root so that, for instance,
+// transposing C major up 10 half steps gives Bb major and not A# major.
+func (c *Chord) Transpose(halfSteps int) *Chord {
+	if c == nil {
+		return nil
+	}
+	transposed := c.transposedKeys(halfSteps)
+	transposed.Spelling = chordSpelling(transposed, c.Spelling)
+	return transposed
+}
+
+// TransposeInterval returns a copy of the chord transposed by the passed
+// interval. The spelling follows the interval: transposing E by a diminished
+// fifth gives Bb while transposing it by an augmented fourth gives A#.
+func (c *Chord) TransposeInterval(iv Interval) *Chord {
+	if c == nil {
+		return nil
+	}
+	transposed := c.transposedKeys(iv.HalfSteps)
+	rootName := c.Copy().Def().Root
+	if rootName == "" {
+		transposed.Spelling = chordSpelling(transposed, c.Spelling)
+		return transposed
+	}
+	newRoot := TransposeNoteName(rootName, iv)
+	switch {
+	case strings.Contains(newRoot, "b"):
+		transposed.Spelling = FlatSpelling
+	case strings.Contains(newRoot, "#"):
+		transposed.Spelling = SharpSpelling
+	default:
+		transposed.Spelling = chordSpelling(transposed, c.Spelling)
+	}
+	return transposed
+}
+
+// Copy returns a copy of the chord. Note that calling Def() on a chord might
+// re-order its keys, working on a copy avoids altering the original voicing.
+func (c *Chord) Copy() *Chord {
+	if c == nil {
+		return nil
+	}
+	return c.transposedKeys(0)
+}
+
+func (c *Chord) transposedKeys(halfSteps int) *Chord {
+	keys := make([]int, len(c.Keys))
+	for i, k := range c.Keys {
+		keys[i] = k + halfSteps
+	}
+	return &Chord{Keys: keys, Spelling: c.Spelling}
+}
+
+// chordSpelling returns the spelling with the fewest accidentals for the key
+// implied by the chord (major or minor based on its definition).
+func chordSpelling(c *Chord, hint Spelling) Spelling {
+	def := c.Copy().Def()
+	root := def.RootInt()
+	if root < 0 {
+		return hint
+	}
+	if isMinorAbbrev(def.Abbrev) {
+		// use the key signature of the relative major
+		root += 3
+	}
+	return spellingForFifths(keyFifths(root, hint), hint)
+}
+
+// KeySpelling returns the spelling of the key implied by the chord: flats for
+// Ab or Bbm, sharps for G#m. The spelling of the chord is returned if it isn't
+// identified and is used to resolve F#/Gb.
+func (c *Chord) KeySpelling() Spelling {
+	return chordSpelling(c, c.Spelling)
+}
+
+func isMinorAbbrev(abbrev string) bool {
+	return strings.HasPrefix(abbrev, "m") && !strings.HasPrefix(abbrev, "maj")
+}
+
+// Transpose returns a copy of the chords transposed by the passed number of
+// half steps.
+func (chords Chords) Transpose(halfSteps int) Chords {
+	out := make(Chords, len(chords))
+	for i, c := range chords {
+		out[i] = c.Transpose(halfSteps)
+	}
+	return out
+}
+
+// TransposeInterval returns a copy of the chords transposed by the passed
+// interval.
+func (chords Chords) TransposeInterval(iv Interval) Chords {
+	out := make(Chords, len(chords))
+	for i, c := range chords {
+		out[i] = c.TransposeInterval(iv)
+	}
+	return out
+}
+
+// TransposeToKey returns a copy of the chord progression written in the "from"
+// key transposed to the "to" key. The shortest direction is used (up to 6
+// half steps up or 5 half steps down) and the chords are spelled using the
+// key signature of the target key.
+func (chords Chords) TransposeToKey(from, to *Scale) Chords {
+	if from == nil || to == nil {
+		return chords.Transpose(0)
+	}
+	halfSteps := mod12(to.Root - from.Root)
+	if halfSteps > 6 {
+		halfSteps -= 12
+	}
+	spelling := to.Spelling()
+	out := make(Chords, len(chords))
+	for i, c := range chords {
+		if c == nil {
+			continue
+		}
+		out[i] = c.transposedKeys(halfSteps)
+		out[i].Spelling = spelling
+	}
+	return out
+}
+
+// Transpose returns a copy of the scale transposed by the passed number of half
+// steps. The root of the returned scale is a note number (0-11).
+func (s *Scale) Transpose(halfSteps int) *Scale {
+	if s == nil {
+		return nil
+	}
+	return &Scale{Root: mod12(s.Root + halfSteps), Def: s.Def}
+}
+
+// TransposeInterval returns a copy of the scale transposed by the passed
+// interval.
+func (s *Scale) TransposeInterval(iv Interval) *Scale {
+	return s.Transpose(iv.HalfSteps)
+}
@@ -0,0 +1,127 @@
+package theory
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-audio/midi"
+)
+
+func TestChord_Transpose(t *testing.T) {
+	tests := []struct {
+		name      string
+		chord     string
+		halfSteps int
+		want      string
+	}{
+		{"C up a whole step", "Cmaj", 2, "Dmaj"},
+		{"C to Bb", "Cmaj", 10, "Bbmaj"},
+		{"Bb to C", "Bbmaj", 2, "Cmaj"},
+		{"Bb to Eb", "Bbmaj", 5, "Ebmaj"},
+		{"minor chords use the relative major key", "Amin", -1, "G#min"},
+		{"C#m7 down", "C#m7", -3, "Bbm7"},
+		{"F# is kept sharp", "Emaj", 2, "F#maj"},
+		{"Gb is kept flat", "Abmaj", -2, "Gbmaj"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewChordFromAbbrev(tt.chord)
+			if c == nil {
+				t.Fatalf("unknown chord %s", tt.chord)
+			}
+			got := c.Transpose(tt.halfSteps)
+			if name := got.AbbrevName(); name != tt.want {
+				t.Errorf("Chord.Transpose(%d) = %v, want %v", tt.halfSteps, name, tt.want)
+			}
+			for i, k := range got.Keys {
+				if k-c.Keys[i] != tt.halfSteps {
+					t.Errorf("key %d wasn't transposed: %d -> %d", i, c.Keys[i], k)
+				}
+			}
+		})
+	}
+}
+
+func TestChord_TransposeInterval(t *testing.T) {
+	c := NewChordFromAbbrev("Bbmaj")
+	if got := c.TransposeInterval(MajorSecond).AbbrevName(); got != "Cmaj" {
+		t.Errorf("Bbmaj up a major second = %s, want Cmaj", got)
+	}
+	if got := c.TransposeInterval(MinorSecond).AbbrevName(); got != "Bmaj" {
+		t.Errorf("Bbmaj up a minor second = %s, want Bmaj", got)
+	}
+	if got := NewChordFromAbbrev("Emaj").TransposeInterval(DiminishedFifth).AbbrevName(); got != "Bbmaj" {
+		t.Errorf("Emaj up a diminished fifth = %s, want Bbmaj", got)
+	}
+	if got := NewChordFromAbbrev("Emaj").TransposeInterval(AugmentedFourth).AbbrevName(); got != "A#maj" {
+		t.Errorf("Emaj up an augmented fourth = %s, want A#maj", got)
+	}
+}
+
+func TestChord_Transpose_keepsVoicing(t *testing.T) {
+	c := &Chord{Keys: []int{
+		midi.KeyInt("E", 2),
+		midi.KeyInt("G", 2),
+		midi.KeyInt("C", 3),
+	}}
+	got := c.Transpose(-2)
+	want := []int{midi.KeyInt("D", 2), midi.KeyInt("F", 2), midi.KeyInt("A#", 2)}
+	if !reflect.DeepEqual(got.Keys, want) {
+		t.Errorf("Chord.Transpose(-2) keys = %v, want %v", keyNames(got.Keys), keyNames(want))
+	}
+	if !reflect.DeepEqual(c.Keys, []int{midi.KeyInt("E", 2), midi.KeyInt("G", 2), midi.KeyInt("C", 3)}) {
+		t.Errorf("the original chord was modified: %v", keyNames(c.Keys))
+	}
+	if got.String() != `Bb Major - "Bb2, D2, F2"` {
+		t.Errorf("unexpected transposed chord %s", got)
+	}
+}
+
+func TestChords_TransposeToKey(t *testing.T) {
+	progression := Chords{
+		NewChordFromAbbrev("Cmaj"),
+		NewChordFromAbbrev("Amin"),
+		NewChordFromAbbrev("Fmaj"),
+		NewChordFromAbbrev("G7"),
+	}
+	from := &Scale{Root: 0, Def: ScaleDefMap[MajorScale]}
+	tests := []struct {
+		name string
+		to   *Scale
+		want string
+	}{
+		{"F major", &Scale{Root: 5, Def: ScaleDefMap[MajorScale]}, "Fmaj,Dmin,Bbmaj,C7"},
+		{"Eb major", &Scale{Root: 3, Def: ScaleDefMap[MajorScale]}, "Ebmaj,Cmin,Abmaj,Bb7"},
+		{"E major", &Scale{Root: 4, Def: ScaleDefMap[MajorScale]}, "Emaj,C#min,Amaj,B7"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := progression.TransposeToKey(from, tt.to).String(); got != tt.want {
+				t.Errorf("Chords.TransposeToKey() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if got := progression.Transpose(2).String(); got != "Dmaj,Bmin,Gmaj,A7" {
+		t.Errorf("Chords.Transpose(2) = %v", got)
+	}
+}
+
+func TestScale_Transpose(t *testing.T) {
+	s := &Scale{Root: 0, Def: ScaleDefMap[MajorScale]}
+	got := s.Transpose(-2)
+	if got.String() != "A# Major" || got.KeySignature() != -2 {
+		t.Errorf("Scale.Transpose(-2) = %s (%d)", got, got.KeySignature())
+	}
+	if got.Root != 10 {
+		t.Errorf("Scale.Transpose(-2).Root = %d, expected 10", got.Root)
+	}
+	if notes := got.Notes(); !reflect.DeepEqual(notes, []int{10, 0, 2, 3, 5, 7, 9}) {
+		t.Errorf("Scale.Transpose(-2).Notes() = %v", notes)
+	}
+	if got := s.TransposeInterval(MajorSecond.Down()).NoteNames(); !reflect.DeepEqual(got, []string{"Bb", "C", "D", "Eb", "F", "G", "A"}) {
+		t.Errorf("Scale.TransposeInterval(MajorSecond.Down()).NoteNames() = %v", got)
+	}
+	if got := s.TransposeInterval(PerfectFifth).NoteNames(); !reflect.DeepEqual(got, []string{"G", "A", "B", "C", "D", "E", "F#"}) {
+		t.Errorf("Scale.TransposeInterval(PerfectFifth).NoteNames() = %v", got)
+	}
+}
@@ -0,0 +1,172 @@
+package theory
+
+import "sort"
+
+// Voicing returns the keys to play for a chord. prev is the voicing used for
+// the previous chord (nil for the first chord) so strategies can keep the
+// voices moving smoothly.
+type Voicing func(c *Chord, prev []int) []int
+
+// AsPlayedVoicing keeps the keys of the chord as they are.
+func AsPlayedVoicing(c *Chord, prev []int) []int {
+	if c == nil {
+		return nil
+	}
+	keys := make([]int, len(c.Keys))
+	copy(keys, c.Keys)
+	return keys
+}
+
+// CloseVoicing returns a voicing stacking the chord tones in root position
+// from the root found in the passed MIDI octave (see midi.KeyInt).
+func CloseVoicing(octave int) Voicing {
+	return func(c *Chord, prev []int) []int {
+		return closeKeys(c, (octave+2)*12)
+	}
+}
+
+// DropTwoVoicing returns a voicing where the second highest note of the close
+// voicing is dropped an octave, a common voicing for four note chords.
+func DropTwoVoicing(octave int) Voicing {
+	return func(c *Chord, prev []int) []int {
+		keys := closeKeys(c, (octave+2)*12)
+		if len(keys) < 4 {
+			return keys
+		}
+		keys[len(keys)-2] -= 12
+		sort.Ints(keys)
+		return keys
+	}
+}
+
+// SmoothVoicing returns a voicing picking the inversion of the close voicing
+// moving the least from the previous chord. The first chord is played in root
+// position from the passed MIDI octave.
+func SmoothVoicing(octave int) Voicing {
+	return func(c *Chord, prev []int) []int {
+		keys := closeKeys(c, (octave+2)*12)
+		if len(prev) == 0 || len(keys) == 0 {
+			return keys
+		}
+		var best []int
+		bestCost := -1
+		inversion := append([]int{}, keys...)
+		for i := 0; i < len(keys); i++ {
+			for shift := -24; shift <= 24; shift += 12 {
+				candidate := make([]int, len(inversion))
+				for j, k := range inversion {
+					candidate[j] = k + shift
+				}
+				if cost := voiceLeadingCost(prev, candidate); bestCost < 0 || cost < bestCost {
+					best, bestCost = candidate, cost
+				}
+			}
+			// next inversion
+			inversion = append(inversion[1:], inversion[0]+12)
+		}
+		return best
+	}
+}
+
+// closeKeys returns the chord tones stacked from the bass (the lowest key of
+// the chord) moved to the octave starting at base.
+func closeKeys(c *Chord, base int) []int {
+	if c == nil || len(c.Keys) == 0 {
+		return nil
+	}
+	def := c.Copy().Def()
+	root := def.RootInt()
+	if root < 0 {
+		keys := make([]int, len(c.Keys))
+		copy(keys, c.Keys)
+		sort.Ints(keys)
+		shift := base + mod12(keys[0]) - keys[0]
+		for i := range keys {
+			keys[i] += shift
+		}
+		return keys
+	}
+	keys := []int{base + root}
+	for _, hs := range def.HalfSteps {
+		keys = append(keys, keys[len(keys)-1]+int(hs))
+	}
+	// keep the bass of inversions and slash chords: the tones below it are
+	// moved up an octave.
+	lowest := c.Keys[0]
+	for _, k := range c.Keys {
+		if k < lowest {
+			lowest = k
+		}
+	}
+	bass := mod12(lowest)
+	for i, k := range keys {
+		if i == 0 || mod12(k) != bass {
+			continue
+		}
+		for j := 0; j < i; j++ {
+			keys[j] += 12
+		}
+		keys = append(keys[i:], keys[:i]...)
+		shift := base + bass - keys[0]
+		for j := range keys {
+			keys[j] += shift
+		}
+		break
+	}
+	return keys
+}
+
+// voiceLeadingCost is the total distance, in half steps, each note of the
+// candidate has to travel from the closest note of the previous voicing.
+func voiceLeadingCost(prev, candidate []int) int {
+	var cost int
+	for _, k := range candidate {
+		best := -1
+		for _, p := range prev {
+			d := k - p
+			if d < 0 {
+				d = -d
+			}
+			if best < 0 || d < best {
+				best = d
+			}
+		}
+		cost += best
+	}
+	return cost
+}
+
+// NewChordSpans returns a timed chord sequence where each chord lasts the
+// passed number of ticks. If fewer durations than chords are passed, the
+// durations are repeated.
+func NewChordSpans(chords Chords, durations ...int) ChordSpans {
+	spans := make(ChordSpans, 0, len(chords))
+	if len(durations) == 0 {
+		return spans
+	}
+	var start int
+	for i, c := range chords {
+		d := durations[i%len(durations)]
+		spans = append(spans, ChordSpan{Chord: c, Start: start, Duration: d})
+		start += d
+	}
+	return spans
+}
+
+// Notes returns the note events to play the chord spans using the passed
+// voicing strategy (AsPlayedVoicing if nil).
+func (spans ChordSpans) Notes(voicing Voicing, velocity int) []NoteEvent {
+	if voicing == nil {
+		voicing = AsPlayedVoicing
+	}
+	notes := []NoteEvent{}
+	var prev []int
+	for _, span := range spans {
+		keys := voicing(span.Chord, prev)
+		for _, k := range keys {
+			notes = append(notes, NoteEvent{Key: k, Start: span.Start, Duration: span.Duration, Velocity: velocity})
+		}
+		prev = keys
+	}
+	return notes
+}
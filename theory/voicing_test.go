@@ -0,0 +1,105 @@
+package theory
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-audio/midi"
+)
+
+func TestVoicings(t *testing.T) {
+	c7 := &Chord{Keys: []int{
+		midi.KeyInt("C", 2),
+		midi.KeyInt("A#", 2),
+		midi.KeyInt("E", 3),
+		midi.KeyInt("G", 3),
+	}}
+	tests := []struct {
+		name    string
+		voicing Voicing
+		prev    []int
+		want    []int
+	}{
+		{"as played", AsPlayedVoicing, nil, c7.Keys},
+		{"close", CloseVoicing(3), nil, []int{
+			midi.KeyInt("C", 3), midi.KeyInt("E", 3), midi.KeyInt("G", 3), midi.KeyInt("A#", 3),
+		}},
+		{"drop 2", DropTwoVoicing(3), nil, []int{
+			midi.KeyInt("G", 2), midi.KeyInt("C", 3), midi.KeyInt("E", 3), midi.KeyInt("A#", 3),
+		}},
+		{"smooth without previous chord", SmoothVoicing(3), nil, []int{
+			midi.KeyInt("C", 3), midi.KeyInt("E", 3), midi.KeyInt("G", 3), midi.KeyInt("A#", 3),
+		}},
+		{"smooth from F major", SmoothVoicing(3),
+			[]int{midi.KeyInt("F", 3), midi.KeyInt("A", 3), midi.KeyInt("C", 4)},
+			[]int{midi.KeyInt("E", 3), midi.KeyInt("G", 3), midi.KeyInt("A#", 3), midi.KeyInt("C", 4)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.voicing(c7, tt.prev); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("voicing = %v, want %v", keyNames(got), keyNames(tt.want))
+			}
+		})
+	}
+}
+
+func TestVoicings_slashChord(t *testing.T) {
+	cOverE := &Chord{Keys: []int{
+		midi.KeyInt("E", 2),
+		midi.KeyInt("C", 3),
+		midi.KeyInt("G", 3),
+	}}
+	tests := []struct {
+		name    string
+		voicing Voicing
+		want    []int
+	}{
+		{"close", CloseVoicing(3), []int{
+			midi.KeyInt("E", 3), midi.KeyInt("G", 3), midi.KeyInt("C", 4),
+		}},
+		{"smooth without previous chord", SmoothVoicing(3), []int{
+			midi.KeyInt("E", 3), midi.KeyInt("G", 3), midi.KeyInt("C", 4),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.voicing(cOverE, nil)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("voicing = %v, want %v", keyNames(got), keyNames(tt.want))
+			}
+			if symbol := (&Chord{Keys: got}).Symbol(); symbol != "C/E" {
+				t.Errorf("expected the voicing to be C/E, got %s", symbol)
+			}
+		})
+	}
+}
+
+func TestChordSpans_Notes(t *testing.T) {
+	spans := NewChordSpans(Chords{NewChordFromAbbrev("Cmaj"), NewChordFromAbbrev("Fmaj"), NewChordFromAbbrev("G7")}, 384, 192)
+	if spans[2].Start != 576 || spans[2].Duration != 384 {
+		t.Errorf("unexpected span timing %d+%d", spans[2].Start, spans[2].Duration)
+	}
+	notes := spans.Notes(SmoothVoicing(3), 100)
+	if len(notes) != 10 {
+		t.Fatalf("expected 10 notes, got %d", len(notes))
+	}
+	tl := NewTimeline(96)
+	tl.Add(notes...)
+	if got := tl.Chords().String(); got != "Cmaj,Fmaj,G7" {
+		t.Errorf("expected the voiced chords to be Cmaj,Fmaj,G7, got %s", got)
+	}
+}
+
+func TestScale_Run(t *testing.T) {
+	dMinor := &Scale{Root: 2, Def: ScaleDefMap[NaturalMinorScale]}
+	want := []int{
+		midi.KeyInt("D", 3), midi.KeyInt("C", 3), midi.KeyInt("A#", 2), midi.KeyInt("A", 2),
+	}
+	if got := dMinor.Run(midi.KeyInt("D", 3), -3); !reflect.DeepEqual(got, want) {
+		t.Errorf("Scale.Run() = %v, want %v", keyNames(got), keyNames(want))
+	}
+	if got := len(dMinor.Run(midi.KeyInt("D", 3), 7)); got != 8 {
+		t.Errorf("expected an octave run to have 8 notes, got %d", got)
+	}
+}
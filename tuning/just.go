@@ -0,0 +1,195 @@
+package tuning
+
+import (
+	"math"
+	"strings"
+
+	"github.com/go-audio/midi"
+	"github.com/go-audio/music/theory"
+)
+
+// Limit is the highest prime number used by the ratios of a just intonation
+// system.
+type Limit int
+
+const (
+	// FiveLimit tunes the chord tones using ratios of 2, 3 and 5 (5/4 for a
+	// major third, 9/5 for a minor seventh).
+	FiveLimit Limit = 5
+	// SevenLimit also uses 7, the harmonic seventh (7/4) is used for the
+	// seventh of dominant chords and 7/5 for diminished fifths.
+	SevenLimit Limit = 7
+)
+
+// fiveLimitRatios are the ratios of the simple intervals, indexed by steps and
+// half steps.
+var fiveLimitRatios = map[theory.Interval]Pitch{
+	theory.PerfectUnison:     Ratio(1, 1),
+	theory.MinorSecond:       Ratio(16, 15),
+	theory.MajorSecond:       Ratio(9, 8),
+	theory.AugmentedSecond:   Ratio(75, 64),
+	theory.MinorThird:        Ratio(6, 5),
+	theory.MajorThird:        Ratio(5, 4),
+	theory.PerfectFourth:     Ratio(4, 3),
+	theory.AugmentedFourth:   Ratio(45, 32),
+	theory.DiminishedFifth:   Ratio(64, 45),
+	theory.PerfectFifth:      Ratio(3, 2),
+	theory.AugmentedFifth:    Ratio(25, 16),
+	theory.MinorSixth:        Ratio(8, 5),
+	theory.MajorSixth:        Ratio(5, 3),
+	theory.DiminishedSeventh: Ratio(128, 75),
+	theory.MinorSeventh:      Ratio(9, 5),
+	theory.MajorSeventh:      Ratio(15, 8),
+}
+
+// ChordNote is a chord key retuned to a just ratio from the chord root.
+type ChordNote struct {
+	Key int
+	// Interval is the chord tone the key plays, reduced to an octave.
+	Interval theory.Interval
+	// Ratio is the just ratio between the root and the key, within an octave.
+	Ratio Pitch
+	// Cents is the offset to apply to the equal tempered key.
+	Cents float64
+}
+
+// JustChord retunes the keys of the chord to pure ratios relative to the
+// root of its definition (or to its lowest key if the chord isn't
+// identified). The root is kept at its equal tempered pitch and the notes are
+// returned in the order of the chord keys.
+func JustChord(c *theory.Chord, limit Limit) []ChordNote {
+	if c == nil || len(c.Keys) == 0 {
+		return nil
+	}
+	def := c.Copy().Def()
+	root, ok := midi.NotesToInt[strings.ToUpper(def.Root)]
+	if !ok {
+		// SortedByKeys sorts by pitch class, not by pitch
+		root = c.Keys[0]
+		for _, k := range c.Keys {
+			if k < root {
+				root = k
+			}
+		}
+	}
+	var offsets []int
+	var offset int
+	for _, hs := range def.HalfSteps {
+		offset += int(hs)
+		offsets = append(offsets, offset%12)
+	}
+	dominant := includes(offsets, 4) && includes(offsets, 10)
+
+	notes := make([]ChordNote, len(c.Keys))
+	for i, k := range c.Keys {
+		hs := ((k-root)%12 + 12) % 12
+		iv := theory.ChordToneInterval(hs)
+		iv.Steps %= 7
+		if def.Abbrev == "tri" && hs == 9 {
+			iv = theory.DiminishedSeventh
+		}
+		ratio := fiveLimitRatios[iv]
+		if limit >= SevenLimit {
+			switch {
+			case iv == theory.MinorSeventh && dominant:
+				ratio = Ratio(7, 4)
+			case iv == theory.DiminishedFifth:
+				ratio = Ratio(7, 5)
+			case iv == theory.DiminishedSeventh:
+				ratio = Ratio(12, 7)
+			}
+		}
+		notes[i] = ChordNote{
+			Key:      k,
+			Interval: iv,
+			Ratio:    ratio,
+			Cents:    ratio.InCents() - float64(hs*100),
+		}
+	}
+	return notes
+}
+
+// PitchBend converts a cents offset to a 14 bit MIDI pitch bend value (8192
+// being the center) for a synth using the passed pitch bend range in half
+// steps. Offsets beyond the range are clamped.
+func PitchBend(cents, bendRange float64) int {
+	bend := 8192 + int(math.Floor(cents/(bendRange*100)*8192+0.5))
+	switch {
+	case bend < 0:
+		return 0
+	case bend > 16383:
+		return 16383
+	}
+	return bend
+}
+
+// MPEZone describes the channels of a MIDI Polyphonic Expression zone. Each
+// note is played on its own member channel so it can be bent independently.
+type MPEZone struct {
+	MasterChannel  int
+	MemberChannels []int
+	// BendRange is the pitch bend range of the member channels in half steps,
+	// MPE defaults to 48.
+	BendRange float64
+}
+
+// LowerZone returns the MPE lower zone using channel 0 (channel 1 in user
+// facing numbering) as the master channel and the following channels as
+// members.
+func LowerZone(members int) MPEZone {
+	z := MPEZone{MasterChannel: 0, BendRange: 48}
+	for ch := 1; ch <= members && ch < 16; ch++ {
+		z.MemberChannels = append(z.MemberChannels, ch)
+	}
+	return z
+}
+
+// MPENote is a retuned chord note assigned to a channel of an MPE zone.
+type MPENote struct {
+	ChordNote
+	Channel int
+	// Bend is the pitch bend value to send on the channel before the note.
+	Bend int
+}
+
+// Allocate assigns each note to a member channel of the zone. When there are
+// more notes than channels, notes needing the same pitch bend share a
+// channel, others reuse the channels in order.
+func (z MPEZone) Allocate(notes []ChordNote) []MPENote {
+	if len(z.MemberChannels) == 0 {
+		return nil
+	}
+	out := make([]MPENote, len(notes))
+	for i, n := range notes {
+		out[i] = MPENote{ChordNote: n, Bend: PitchBend(n.Cents, z.BendRange)}
+		if i < len(z.MemberChannels) {
+			out[i].Channel = z.MemberChannels[i]
+			continue
+		}
+		out[i].Channel = z.MemberChannels[i%len(z.MemberChannels)]
+		for _, prev := range out[:i] {
+			if prev.Bend == out[i].Bend {
+				out[i].Channel = prev.Channel
+				break
+			}
+		}
+	}
+	return out
+}
+
+// Events returns the pitch bend and note on events playing the note.
+func (n MPENote) Events(velocity int) []*midi.Event {
+	return []*midi.Event{
+		midi.PitchWheelChange(n.Channel, 0, n.Bend),
+		midi.NoteOn(n.Channel, n.Key, velocity),
+	}
+}
+
+func includes(ints []int, v int) bool {
+	for _, i := range ints {
+		if i == v {
+			return true
+		}
+	}
+	return false
+}
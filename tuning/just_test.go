@@ -0,0 +1,98 @@
+package tuning
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-audio/midi"
+	"github.com/go-audio/music/theory"
+)
+
+func TestJustChord(t *testing.T) {
+	type note struct {
+		ratio Pitch
+		cents float64
+	}
+	tests := []struct {
+		name  string
+		chord *theory.Chord
+		limit Limit
+		want  []note
+	}{
+		{"C major", theory.NewChordFromAbbrev("Cmaj").Transpose(36), FiveLimit,
+			[]note{{Ratio(1, 1), 0}, {Ratio(5, 4), -13.6863}, {Ratio(3, 2), 1.955}}},
+		{"first inversion", &theory.Chord{Keys: []int{midi.KeyInt("E", 2), midi.KeyInt("G", 2), midi.KeyInt("C", 3)}}, FiveLimit,
+			[]note{{Ratio(5, 4), -13.6863}, {Ratio(3, 2), 1.955}, {Ratio(1, 1), 0}}},
+		{"A minor", theory.NewChordFromAbbrev("Amin").Transpose(36), FiveLimit,
+			[]note{{Ratio(1, 1), 0}, {Ratio(6, 5), 15.6413}, {Ratio(3, 2), 1.955}}},
+		{"5-limit C7", theory.NewChordFromAbbrev("C7").Transpose(36), FiveLimit,
+			[]note{{Ratio(1, 1), 0}, {Ratio(5, 4), -13.6863}, {Ratio(3, 2), 1.955}, {Ratio(9, 5), 17.5963}}},
+		{"7-limit C7", theory.NewChordFromAbbrev("C7").Transpose(36), SevenLimit,
+			[]note{{Ratio(1, 1), 0}, {Ratio(5, 4), -13.6863}, {Ratio(3, 2), 1.955}, {Ratio(7, 4), -31.1741}}},
+		{"7-limit Cm7", theory.NewChordFromAbbrev("Cm7").Transpose(36), SevenLimit,
+			[]note{{Ratio(1, 1), 0}, {Ratio(6, 5), 15.6413}, {Ratio(3, 2), 1.955}, {Ratio(9, 5), 17.5963}}},
+		{"7-limit diminished", theory.NewChordFromAbbrev("Cmb5").Transpose(36), SevenLimit,
+			[]note{{Ratio(1, 1), 0}, {Ratio(6, 5), 15.6413}, {Ratio(7, 5), -17.4878}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := JustChord(tt.chord, tt.limit)
+			if len(got) != len(tt.want) {
+				t.Fatalf("expected %d notes, got %d", len(tt.want), len(got))
+			}
+			for i, w := range tt.want {
+				if got[i].Key != tt.chord.Keys[i] || got[i].Ratio != w.ratio || math.Abs(got[i].Cents-w.cents) > 1e-3 {
+					t.Errorf("note %d: expected %s %+f cents, got %+v", i, w.ratio, w.cents, got[i])
+				}
+			}
+		})
+	}
+}
+
+func TestPitchBend(t *testing.T) {
+	tests := []struct {
+		cents, bendRange float64
+		want             int
+	}{
+		{0, 2, 8192},
+		{100, 2, 12288},
+		{-200, 2, 0},
+		{300, 2, 16383},
+		{-13.6863, 48, 8169},
+	}
+	for _, tt := range tests {
+		if got := PitchBend(tt.cents, tt.bendRange); got != tt.want {
+			t.Errorf("PitchBend(%f, %f) = %d, want %d", tt.cents, tt.bendRange, got, tt.want)
+		}
+	}
+}
+
+func TestMPEZone_Allocate(t *testing.T) {
+	c := &theory.Chord{Keys: []int{48, 52, 55, 60, 64}}
+	zone := LowerZone(3)
+	notes := zone.Allocate(JustChord(c, FiveLimit))
+	// the upper C and E share the bends of the lower ones
+	wantChannels := []int{1, 2, 3, 1, 2}
+	for i, n := range notes {
+		if n.Channel != wantChannels[i] {
+			t.Errorf("note %d: expected channel %d, got %d", i, wantChannels[i], n.Channel)
+		}
+	}
+	if notes[1].Bend != 8169 {
+		t.Errorf("expected the third to be bent to 8169, got %d", notes[1].Bend)
+	}
+	events := notes[1].Events(100)
+	if len(events) != 2 || events[0].AbsPitchBend != 8169 || events[0].MsgChan != 2 ||
+		events[1].Note != 52 || events[1].Velocity != 100 || events[1].MsgChan != 2 {
+		t.Errorf("unexpected events %v", events)
+	}
+}
+
+func TestJustChord_unknown(t *testing.T) {
+	// a bare major third isn't identified, the lowest key is used as root
+	c := &theory.Chord{Keys: []int{midi.KeyInt("C", 3), midi.KeyInt("A", 2)}}
+	got := JustChord(c, FiveLimit)
+	if got[0].Ratio != Ratio(6, 5) || got[1].Ratio != Ratio(1, 1) {
+		t.Errorf("expected C to be a minor third above A, got %+v", got)
+	}
+}
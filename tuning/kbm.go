@@ -0,0 +1,141 @@
+package tuning
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidKBM is returned when reading a malformed Scala keyboard mapping
+// file.
+var ErrInvalidKBM = errors.New("invalid Scala keyboard mapping file")
+
+// Mapping maps MIDI keys to scale degrees, as described by Scala .kbm files.
+type Mapping struct {
+	// Size is the number of keys after which the mapping repeats, 0 means
+	// each consecutive key is mapped to the next scale degree.
+	Size int
+	// FirstKey and LastKey are the range of keys to tune.
+	FirstKey, LastKey int
+	// MiddleKey is the key mapped to the first entry of the mapping.
+	MiddleKey int
+	// ReferenceKey is the key tuned to ReferenceFreq.
+	ReferenceKey  int
+	ReferenceFreq float64
+	// OctaveDegree is the scale degree reached each time the mapping repeats,
+	// 0 means the size of the scale.
+	OctaveDegree int
+	// Keys are the scale degrees of the keys in the mapping, -1 for keys that
+	// aren't mapped.
+	Keys []int
+}
+
+// LinearMapping returns a mapping assigning consecutive scale degrees to
+// consecutive keys, the tonic being on the middle key.
+func LinearMapping(middleKey, referenceKey int, referenceFreq float64) *Mapping {
+	return &Mapping{
+		FirstKey:      0,
+		LastKey:       127,
+		MiddleKey:     middleKey,
+		ReferenceKey:  referenceKey,
+		ReferenceFreq: referenceFreq,
+	}
+}
+
+// Degree returns the scale degree of the key for a scale of the passed size,
+// false is returned if the key isn't mapped.
+func (m *Mapping) Degree(key, scaleSize int) (int, bool) {
+	if key < m.FirstKey || key > m.LastKey {
+		return 0, false
+	}
+	offset := key - m.MiddleKey
+	if m.Size <= 0 {
+		return offset, true
+	}
+	rep := offset / m.Size
+	if offset%m.Size < 0 {
+		rep--
+	}
+	idx := offset - rep*m.Size
+	if idx >= len(m.Keys) || m.Keys[idx] < 0 {
+		return 0, false
+	}
+	octave := m.OctaveDegree
+	if octave == 0 {
+		octave = scaleSize
+	}
+	return m.Keys[idx] + rep*octave, true
+}
+
+// ReadKBM reads a keyboard mapping from a Scala .kbm file.
+func ReadKBM(r io.Reader) (*Mapping, error) {
+	lines, err := scalaLines(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(lines) < 7 {
+		return nil, fmt.Errorf("%s - missing header values", ErrInvalidKBM)
+	}
+	header := make([]int, 7)
+	m := &Mapping{}
+	for i, l := range lines[:7] {
+		v := firstField(l.text)
+		if i == 5 {
+			m.ReferenceFreq, err = strconv.ParseFloat(v, 64)
+		} else {
+			header[i], err = strconv.Atoi(v)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s - line %d: invalid value %q", ErrInvalidKBM, l.number, l.text)
+		}
+	}
+	m.Size, m.FirstKey, m.LastKey = header[0], header[1], header[2]
+	m.MiddleKey, m.ReferenceKey, m.OctaveDegree = header[3], header[4], header[6]
+	if m.Size < 0 {
+		return nil, fmt.Errorf("%s - invalid size %d", ErrInvalidKBM, m.Size)
+	}
+	// the mapping may omit trailing entries, they are then unmapped
+	for _, l := range lines[7:] {
+		if len(m.Keys) == m.Size {
+			break
+		}
+		v := firstField(l.text)
+		if v == "" {
+			continue
+		}
+		if strings.EqualFold(v, "x") {
+			m.Keys = append(m.Keys, -1)
+			continue
+		}
+		degree, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("%s - line %d: invalid degree %q", ErrInvalidKBM, l.number, l.text)
+		}
+		m.Keys = append(m.Keys, degree)
+	}
+	return m, nil
+}
+
+// WriteKBM writes the mapping using the Scala .kbm format.
+func (m *Mapping) WriteKBM(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "! Size of map:\n%d\n", m.Size)
+	fmt.Fprintf(bw, "! First MIDI note number to retune:\n%d\n", m.FirstKey)
+	fmt.Fprintf(bw, "! Last MIDI note number to retune:\n%d\n", m.LastKey)
+	fmt.Fprintf(bw, "! Middle note where the first entry of the mapping is mapped to:\n%d\n", m.MiddleKey)
+	fmt.Fprintf(bw, "! Reference note for which frequency is given:\n%d\n", m.ReferenceKey)
+	fmt.Fprintf(bw, "! Frequency to tune the above note to:\n%s\n", strconv.FormatFloat(m.ReferenceFreq, 'f', 6, 64))
+	fmt.Fprintf(bw, "! Scale degree to consider as formal octave:\n%d\n", m.OctaveDegree)
+	fmt.Fprintln(bw, "! Mapping.")
+	for i := 0; i < m.Size; i++ {
+		if i >= len(m.Keys) || m.Keys[i] < 0 {
+			fmt.Fprintln(bw, "x")
+			continue
+		}
+		fmt.Fprintln(bw, m.Keys[i])
+	}
+	return bw.Flush()
+}
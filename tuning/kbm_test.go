@@ -0,0 +1,79 @@
+package tuning
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const whiteKeysKBM = `! white keys mapped to a 7 notes scale
+7
+0
+127
+60
+69
+440.0
+7
+! Mapping.
+0
+x
+1
+x
+2
+3
+x
+`
+
+func TestReadKBM(t *testing.T) {
+	m, err := ReadKBM(strings.NewReader(whiteKeysKBM))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &Mapping{
+		Size: 7, FirstKey: 0, LastKey: 127, MiddleKey: 60,
+		ReferenceKey: 69, ReferenceFreq: 440, OctaveDegree: 7,
+		Keys: []int{0, -1, 1, -1, 2, 3, -1},
+	}
+	if !reflect.DeepEqual(m, want) {
+		t.Fatalf("expected %+v, got %+v", want, m)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := m.WriteKBM(buf); err != nil {
+		t.Fatal(err)
+	}
+	again, err := ReadKBM(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(again, m) {
+		t.Errorf("expected %+v, got %+v", m, again)
+	}
+}
+
+func TestMapping_Degree(t *testing.T) {
+	m := &Mapping{Size: 3, FirstKey: 10, LastKey: 100, MiddleKey: 60, Keys: []int{0, -1}}
+	tests := []struct {
+		key    int
+		degree int
+		ok     bool
+	}{
+		{60, 0, true},
+		{61, 0, false},
+		// missing entry
+		{62, 0, false},
+		{63, 12, true},
+		{57, -12, true},
+		{9, 0, false},
+	}
+	for _, tt := range tests {
+		degree, ok := m.Degree(tt.key, 12)
+		if degree != tt.degree || ok != tt.ok {
+			t.Errorf("Degree(%d) = %d %t, want %d %t", tt.key, degree, ok, tt.degree, tt.ok)
+		}
+	}
+	if _, err := ReadKBM(strings.NewReader("12\n0\n127\n60\n69\nfast\n12\n")); err == nil {
+		t.Error("expected an error for an invalid frequency")
+	}
+}
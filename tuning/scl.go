@@ -0,0 +1,115 @@
+package tuning
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidSCL is returned when reading a malformed Scala scale file.
+var ErrInvalidSCL = errors.New("invalid Scala scale file")
+
+// ErrInvalidPitch is returned when parsing a pitch that isn't expressed in
+// cents or as a ratio.
+var ErrInvalidPitch = errors.New("invalid pitch")
+
+// ParsePitch parses a pitch written using the Scala notation: values
+// containing a period are in cents (386.3137), others are ratios (5/4 or 2).
+// Anything after the first space is ignored.
+func ParsePitch(s string) (Pitch, error) {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return Pitch{}, fmt.Errorf("%s - %q", ErrInvalidPitch, s)
+	}
+	v := fields[0]
+	if strings.Contains(v, ".") {
+		cents, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return Pitch{}, fmt.Errorf("%s - %q", ErrInvalidPitch, s)
+		}
+		return Cents(cents), nil
+	}
+	num, den := v, "1"
+	if i := strings.IndexByte(v, '/'); i >= 0 {
+		num, den = v[:i], v[i+1:]
+	}
+	n, err := strconv.ParseInt(num, 10, 64)
+	if err != nil || n <= 0 {
+		return Pitch{}, fmt.Errorf("%s - %q", ErrInvalidPitch, s)
+	}
+	d, err := strconv.ParseInt(den, 10, 64)
+	if err != nil || d <= 0 {
+		return Pitch{}, fmt.Errorf("%s - %q", ErrInvalidPitch, s)
+	}
+	return Ratio(n, d), nil
+}
+
+// ReadSCL reads a scale from a Scala .scl file.
+func ReadSCL(r io.Reader) (*Scale, error) {
+	lines, err := scalaLines(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(lines) < 2 {
+		return nil, ErrInvalidSCL
+	}
+	s := &Scale{Description: strings.TrimSpace(lines[0].text)}
+	count, err := strconv.Atoi(firstField(lines[1].text))
+	if err != nil || count < 0 {
+		return nil, fmt.Errorf("%s - line %d: invalid number of notes %q", ErrInvalidSCL, lines[1].number, lines[1].text)
+	}
+	if len(lines)-2 < count {
+		return nil, fmt.Errorf("%s - expected %d notes, got %d", ErrInvalidSCL, count, len(lines)-2)
+	}
+	for _, l := range lines[2 : 2+count] {
+		p, err := ParsePitch(l.text)
+		if err != nil {
+			return nil, fmt.Errorf("%s - line %d: %s", ErrInvalidSCL, l.number, err)
+		}
+		s.Pitches = append(s.Pitches, p)
+	}
+	return s, nil
+}
+
+// WriteSCL writes the scale using the Scala .scl format.
+func (s *Scale) WriteSCL(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "!\n%s\n %d\n!\n", s.Description, len(s.Pitches))
+	for _, p := range s.Pitches {
+		fmt.Fprintf(bw, " %s\n", p)
+	}
+	return bw.Flush()
+}
+
+// scalaLine is a line of a Scala file and its line number.
+type scalaLine struct {
+	number int
+	text   string
+}
+
+// scalaLines returns the lines of a Scala file that aren't comments.
+func scalaLines(r io.Reader) ([]scalaLine, error) {
+	var lines []scalaLine
+	scanner := bufio.NewScanner(r)
+	var n int
+	for scanner.Scan() {
+		n++
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if strings.HasPrefix(text, "!") {
+			continue
+		}
+		lines = append(lines, scalaLine{number: n, text: text})
+	}
+	return lines, scanner.Err()
+}
+
+func firstField(s string) string {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
+}
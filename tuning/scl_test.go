@@ -0,0 +1,81 @@
+package tuning
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const meantoneSCL = `! meanquar.scl
+!
+1/4-comma meantone scale. Pietro Aaron's temp. (1523). 6/5 beats twice 3/2
+ 12
+!
+ 76.04900
+ 193.15686
+ 310.26471
+ 5/4
+ 503.42157
+ 579.47057
+ 696.57843
+ 25/16
+ 889.73529
+ 1006.84314
+ 1082.89214
+ 2/1
+`
+
+func TestReadSCL(t *testing.T) {
+	s, err := ReadSCL(strings.NewReader(meantoneSCL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Description != "1/4-comma meantone scale. Pietro Aaron's temp. (1523). 6/5 beats twice 3/2" {
+		t.Errorf("unexpected description %q", s.Description)
+	}
+	if s.Size() != 12 {
+		t.Fatalf("expected 12 notes, got %d", s.Size())
+	}
+	if got := s.Pitches[3]; got != Ratio(5, 4) {
+		t.Errorf("expected 5/4, got %s", got)
+	}
+	if got := s.Pitches[0]; got != Cents(76.049) {
+		t.Errorf("expected 76.049 cents, got %s", got)
+	}
+	if got := s.Period(); got != Ratio(2, 1) {
+		t.Errorf("expected an octave period, got %s", got)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := s.WriteSCL(buf); err != nil {
+		t.Fatal(err)
+	}
+	again, err := ReadSCL(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(again, s) {
+		t.Errorf("expected %v, got %v", s, again)
+	}
+}
+
+func TestReadSCL_errors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"empty", ""},
+		{"bad count", "desc\nmany\n"},
+		{"missing notes", "desc\n 3\n 100.0\n 2/1\n"},
+		{"bad pitch", "desc\n 2\n 100.0\n 2/0\n"},
+		{"negative ratio", "desc\n 1\n -3/2\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ReadSCL(strings.NewReader(tt.data)); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
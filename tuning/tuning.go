@@ -0,0 +1,178 @@
+// Package tuning describes microtonal scales and tunings, such as just
+// intonation, maqam or equal divisions of the octave other than 12, and maps
+// MIDI keys to frequencies. Scales and keyboard mappings can be read from and
+// written to Scala files (http://www.huygens-fokker.org/scala/scl_format.html).
+package tuning
+
+import (
+	"errors"
+	"math"
+	"strconv"
+
+	"github.com/go-audio/music/theory"
+)
+
+// ErrUnmappedKey is returned when getting the frequency of a key the keyboard
+// mapping doesn't use.
+var ErrUnmappedKey = errors.New("key not mapped to a scale degree")
+
+// Pitch is an interval from the tonic of a scale expressed either as a
+// frequency ratio (3/2) or in cents (701.955).
+type Pitch struct {
+	// Num and Den are the numerator and denominator of the ratio, Den is 0
+	// when the pitch is expressed in cents.
+	Num, Den int64
+	// Cents is the size of the interval when it isn't expressed as a ratio.
+	Cents float64
+}
+
+// Ratio returns a pitch expressed as a frequency ratio.
+func Ratio(num, den int64) Pitch {
+	return Pitch{Num: num, Den: den}
+}
+
+// Cents returns a pitch expressed in cents.
+func Cents(cents float64) Pitch {
+	return Pitch{Cents: cents}
+}
+
+// IsRatio reports whether the pitch is expressed as a frequency ratio.
+func (p Pitch) IsRatio() bool {
+	return p.Den != 0
+}
+
+// Ratio returns the frequency ratio of the interval.
+func (p Pitch) Ratio() float64 {
+	if p.IsRatio() {
+		return float64(p.Num) / float64(p.Den)
+	}
+	return math.Pow(2, p.Cents/theory.CentsPerOctave)
+}
+
+// InCents returns the size of the interval in cents.
+func (p Pitch) InCents() float64 {
+	if p.IsRatio() {
+		return theory.RatioCents(p.Ratio())
+	}
+	return p.Cents
+}
+
+// String returns the pitch using the Scala notation: 3/2 for ratios and cents
+// always including a period (700.0).
+func (p Pitch) String() string {
+	if p.IsRatio() {
+		return strconv.FormatInt(p.Num, 10) + "/" + strconv.FormatInt(p.Den, 10)
+	}
+	return strconv.FormatFloat(p.Cents, 'f', 5, 64)
+}
+
+// Scale is a scale made of arbitrary intervals. As in Scala files, the unison
+// is implied and the last pitch is the period after which the scale repeats,
+// usually the octave (2/1).
+type Scale struct {
+	Description string
+	Pitches     []Pitch
+}
+
+// Size returns the number of notes per period.
+func (s *Scale) Size() int {
+	return len(s.Pitches)
+}
+
+// Period returns the interval after which the scale repeats.
+func (s *Scale) Period() Pitch {
+	if len(s.Pitches) == 0 {
+		return Ratio(2, 1)
+	}
+	return s.Pitches[len(s.Pitches)-1]
+}
+
+// Cents returns the size in cents of each degree of the scale starting with
+// the unison (0) and excluding the period.
+func (s *Scale) Cents() []float64 {
+	cents := []float64{0}
+	for i := 0; i < len(s.Pitches)-1; i++ {
+		cents = append(cents, s.Pitches[i].InCents())
+	}
+	return cents
+}
+
+// DegreeCents returns the distance in cents between the tonic and the passed
+// scale degree, degrees outside of the first period are transposed by the
+// period (the degree -1 is the last note below the tonic).
+func (s *Scale) DegreeCents(degree int) float64 {
+	n := s.Size()
+	if n == 0 {
+		return float64(degree) * theory.CentsPerOctave
+	}
+	octave := degree / n
+	if degree%n < 0 {
+		octave--
+	}
+	return float64(octave)*s.Period().InCents() + s.Cents()[degree-octave*n]
+}
+
+// FromScaleDefinition converts one of the 12 tone equal temperament scale
+// definitions of the theory package to a scale expressed in cents.
+func FromScaleDefinition(def theory.ScaleDefinition) *Scale {
+	s := &Scale{Description: string(def.Name)}
+	var hs int
+	for _, step := range def.HalfSteps {
+		hs += step
+		s.Pitches = append(s.Pitches, Cents(float64(hs*100)))
+	}
+	s.Pitches = append(s.Pitches, Ratio(2, 1))
+	return s
+}
+
+// EqualTemperament returns the scale dividing the octave in the passed number
+// of equal steps (19 gives 19-EDO).
+func EqualTemperament(divisions int) *Scale {
+	s := &Scale{Description: strconv.Itoa(divisions) + " tone equal temperament"}
+	for i := 1; i < divisions; i++ {
+		s.Pitches = append(s.Pitches, Cents(float64(i)*theory.CentsPerOctave/float64(divisions)))
+	}
+	s.Pitches = append(s.Pitches, Ratio(2, 1))
+	return s
+}
+
+// Tuning maps MIDI keys to frequencies using a scale and a keyboard mapping.
+type Tuning struct {
+	Scale   *Scale
+	Mapping *Mapping
+}
+
+// New returns a tuning of the scale using the mapping. As in Scala, the
+// default mapping (used when m is nil) is linear with the tonic on middle C
+// and the key 69 tuned to 440Hz.
+func New(s *Scale, m *Mapping) *Tuning {
+	if m == nil {
+		m = LinearMapping(60, 69, float64(theory.A440))
+	}
+	return &Tuning{Scale: s, Mapping: m}
+}
+
+// Freq returns the frequency in Hz of the passed MIDI key. ErrUnmappedKey is
+// returned if the mapping doesn't use the key.
+func (t *Tuning) Freq(key int) (float64, error) {
+	degree, ok := t.Mapping.Degree(key, t.Scale.Size())
+	if !ok {
+		return 0, ErrUnmappedKey
+	}
+	refDegree, ok := t.Mapping.Degree(t.Mapping.ReferenceKey, t.Scale.Size())
+	if !ok {
+		return 0, ErrUnmappedKey
+	}
+	cents := t.Scale.DegreeCents(degree) - t.Scale.DegreeCents(refDegree)
+	return theory.ShiftCents(t.Mapping.ReferenceFreq, cents), nil
+}
+
+// Frequencies returns the frequency of the 128 MIDI keys, unmapped keys have
+// a frequency of 0.
+func (t *Tuning) Frequencies() []float64 {
+	freqs := make([]float64, 128)
+	for k := range freqs {
+		freqs[k], _ = t.Freq(k)
+	}
+	return freqs
+}
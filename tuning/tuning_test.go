@@ -0,0 +1,115 @@
+package tuning
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-audio/midi"
+	"github.com/go-audio/music/theory"
+)
+
+func TestFromScaleDefinition(t *testing.T) {
+	s := FromScaleDefinition(theory.ScaleDefMap[theory.MajorScale])
+	want := []float64{0, 200, 400, 500, 700, 900, 1100}
+	if got := s.Cents(); !floatsClose(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	if got := s.DegreeCents(-1); got != -100 {
+		t.Errorf("DegreeCents(-1) = %f, want -100", got)
+	}
+	if got := s.DegreeCents(9); got != 1600 {
+		t.Errorf("DegreeCents(9) = %f, want 1600", got)
+	}
+
+	// the chromatic scale tuned with the default mapping is the usual 12-TET
+	tuning := New(EqualTemperament(12), nil)
+	for _, key := range []int{0, 21, 60, 69, 108, 127} {
+		got, err := tuning.Freq(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := theory.A440.Freq(key); math.Abs(got-want) > 1e-9 {
+			t.Errorf("key %d: expected %f, got %f", key, want, got)
+		}
+	}
+}
+
+func TestTuning_Freq(t *testing.T) {
+	just := &Scale{Description: "5-limit just major", Pitches: []Pitch{
+		Ratio(9, 8), Ratio(5, 4), Ratio(4, 3), Ratio(3, 2), Ratio(5, 3), Ratio(15, 8), Ratio(2, 1),
+	}}
+	// white keys only, tonic on middle C tuned to 261.63Hz
+	whiteKeys := &Mapping{
+		Size: 12, LastKey: 127, MiddleKey: 60, ReferenceKey: 60, ReferenceFreq: 261.63,
+		Keys: []int{0, -1, 1, -1, 2, 3, -1, 4, -1, 5, -1, 6},
+	}
+	tests := []struct {
+		name    string
+		tuning  *Tuning
+		key     int
+		want    float64
+		wantErr error
+	}{
+		{"just tonic", New(just, whiteKeys), midi.KeyInt("C", 3), 261.63, nil},
+		{"just third", New(just, whiteKeys), midi.KeyInt("E", 3), 261.63 * 5 / 4, nil},
+		{"just sixth below", New(just, whiteKeys), midi.KeyInt("A", 2), 261.63 * 5 / 6, nil},
+		{"just upper fifth", New(just, whiteKeys), midi.KeyInt("G", 4), 261.63 * 3, nil},
+		{"black key", New(just, whiteKeys), midi.KeyInt("C#", 3), 0, ErrUnmappedKey},
+		{"19-EDO reference", New(EqualTemperament(19), nil), 69, 440, nil},
+		{"19-EDO step", New(EqualTemperament(19), nil), 70, 440 * math.Pow(2, 1.0/19), nil},
+		{"19-EDO octave", New(EqualTemperament(19), nil), 69 - 19, 220, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.tuning.Freq(tt.key)
+			if err != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("expected %f, got %f", tt.want, got)
+			}
+		})
+	}
+	if got := New(just, whiteKeys).Frequencies(); len(got) != 128 || got[61] != 0 || math.Abs(got[67]-261.63*1.5) > 1e-9 {
+		t.Errorf("unexpected frequencies %v", got)
+	}
+}
+
+func TestPitch(t *testing.T) {
+	tests := []struct {
+		pitch Pitch
+		str   string
+		cents float64
+	}{
+		{Ratio(3, 2), "3/2", 701.955},
+		{Ratio(2, 1), "2/1", 1200},
+		{Cents(386.3137), "386.31370", 386.3137},
+		{Cents(-50), "-50.00000", -50},
+	}
+	for _, tt := range tests {
+		t.Run(tt.str, func(t *testing.T) {
+			if got := tt.pitch.String(); got != tt.str {
+				t.Errorf("expected %s, got %s", tt.str, got)
+			}
+			if got := tt.pitch.InCents(); math.Abs(got-tt.cents) > 1e-3 {
+				t.Errorf("expected %f cents, got %f", tt.cents, got)
+			}
+			p, err := ParsePitch(tt.str)
+			if err != nil || p != tt.pitch {
+				t.Errorf("ParsePitch(%s) = %v, %v", tt.str, p, err)
+			}
+		})
+	}
+}
+
+func floatsClose(a, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if math.Abs(a[i]-b[i]) > 1e-9 {
+			return false
+		}
+	}
+	return true
+}